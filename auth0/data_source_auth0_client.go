@@ -37,7 +37,7 @@ func readDataClient(d *schema.ResourceData, m interface{}) error {
 		return errors.New("no 'client_id' or 'name' was specified")
 	}
 
-	api := m.(*management.Management)
+	api := m.(*config).api
 	clients, err := api.Client.List(management.IncludeFields("client_id", "name"))
 	if err != nil {
 		return err
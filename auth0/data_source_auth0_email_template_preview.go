@@ -0,0 +1,79 @@
+package auth0
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func newDataEmailTemplatePreview() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataEmailTemplatePreview,
+		Schema: map[string]*schema.Schema{
+			"template": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(liquidTemplates(), false),
+				Description:  "Name of the template, which defines the variables available to it",
+			},
+			"body": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLiquidWarnings(),
+				Description:  "Body of the template, with the Liquid syntax",
+			},
+			"subject": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateLiquidWarnings(),
+				Description:  "Subject of the template, with the Liquid syntax",
+			},
+			"variables": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "JSON object of variables overriding the sample data the template is rendered with",
+			},
+			"rendered_body": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Body of the template, rendered with the sample data",
+			},
+			"rendered_subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subject of the template, rendered with the sample data",
+			},
+		},
+	}
+}
+
+func readDataEmailTemplatePreview(d *schema.ResourceData, m interface{}) error {
+	template := d.Get("template").(string)
+
+	data := liquidTemplateSampleData()
+	if v, ok := d.GetOk("variables"); ok {
+		var variables map[string]interface{}
+		if err := json.Unmarshal([]byte(v.(string)), &variables); err != nil {
+			return err
+		}
+		data = mergeLiquidTemplateData(data, variables)
+	}
+
+	for _, key := range []string{"body", "subject"} {
+		t, err := parseLiquidTemplate(key, template, d.Get(key).(string))
+		if err != nil {
+			return err
+		}
+		rendered, err := t.Render(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		d.Set("rendered_"+key, rendered)
+	}
+
+	d.SetId(template)
+	return nil
+}
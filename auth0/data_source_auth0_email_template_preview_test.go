@@ -0,0 +1,54 @@
+package auth0
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+const testAccDataEmailTemplatePreviewConfig = `
+data auth0_email_template_preview verify_email {
+  template = "verify_email"
+  subject  = "Verify your {{ application.name }} account"
+  body     = <<-EOT
+    <p>Hi {{ user.given_name | default: user.email }},</p>
+    {% if user.user_metadata.plan == "pro" -%}
+    <p>Thanks for going pro!</p>
+    {%- endif %}
+    <a href="{{ url }}">Verify</a>
+  EOT
+  variables = jsonencode({
+    user = { given_name = "Ada", user_metadata = { plan = "pro" } }
+  })
+}
+`
+
+const testAccDataEmailTemplatePreviewInvalidConfig = `
+data auth0_email_template_preview welcome_email {
+  template = "welcome_email"
+  body     = "<p>Welcome {{ user.name }}</p>\n<a href=\"{{ url }}\">Start</a>"
+}
+`
+
+func TestAccDataEmailTemplatePreview(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataEmailTemplatePreviewConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.auth0_email_template_preview.verify_email", "rendered_subject", "Verify your My App account"),
+					resource.TestCheckResourceAttr("data.auth0_email_template_preview.verify_email", "rendered_body", "<p>Hi Ada,</p>\n<p>Thanks for going pro!</p>\n<a href=\"https://example.auth0.com/u/email-verification?ticket=sample#\">Verify</a>\n"),
+				),
+			},
+			{
+				Config:      testAccDataEmailTemplatePreviewInvalidConfig,
+				ExpectError: regexp.MustCompile(`body: line 2: unknown variable "url" for the "welcome_email" template`),
+			},
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package auth0
+
+import (
+	"io/ioutil"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func newDataSAMLMetadata() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSAMLMetadata,
+		Schema: map[string]*schema.Schema{
+			"file": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Path of the file holding the SAML metadata of the identity provider",
+			},
+			"metadata_xml": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Content of the metadata file",
+			},
+			"entity_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Entity ID of the identity provider",
+			},
+			"sign_in_endpoint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SAML single login URL of the identity provider",
+			},
+			"sign_out_endpoint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SAML single logout URL of the identity provider",
+			},
+			"protocol_binding": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Binding of the single login URL",
+			},
+			"signing_cert": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "X.509 signing certificate of the identity provider, PEM encoded",
+			},
+			"signing_cert_expires_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiry date of the signing certificate, in RFC 3339 format",
+			},
+			"signing_cert_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-1 fingerprint of the signing certificate, hex encoded",
+			},
+			"signing_cert_subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subject of the signing certificate",
+			},
+		},
+	}
+}
+
+func readDataSAMLMetadata(d *schema.ResourceData, m interface{}) error {
+	b, err := ioutil.ReadFile(d.Get("file").(string))
+	if err != nil {
+		return err
+	}
+	md, err := parseSAMLMetadata(string(b))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(md.EntityID)
+	d.Set("metadata_xml", string(b))
+	d.Set("entity_id", md.EntityID)
+	d.Set("sign_in_endpoint", md.SignInEndpoint)
+	d.Set("sign_out_endpoint", md.SignOutEndpoint)
+	d.Set("protocol_binding", md.ProtocolBinding)
+	d.Set("signing_cert", md.SigningCert)
+	for k, v := range flattenSigningCert(md.SigningCert) {
+		d.Set(k, v)
+	}
+	return nil
+}
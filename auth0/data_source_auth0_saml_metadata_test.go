@@ -0,0 +1,38 @@
+package auth0
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+const testAccDataSAMLMetadataConfig = `
+data auth0_saml_metadata okta {
+  file = "testdata/saml/okta.xml"
+}
+`
+
+func TestAccDataSAMLMetadata(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSAMLMetadataConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "entity_id", "http://www.okta.com/exk1a2b3c4d5e6f7g8h9"),
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "sign_in_endpoint", "https://example.okta.com/app/example_app/exk1a2b3c4d5e6f7g8h9/sso/saml/redirect"),
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "sign_out_endpoint", "https://example.okta.com/app/example_app/exk1a2b3c4d5e6f7g8h9/slo/saml"),
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "protocol_binding", "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"),
+					resource.TestCheckResourceAttrSet("data.auth0_saml_metadata.okta", "signing_cert"),
+					resource.TestCheckResourceAttrSet("data.auth0_saml_metadata.okta", "metadata_xml"),
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "signing_cert_expires_at", "2036-10-16T15:08:29Z"),
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "signing_cert_fingerprint", "9f6d4215a1f5790b6884fd9de72ed9abd2dae520"),
+					resource.TestCheckResourceAttr("data.auth0_saml_metadata.okta", "signing_cert_subject", "CN=idp.example.com,O=Example,C=US"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,73 @@
+package auth0
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func newDataUniversalLoginPreview() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataUniversalLoginPreview,
+		Schema: map[string]*schema.Schema{
+			"body": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLiquidWarnings(universalLoginHeadTag, universalLoginWidgetTag),
+				Description:  "Liquid template of the login pages",
+			},
+			"prompt": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "login",
+				Description: "Name of the prompt the template is rendered for, such as login or signup",
+			},
+			"locale": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "en",
+				Description: "Locale the template is rendered for",
+			},
+			"variables": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "JSON object of variables overriding the sample data the template is rendered with",
+			},
+			"rendered_body": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Template rendered with the sample data",
+			},
+		},
+	}
+}
+
+func readDataUniversalLoginPreview(d *schema.ResourceData, m interface{}) error {
+	prompt := d.Get("prompt").(string)
+	locale := d.Get("locale").(string)
+
+	data := universalLoginSampleData(prompt, locale)
+	if v, ok := d.GetOk("variables"); ok {
+		var variables map[string]interface{}
+		if err := json.Unmarshal([]byte(v.(string)), &variables); err != nil {
+			return err
+		}
+		data = mergeLiquidTemplateData(data, variables)
+	}
+
+	t, err := parseUniversalLoginTemplate("body", d.Get("body").(string))
+	if err != nil {
+		return err
+	}
+	rendered, err := t.RenderTags(data, universalLoginPreviewTags)
+	if err != nil {
+		return fmt.Errorf("body: %w", err)
+	}
+	d.Set("rendered_body", rendered)
+
+	d.SetId(prompt + ":" + locale)
+	return nil
+}
@@ -0,0 +1,57 @@
+package auth0
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+const testAccDataUniversalLoginPreviewConfig = `
+data auth0_universal_login_preview login {
+  body = <<-EOT
+    <html lang="{{ locale }}">
+    <head>{%- auth0:head -%}</head>
+    <body>
+    {%- if organization %}<h1>{{ organization.display_name }}</h1>{% endif -%}
+    {%- auth0:widget -%}
+    </body>
+    </html>
+  EOT
+}
+
+data auth0_universal_login_preview signup {
+  body      = data.auth0_universal_login_preview.login.body
+  prompt    = "signup"
+  locale    = "fr"
+  variables = jsonencode({ organization = null })
+}
+`
+
+const testAccDataUniversalLoginPreviewInvalidConfig = `
+data auth0_universal_login_preview login {
+  body = "<html><head></head><body>{%- auth0:widget -%}</body></html>"
+}
+`
+
+func TestAccDataUniversalLoginPreview(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataUniversalLoginPreviewConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.auth0_universal_login_preview.login", "rendered_body", "<html lang=\"en\">\n<head><!-- auth0:head --></head>\n<body><h1>Acme</h1><!-- auth0:widget --></body>\n</html>\n"),
+					resource.TestCheckResourceAttr("data.auth0_universal_login_preview.signup", "rendered_body", "<html lang=\"fr\">\n<head><!-- auth0:head --></head>\n<body><!-- auth0:widget --></body>\n</html>\n"),
+				),
+			},
+			{
+				Config:      testAccDataUniversalLoginPreviewInvalidConfig,
+				ExpectError: regexp.MustCompile(`body: the template must contain the {%- auth0:head -%} tag`),
+			},
+		},
+	})
+}
@@ -0,0 +1,210 @@
+package auth0
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/liquid"
+)
+
+// The templates of passwordless connections, which are Liquid as well when
+// their syntax is liquid. They are not email templates of the API, but are
+// checked and previewed the same way.
+const (
+	passwordlessEmailTemplate = "passwordless_email"
+	passwordlessSMSTemplate   = "passwordless_sms"
+)
+
+// emailTemplateCommonVariables are the variables available to every email
+// template. Paths ending with .* allow any property below them.
+var emailTemplateCommonVariables = []string{
+	"application.name",
+	"application.clientID",
+	"application.callback_domain",
+	"application.clientMetadata.*",
+	"connection.name",
+	"user.email",
+	"user.email_verified",
+	"user.picture",
+	"user.nickname",
+	"user.given_name",
+	"user.family_name",
+	"user.name",
+	"user.user_id",
+	"user.app_metadata.*",
+	"user.user_metadata.*",
+	"organization.id",
+	"organization.name",
+	"organization.display_name",
+	"organization.metadata.*",
+	"organization.branding.logo_url",
+	"organization.branding.colors.primary",
+	"organization.branding.colors.page_background",
+	"tenant",
+	"friendly_name",
+	"support_email",
+	"support_url",
+	"request_language",
+}
+
+// emailTemplateVariables are the variables available to each template, along
+// with the common ones.
+var emailTemplateVariables = map[string][]string{
+	"verify_email":         {"url"},
+	"verify_email_by_code": {"code"},
+	"reset_email":          {"url"},
+	"welcome_email":        {},
+	"blocked_account":      {"url"},
+	"stolen_credentials":   {"url"},
+	"enrollment_email":     {"link"},
+	"change_password":      {"url"},
+	"password_reset":       {"url"},
+	"mfa_oob_code":         {"code"},
+	"user_invitation":      {"url", "inviter.name"},
+}
+
+// passwordlessTemplateVariables are the variables available to the templates
+// of passwordless connections, which don't have the common ones.
+var passwordlessTemplateVariables = map[string][]string{
+	passwordlessEmailTemplate: {
+		"application.name", "connection.name", "code", "link", "email",
+		"operation", "send", "request_language",
+	},
+	passwordlessSMSTemplate: {
+		"application.name", "connection.name", "code", "phone_number",
+		"operation", "send", "request_language",
+	},
+}
+
+// liquidTemplates returns the names of the templates whose variables are
+// known, sorted.
+func liquidTemplates() []string {
+	var names []string
+	for name := range emailTemplateVariables {
+		names = append(names, name)
+	}
+	for name := range passwordlessTemplateVariables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// liquidTemplateVariables returns the variables available to a template.
+func liquidTemplateVariables(template string) []string {
+	if v, ok := passwordlessTemplateVariables[template]; ok {
+		return v
+	}
+	return append(append([]string{}, emailTemplateCommonVariables...), emailTemplateVariables[template]...)
+}
+
+// parseLiquidTemplate parses a Liquid template, checking that it only uses
+// the variables available to it. Errors are prefixed with key, the attribute
+// holding the template.
+func parseLiquidTemplate(key, template, src string) (*liquid.Template, error) {
+	t, err := liquid.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	var errs *multierror.Error
+	for _, v := range t.UnknownVariables(liquidTemplateVariables(template)) {
+		errs = multierror.Append(errs, fmt.Errorf("%s: line %d: unknown variable %q for the %q template", key, v.Line, v.Path, template))
+	}
+	return t, errs.ErrorOrNil()
+}
+
+// validateLiquidWarnings returns a validation function warning of the tags and
+// filters of a Liquid template which are not supported locally, allowing the
+// given custom tags. Auth0 may support them, so they are not errors, and they
+// are rendered as if they were absent in previews. Syntax errors are reported
+// by the CustomizeDiff of the resource, which knows the syntax and template.
+func validateLiquidWarnings(custom ...string) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, es []error) {
+		src, ok := v.(string)
+		if !ok {
+			return
+		}
+		t, err := liquid.ParseTags(src, custom...)
+		if err != nil {
+			return
+		}
+		for _, w := range t.Warnings() {
+			ws = append(ws, fmt.Sprintf("%s: %s, which is rendered as if it were absent in previews", k, w))
+		}
+		return
+	}
+}
+
+// liquidTemplateSampleData holds sample values of the variables available to
+// the templates, to preview them.
+func liquidTemplateSampleData() map[string]interface{} {
+	return map[string]interface{}{
+		"application": map[string]interface{}{
+			"name":            "My App",
+			"clientID":        "AaiyAPdpYdesoKnqjj8HJqRn4T5titww",
+			"callback_domain": "app.example.com",
+			"clientMetadata":  map[string]interface{}{},
+		},
+		"connection": map[string]interface{}{
+			"name": "Username-Password-Authentication",
+		},
+		"user": map[string]interface{}{
+			"email":          "jane.doe@example.com",
+			"email_verified": false,
+			"picture":        "https://example.com/jane.png",
+			"nickname":       "jane.doe",
+			"given_name":     "Jane",
+			"family_name":    "Doe",
+			"name":           "Jane Doe",
+			"user_id":        "auth0|5f7c8ec7c33c6c004bbafe82",
+			"app_metadata":   map[string]interface{}{},
+			"user_metadata":  map[string]interface{}{},
+		},
+		"organization": map[string]interface{}{
+			"id":           "org_Lv4xCzTMm3gMwFRl",
+			"name":         "acme",
+			"display_name": "Acme",
+			"metadata":     map[string]interface{}{},
+			"branding": map[string]interface{}{
+				"logo_url": "https://example.com/acme.png",
+				"colors": map[string]interface{}{
+					"primary":         "#0059d6",
+					"page_background": "#000000",
+				},
+			},
+		},
+		"inviter": map[string]interface{}{
+			"name": "John Doe",
+		},
+		"tenant":           "example",
+		"friendly_name":    "Example",
+		"support_email":    "support@example.com",
+		"support_url":      "https://example.com/support",
+		"request_language": "en",
+		"url":              "https://example.auth0.com/u/email-verification?ticket=sample#",
+		"link":             "https://example.auth0.com/passwordless/verify_redirect?verification_code=123456",
+		"code":             "123456",
+		"email":            "jane.doe@example.com",
+		"phone_number":     "+15555550100",
+		"operation":        "",
+		"send":             "code",
+	}
+}
+
+// mergeLiquidTemplateData merges variables into the sample data, recursively
+// for objects, so that a single property of a sample object can be set.
+func mergeLiquidTemplateData(data, variables map[string]interface{}) map[string]interface{} {
+	for k, v := range variables {
+		if vm, ok := v.(map[string]interface{}); ok {
+			if dm, ok := data[k].(map[string]interface{}); ok {
+				data[k] = mergeLiquidTemplateData(dm, vm)
+				continue
+			}
+		}
+		data[k] = v
+	}
+	return data
+}
@@ -0,0 +1,250 @@
+package auth0
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestParseLiquidTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		template string
+		src      string
+		expected []string
+	}{
+		{
+			name:     "VerifyEmail",
+			template: "verify_email",
+			src:      `<p>Hi {{ user.given_name | default: user.email }},</p><a href="{{ url }}">Verify {{ user.user_metadata.account }}</a>`,
+		},
+		{
+			name:     "UserInvitation",
+			template: "user_invitation",
+			src:      `{{ inviter.name }} invited you to {{ organization.display_name }}: {{ url }}`,
+		},
+		{
+			name:     "UnknownVariables",
+			template: "welcome_email",
+			src:      "Welcome {{ user.name }}\n<a href=\"{{ url }}\">{{ code }}</a>",
+			expected: []string{
+				`body: line 2: unknown variable "url" for the "welcome_email" template`,
+				`body: line 2: unknown variable "code" for the "welcome_email" template`,
+			},
+		},
+		{
+			name:     "SyntaxError",
+			template: "reset_email",
+			src:      "<p>\n{% if user.name %}\nHi\n{% endunless %}",
+			expected: []string{"body: line 4: unexpected endunless tag in if tag"},
+		},
+		{
+			name:     "PasswordlessSMS",
+			template: passwordlessSMSTemplate,
+			src:      "{{ code }} is your code for {{ application.name }} {{ link }}",
+			expected: []string{`body: line 1: unknown variable "link" for the "passwordless_sms" template`},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseLiquidTemplate("body", tt.template, tt.src)
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q, got none", tt.expected)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error %q, got %s", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateLiquidWarnings(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		src      string
+		custom   []string
+		expected []string
+	}{
+		{
+			name: "Supported",
+			src:  "{% if user.name %}{{ user.name | upcase }}{% endif %}",
+		},
+		{
+			name: "UnknownTagAndFilter",
+			src:  "{% form 'signup' %}\n{{ user.name | localize }}\n{% endform %}",
+			expected: []string{
+				`body: line 1: unknown tag "form", which is rendered as if it were absent in previews`,
+				`body: line 2: unknown filter "localize", which is rendered as if it were absent in previews`,
+			},
+		},
+		{
+			name:   "CustomTags",
+			src:    "{%- auth0:head -%}{%- auth0:widget -%}",
+			custom: []string{universalLoginHeadTag, universalLoginWidgetTag},
+		},
+		{
+			// Syntax errors are reported when planning.
+			name: "SyntaxError",
+			src:  "{% for %}{{ user.name | localize }}",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, es := validateLiquidWarnings(tt.custom...)(tt.src, "body")
+			if len(es) > 0 {
+				t.Errorf("expected no errors, got %v", es)
+			}
+			if !reflect.DeepEqual(tt.expected, ws) {
+				t.Errorf("expected warnings %q, got %q", tt.expected, ws)
+			}
+		})
+	}
+}
+
+func TestLiquidTemplateSampleData(t *testing.T) {
+	// Every variable of the templates must have sample data, so that
+	// previews are complete.
+	data := liquidTemplateSampleData()
+	for _, template := range liquidTemplates() {
+		for _, v := range liquidTemplateVariables(template) {
+			var value interface{} = data
+			for _, part := range strings.Split(strings.TrimSuffix(v, ".*"), ".") {
+				value = value.(map[string]interface{})[part]
+			}
+			if value == nil {
+				t.Errorf("expected sample data for %s of the %s template", v, template)
+			}
+		}
+	}
+}
+
+func TestMergeLiquidTemplateData(t *testing.T) {
+	data := mergeLiquidTemplateData(map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":  "Jane Doe",
+			"email": "jane.doe@example.com",
+		},
+		"code": "123456",
+	}, map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":          "John Doe",
+			"user_metadata": map[string]interface{}{"plan": "pro"},
+		},
+		"code": "654321",
+	})
+
+	expected := map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":          "John Doe",
+			"email":         "jane.doe@example.com",
+			"user_metadata": map[string]interface{}{"plan": "pro"},
+		},
+		"code": "654321",
+	}
+	if !reflect.DeepEqual(expected, data) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestValidateEmailTemplateWarnings(t *testing.T) {
+	ws, es := newEmailTemplate().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"template": "verify_email",
+		"body":     "{{ url | localize }}",
+		"from":     "support@example.com",
+		"subject":  "Verify your email",
+		"syntax":   "liquid",
+		"enabled":  true,
+	}))
+	if len(es) > 0 {
+		t.Errorf("expected no errors, got %v", es)
+	}
+	expected := []string{`body: line 1: unknown filter "localize", which is rendered as if it were absent in previews`}
+	if !reflect.DeepEqual(expected, ws) {
+		t.Errorf("expected warnings %q, got %q", expected, ws)
+	}
+}
+
+func TestValidateEmailTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		config   map[string]interface{}
+		expected []string
+	}{
+		{
+			name: "Liquid",
+			config: map[string]interface{}{
+				"template": "verify_email",
+				"body":     `<a href="{{ url }}">Verify</a>`,
+				"from":     "{{ friendly_name }} <support@example.com>",
+				"subject":  "Welcome to {{ application.name }}",
+				"syntax":   "liquid",
+				"enabled":  true,
+			},
+		},
+		{
+			name: "InvalidLiquid",
+			config: map[string]interface{}{
+				"template": "verify_email",
+				"body":     "<p>\n{% for %}\n</p>",
+				"from":     "support@example.com",
+				"subject":  "Your {{ code }}",
+				"syntax":   "liquid",
+				"enabled":  true,
+			},
+			expected: []string{
+				"body: line 2: expected a name, got end of markup",
+				`subject: line 1: unknown variable "code" for the "verify_email" template`,
+			},
+		},
+		{
+			// Tags and filters which are not supported locally are warnings
+			// rather than errors, as Auth0 may support them.
+			name: "UnknownTag",
+			config: map[string]interface{}{
+				"template": "verify_email",
+				"body":     `{% form %}<a href="{{ url | localize }}">Verify</a>{% endform %}`,
+				"from":     "support@example.com",
+				"subject":  "Verify your email",
+				"syntax":   "liquid",
+				"enabled":  true,
+			},
+		},
+		{
+			name: "Text",
+			config: map[string]interface{}{
+				"template": "verify_email",
+				"body":     "{% for %}",
+				"from":     "support@example.com",
+				"subject":  "Verify your email",
+				"syntax":   "text",
+				"enabled":  true,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newEmailTemplate().Diff(nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q, got none", tt.expected)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error %q, got %s", expected, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,617 @@
+package liquid
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type exprTokenKind int
+
+const (
+	identTok exprTokenKind = iota
+	stringTok
+	numberTok
+	operatorTok
+	punctTok
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	value string
+}
+
+// lexExpression splits the markup of an output or a tag into tokens.
+func lexExpression(s string, line int) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, errorf(line, "string %s is not closed", s[i:])
+			}
+			tokens = append(tokens, exprToken{stringTok, s[i+1 : i+1+end]})
+			i += end + 2
+		case c == '.' && i+1 < len(s) && s[i+1] == '.':
+			tokens = append(tokens, exprToken{punctTok, ".."})
+			i += 2
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' && !strings.HasPrefix(s[j:], "..")) {
+				j++
+			}
+			tokens = append(tokens, exprToken{numberTok, s[i:j]})
+			i = j
+		case isIdentStart(rune(c)):
+			j := i + 1
+			for j < len(s) && isIdentPart(rune(s[j])) {
+				j++
+			}
+			// A trailing question mark is part of the identifier.
+			if j < len(s) && s[j] == '?' {
+				j++
+			}
+			tokens = append(tokens, exprToken{identTok, s[i:j]})
+			i = j
+		case strings.ContainsRune("=!<>", rune(c)):
+			j := i + 1
+			if j < len(s) && (s[j] == '=' || c == '<' && s[j] == '>') {
+				j++
+			}
+			op := s[i:j]
+			if op == "=" || op == "!" {
+				return nil, errorf(line, "unexpected %q", op)
+			}
+			tokens = append(tokens, exprToken{operatorTok, op})
+			i = j
+		case strings.ContainsRune(".[]()|:,", rune(c)):
+			tokens = append(tokens, exprToken{punctTok, string(c)})
+			i++
+		default:
+			return nil, errorf(line, "unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '-'
+}
+
+// exprParser parses the expressions of the markup of an output or a tag.
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	line   int
+}
+
+func newExprParser(markup string, line int) (*exprParser, error) {
+	tokens, err := lexExpression(markup, line)
+	if err != nil {
+		return nil, err
+	}
+	return &exprParser{tokens: tokens, line: line}, nil
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *exprParser) peek() (exprToken, bool) {
+	if p.done() {
+		return exprToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept consumes the next token if it has the given kind and value.
+func (p *exprParser) accept(kind exprTokenKind, value string) bool {
+	if t, ok := p.peek(); ok && t.kind == kind && t.value == value {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(kind exprTokenKind, value string) error {
+	if !p.accept(kind, value) {
+		return p.unexpected(fmt.Sprintf("%q", value))
+	}
+	return nil
+}
+
+func (p *exprParser) expectEnd() error {
+	if !p.done() {
+		return p.unexpected("end of markup")
+	}
+	return nil
+}
+
+func (p *exprParser) ident() (string, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != identTok {
+		return "", p.unexpected("a name")
+	}
+	p.pos++
+	return t.value, nil
+}
+
+func (p *exprParser) unexpected(expected string) error {
+	t, ok := p.peek()
+	if !ok {
+		return errorf(p.line, "expected %s, got end of markup", expected)
+	}
+	return errorf(p.line, "expected %s, got %q", expected, t.value)
+}
+
+// expression is a value of a template: a literal, a variable or a range.
+type expression interface {
+	eval(c *context) interface{}
+}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(*context) interface{} {
+	return l.value
+}
+
+// emptyValue is the value of the empty and blank keywords, which are equal to
+// empty strings, arrays and maps.
+type emptyValue struct{}
+
+// accessor is a property or an index of a variable. Static accessors, such as
+// user.name or user["name"], have a name.
+type accessor struct {
+	name  string
+	index expression
+}
+
+type variable struct {
+	name string
+	path []accessor
+	line int
+}
+
+// staticPath returns the dotted path of the variable, up to its first dynamic
+// accessor.
+func (v *variable) staticPath() string {
+	parts := []string{v.name}
+	for _, a := range v.path {
+		if a.index != nil {
+			break
+		}
+		parts = append(parts, a.name)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (v *variable) eval(c *context) interface{} {
+	value := c.lookup(v.name)
+	for _, a := range v.path {
+		var key interface{} = a.name
+		if a.index != nil {
+			key = a.index.eval(c)
+		}
+		value = property(value, key)
+	}
+	return value
+}
+
+// property returns a property of a value, or nil if it has none.
+func property(value interface{}, key interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if k, ok := key.(string); ok {
+			if v, ok := mapIndex(rv, k); ok {
+				return v
+			}
+			if k == "size" {
+				return rv.Len()
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		switch k := key.(type) {
+		case string:
+			switch k {
+			case "size":
+				return rv.Len()
+			case "first":
+				if rv.Len() > 0 {
+					return rv.Index(0).Interface()
+				}
+			case "last":
+				if rv.Len() > 0 {
+					return rv.Index(rv.Len() - 1).Interface()
+				}
+			}
+		default:
+			if i, ok := toInt(k); ok {
+				if i < 0 {
+					i += rv.Len()
+				}
+				if i >= 0 && i < rv.Len() {
+					return rv.Index(i).Interface()
+				}
+			}
+		}
+	case reflect.String:
+		if key == "size" {
+			return len([]rune(rv.String()))
+		}
+	}
+	return nil
+}
+
+type rangeExpression struct {
+	from, to expression
+}
+
+func (r rangeExpression) eval(c *context) interface{} {
+	from, _ := toInt(r.from.eval(c))
+	to, _ := toInt(r.to.eval(c))
+	var values []interface{}
+	for i := from; i <= to; i++ {
+		values = append(values, i)
+	}
+	return values
+}
+
+// expression parses a literal, a variable or a range.
+func (p *exprParser) expression() (expression, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, p.unexpected("a value")
+	}
+	switch t.kind {
+	case stringTok:
+		p.pos++
+		return literal{t.value}, nil
+	case numberTok:
+		p.pos++
+		if strings.Contains(t.value, ".") {
+			f, err := strconv.ParseFloat(t.value, 64)
+			if err != nil {
+				return nil, errorf(p.line, "invalid number %q", t.value)
+			}
+			return literal{f}, nil
+		}
+		i, err := strconv.Atoi(t.value)
+		if err != nil {
+			return nil, errorf(p.line, "invalid number %q", t.value)
+		}
+		return literal{i}, nil
+	case punctTok:
+		if t.value != "(" {
+			break
+		}
+		p.pos++
+		from, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(punctTok, ".."); err != nil {
+			return nil, err
+		}
+		to, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(punctTok, ")"); err != nil {
+			return nil, err
+		}
+		return rangeExpression{from, to}, nil
+	case identTok:
+		p.pos++
+		switch t.value {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "nil", "null":
+			return literal{nil}, nil
+		case "empty", "blank":
+			return literal{emptyValue{}}, nil
+		}
+		return p.variable(t.value)
+	}
+	return nil, p.unexpected("a value")
+}
+
+func (p *exprParser) variable(name string) (*variable, error) {
+	v := &variable{name: name, line: p.line}
+	for {
+		switch {
+		case p.accept(punctTok, "."):
+			name, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			v.path = append(v.path, accessor{name: name})
+		case p.accept(punctTok, "["):
+			index, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(punctTok, "]"); err != nil {
+				return nil, err
+			}
+			if l, ok := index.(literal); ok {
+				if s, ok := l.value.(string); ok {
+					v.path = append(v.path, accessor{name: s})
+					continue
+				}
+			}
+			v.path = append(v.path, accessor{index: index})
+		default:
+			return v, nil
+		}
+	}
+}
+
+// filtered is an expression followed by filters, such as
+// {{ user.name | upcase }}.
+type filtered struct {
+	value   expression
+	filters []filterCall
+	line    int
+}
+
+// filterCall is a filter applied to a value. Its fn is nil if the filter is
+// unknown, in which case the value is left as it is.
+type filterCall struct {
+	name     string
+	fn       filterFunc
+	args     []expression
+	keywords []keywordArg
+}
+
+type keywordArg struct {
+	name  string
+	value expression
+}
+
+func (f *filtered) eval(c *context) (interface{}, error) {
+	v := f.value.eval(c)
+	for _, call := range f.filters {
+		if call.fn == nil {
+			continue
+		}
+		args := make([]interface{}, len(call.args))
+		for i, a := range call.args {
+			args[i] = a.eval(c)
+		}
+		if len(call.keywords) > 0 {
+			keywords := make(keywordArgs, len(call.keywords))
+			for _, k := range call.keywords {
+				keywords[k.name] = k.value.eval(c)
+			}
+			args = append(args, keywords)
+		}
+		var err error
+		if v, err = call.fn(v, args); err != nil {
+			return nil, errorf(f.line, "%s filter: %s", call.name, err)
+		}
+	}
+	return v, nil
+}
+
+// filtered parses an expression followed by filters.
+func (p *exprParser) filtered() (*filtered, error) {
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	f := &filtered{value: value, line: p.line}
+	for p.accept(punctTok, "|") {
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		call := filterCall{name: name, fn: filters[name]}
+		if p.accept(punctTok, ":") {
+			for {
+				if keyword, ok := p.keyword(); ok {
+					if call.fn != nil && !containsString(filterKeywords[name], keyword) {
+						return nil, errorf(p.line, "%s filter has no %s argument", name, keyword)
+					}
+					arg, err := p.expression()
+					if err != nil {
+						return nil, err
+					}
+					call.keywords = append(call.keywords, keywordArg{keyword, arg})
+				} else {
+					arg, err := p.expression()
+					if err != nil {
+						return nil, err
+					}
+					call.args = append(call.args, arg)
+				}
+				if !p.accept(punctTok, ",") {
+					break
+				}
+			}
+		}
+		f.filters = append(f.filters, call)
+	}
+	return f, nil
+}
+
+// keyword consumes the name of a keyword argument, such as allow_false: in
+// default: "none", allow_false: true.
+func (p *exprParser) keyword() (string, bool) {
+	if p.pos+1 >= len(p.tokens) {
+		return "", false
+	}
+	name, colon := p.tokens[p.pos], p.tokens[p.pos+1]
+	if name.kind != identTok || colon.kind != punctTok || colon.value != ":" {
+		return "", false
+	}
+	p.pos += 2
+	return name.value, true
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// condition is the condition of an if, elsif or unless tag.
+type condition interface {
+	test(c *context) bool
+}
+
+type comparison struct {
+	left     expression
+	operator string
+	right    expression
+}
+
+func (cmp comparison) test(c *context) bool {
+	left := cmp.left.eval(c)
+	if cmp.operator == "" {
+		return truthy(left)
+	}
+	right := cmp.right.eval(c)
+	switch cmp.operator {
+	case "==":
+		return equal(left, right)
+	case "!=", "<>":
+		return !equal(left, right)
+	case "contains":
+		return contains(left, right)
+	}
+	l, lok := toFloat(left)
+	r, rok := toFloat(right)
+	if !lok || !rok {
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if !lok || !rok {
+			return false
+		}
+		l, r = float64(strings.Compare(ls, rs)), 0
+	}
+	switch cmp.operator {
+	case "<":
+		return l < r
+	case ">":
+		return l > r
+	case "<=":
+		return l <= r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+type logical struct {
+	operator    string
+	left, right condition
+}
+
+func (l logical) test(c *context) bool {
+	if l.operator == "and" {
+		return l.left.test(c) && l.right.test(c)
+	}
+	return l.left.test(c) || l.right.test(c)
+}
+
+// condition parses comparisons joined by and and or. As in Liquid, they are
+// evaluated from right to left, without precedence.
+func (p *exprParser) condition() (condition, error) {
+	left, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	cmp := comparison{left: left}
+	if t, ok := p.peek(); ok && (t.kind == operatorTok || t.kind == identTok && t.value == "contains") {
+		p.pos++
+		cmp.operator = t.value
+		if cmp.right, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	for _, op := range []string{"and", "or"} {
+		if p.accept(identTok, op) {
+			right, err := p.condition()
+			if err != nil {
+				return nil, err
+			}
+			return logical{op, cmp, right}, nil
+		}
+	}
+	return cmp, nil
+}
+
+// truthy reports whether a value is true in a condition. Only false and nil
+// are false.
+func truthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return true
+}
+
+func isEmpty(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() == 0
+	}
+	return false
+}
+
+func equal(a, b interface{}) bool {
+	if _, ok := a.(emptyValue); ok {
+		return isEmpty(b)
+	}
+	if _, ok := b.(emptyValue); ok {
+		return isEmpty(a)
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func contains(container, v interface{}) bool {
+	if s, ok := container.(string); ok {
+		return strings.Contains(s, toString(v))
+	}
+	rv := reflect.ValueOf(container)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if equal(rv.Index(i).Interface(), v) {
+				return true
+			}
+		}
+	case reflect.Map:
+		if k, ok := v.(string); ok {
+			_, ok := mapIndex(rv, k)
+			return ok
+		}
+	}
+	return false
+}
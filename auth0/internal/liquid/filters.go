@@ -0,0 +1,669 @@
+package liquid
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// filterFunc applies a filter to a value, with the arguments of the filter.
+type filterFunc func(v interface{}, args []interface{}) (interface{}, error)
+
+// filters are the standard filters of Liquid.
+var filters = map[string]filterFunc{
+	"abs": numberFilter(math.Abs),
+	"append": stringFilter(1, func(s string, args []string) string {
+		return s + args[0]
+	}),
+	"at_least": numberArgFilter(math.Max),
+	"at_most":  numberArgFilter(math.Min),
+	"base64_decode": func(v interface{}, _ []interface{}) (interface{}, error) {
+		b, err := base64.StdEncoding.DecodeString(toString(v))
+		return string(b), err
+	},
+	"base64_encode": stringFilter(0, func(s string, _ []string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}),
+	"base64_url_safe_decode": func(v interface{}, _ []interface{}) (interface{}, error) {
+		b, err := base64.URLEncoding.DecodeString(toString(v))
+		return string(b), err
+	},
+	"base64_url_safe_encode": stringFilter(0, func(s string, _ []string) string {
+		return base64.URLEncoding.EncodeToString([]byte(s))
+	}),
+	"capitalize": stringFilter(0, func(s string, _ []string) string {
+		if s == "" {
+			return s
+		}
+		r, n := utf8.DecodeRuneInString(s)
+		return string(unicode.ToUpper(r)) + strings.ToLower(s[n:])
+	}),
+	"ceil": numberFilter(math.Ceil),
+	"compact": func(v interface{}, args []interface{}) (interface{}, error) {
+		key, err := propertyArg(args)
+		if err != nil {
+			return nil, err
+		}
+		var compacted []interface{}
+		for _, item := range toSlice(v) {
+			if key(item) != nil {
+				compacted = append(compacted, item)
+			}
+		}
+		return compacted, nil
+	},
+	"concat": func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		if !isArray(args[0]) {
+			return nil, fmt.Errorf("expected an array argument")
+		}
+		return append(append([]interface{}{}, toSlice(v)...), toSlice(args[0])...), nil
+	},
+	"date": filterDate,
+	"default": func(v interface{}, args []interface{}) (interface{}, error) {
+		args, keywords := splitKeywordArgs(args)
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		if v == false && truthy(keywords["allow_false"]) {
+			return v, nil
+		}
+		if !truthy(v) || isEmpty(v) {
+			return args[0], nil
+		}
+		return v, nil
+	},
+	"divided_by": arithmeticFilter(func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("divided by 0")
+		}
+		return a / b, nil
+	}),
+	"downcase": stringFilter(0, func(s string, _ []string) string {
+		return strings.ToLower(s)
+	}),
+	"escape": stringFilter(0, func(s string, _ []string) string {
+		return html.EscapeString(s)
+	}),
+	"escape_once": stringFilter(0, func(s string, _ []string) string {
+		return html.EscapeString(html.UnescapeString(s))
+	}),
+	"find": func(v interface{}, args []interface{}) (interface{}, error) {
+		match, err := matchArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range toSlice(v) {
+			if match(item) {
+				return item, nil
+			}
+		}
+		return nil, nil
+	},
+	"find_index": func(v interface{}, args []interface{}) (interface{}, error) {
+		match, err := matchArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range toSlice(v) {
+			if match(item) {
+				return i, nil
+			}
+		}
+		return nil, nil
+	},
+	"first": func(v interface{}, _ []interface{}) (interface{}, error) {
+		return property(v, "first"), nil
+	},
+	"floor": numberFilter(math.Floor),
+	"has": func(v interface{}, args []interface{}) (interface{}, error) {
+		match, err := matchArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range toSlice(v) {
+			if match(item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	},
+	"join": func(v interface{}, args []interface{}) (interface{}, error) {
+		separator := " "
+		if len(args) > 0 {
+			separator = toString(args[0])
+		}
+		var parts []string
+		for _, item := range toSlice(v) {
+			parts = append(parts, toString(item))
+		}
+		return strings.Join(parts, separator), nil
+	},
+	"last": func(v interface{}, _ []interface{}) (interface{}, error) {
+		return property(v, "last"), nil
+	},
+	"lstrip": stringFilter(0, func(s string, _ []string) string {
+		return strings.TrimLeftFunc(s, unicode.IsSpace)
+	}),
+	"map": func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		var mapped []interface{}
+		for _, item := range toSlice(v) {
+			mapped = append(mapped, property(item, toString(args[0])))
+		}
+		return mapped, nil
+	},
+	"minus": arithmeticFilter(func(a, b float64) (float64, error) {
+		return a - b, nil
+	}),
+	"modulo": arithmeticFilter(func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("divided by 0")
+		}
+		return math.Mod(a, b), nil
+	}),
+	"newline_to_br": stringFilter(0, func(s string, _ []string) string {
+		return strings.ReplaceAll(s, "\n", "<br />\n")
+	}),
+	"plus": arithmeticFilter(func(a, b float64) (float64, error) {
+		return a + b, nil
+	}),
+	"prepend": stringFilter(1, func(s string, args []string) string {
+		return args[0] + s
+	}),
+	"reject": func(v interface{}, args []interface{}) (interface{}, error) {
+		match, err := matchArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		var rejected []interface{}
+		for _, item := range toSlice(v) {
+			if !match(item) {
+				rejected = append(rejected, item)
+			}
+		}
+		return rejected, nil
+	},
+	"remove": stringFilter(1, func(s string, args []string) string {
+		return strings.ReplaceAll(s, args[0], "")
+	}),
+	"remove_first": stringFilter(1, func(s string, args []string) string {
+		return strings.Replace(s, args[0], "", 1)
+	}),
+	"remove_last": stringFilter(1, func(s string, args []string) string {
+		return replaceLast(s, args[0], "")
+	}),
+	"replace": stringFilter(2, func(s string, args []string) string {
+		return strings.ReplaceAll(s, args[0], args[1])
+	}),
+	"replace_first": stringFilter(2, func(s string, args []string) string {
+		return strings.Replace(s, args[0], args[1], 1)
+	}),
+	"replace_last": stringFilter(2, func(s string, args []string) string {
+		return replaceLast(s, args[0], args[1])
+	}),
+	"reverse": func(v interface{}, _ []interface{}) (interface{}, error) {
+		items := toSlice(v)
+		reversed := make([]interface{}, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		return reversed, nil
+	},
+	"round": func(v interface{}, args []interface{}) (interface{}, error) {
+		f, _ := toFloat(v)
+		digits := 0
+		if len(args) > 0 {
+			digits, _ = toInt(args[0])
+		}
+		if digits <= 0 {
+			return int(math.Round(f)), nil
+		}
+		p := math.Pow(10, float64(digits))
+		return math.Round(f*p) / p, nil
+	},
+	"rstrip": stringFilter(0, func(s string, _ []string) string {
+		return strings.TrimRightFunc(s, unicode.IsSpace)
+	}),
+	"size": func(v interface{}, _ []interface{}) (interface{}, error) {
+		if size := property(v, "size"); size != nil {
+			return size, nil
+		}
+		return 0, nil
+	},
+	"slice": func(v interface{}, args []interface{}) (interface{}, error) {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, fmt.Errorf("expected 1 or 2 arguments, got %d", len(args))
+		}
+		start, _ := toInt(args[0])
+		length := 1
+		if len(args) == 2 {
+			length, _ = toInt(args[1])
+		}
+		if s, ok := v.(string); ok {
+			runes := []rune(s)
+			from, to := sliceBounds(len(runes), start, length)
+			return string(runes[from:to]), nil
+		}
+		items := toSlice(v)
+		from, to := sliceBounds(len(items), start, length)
+		return items[from:to], nil
+	},
+	"sort": func(v interface{}, args []interface{}) (interface{}, error) {
+		key, err := propertyArg(args)
+		if err != nil {
+			return nil, err
+		}
+		items := append([]interface{}{}, toSlice(v)...)
+		sort.SliceStable(items, func(i, j int) bool {
+			a, b := key(items[i]), key(items[j])
+			if x, ok := toFloat(a); ok {
+				if y, ok := toFloat(b); ok {
+					return x < y
+				}
+			}
+			return toString(a) < toString(b)
+		})
+		return items, nil
+	},
+	"sort_natural": func(v interface{}, args []interface{}) (interface{}, error) {
+		key, err := propertyArg(args)
+		if err != nil {
+			return nil, err
+		}
+		items := append([]interface{}{}, toSlice(v)...)
+		sort.SliceStable(items, func(i, j int) bool {
+			return strings.ToLower(toString(key(items[i]))) < strings.ToLower(toString(key(items[j])))
+		})
+		return items, nil
+	},
+	"split": func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		var parts []interface{}
+		for _, part := range strings.Split(toString(v), toString(args[0])) {
+			parts = append(parts, part)
+		}
+		return parts, nil
+	},
+	"strip": stringFilter(0, func(s string, _ []string) string {
+		return strings.TrimSpace(s)
+	}),
+	"strip_html": stringFilter(0, func(s string, _ []string) string {
+		return htmlTagRegexp.ReplaceAllString(s, "")
+	}),
+	"strip_newlines": stringFilter(0, func(s string, _ []string) string {
+		return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+	}),
+	"sum": func(v interface{}, args []interface{}) (interface{}, error) {
+		key, err := propertyArg(args)
+		if err != nil {
+			return nil, err
+		}
+		var sum float64
+		ints := true
+		for _, item := range toSlice(v) {
+			value := key(item)
+			if f, ok := toFloat(value); ok {
+				sum += f
+				ints = ints && isInt(value)
+			}
+		}
+		if ints {
+			return int(sum), nil
+		}
+		return sum, nil
+	},
+	"times": arithmeticFilter(func(a, b float64) (float64, error) {
+		return a * b, nil
+	}),
+	"truncate": func(v interface{}, args []interface{}) (interface{}, error) {
+		length, ellipsis := 50, "..."
+		if len(args) > 0 {
+			length, _ = toInt(args[0])
+		}
+		if len(args) > 1 {
+			ellipsis = toString(args[1])
+		}
+		runes := []rune(toString(v))
+		if len(runes) <= length {
+			return string(runes), nil
+		}
+		n := length - len([]rune(ellipsis))
+		if n < 0 {
+			n = 0
+		}
+		return string(runes[:n]) + ellipsis, nil
+	},
+	"truncatewords": func(v interface{}, args []interface{}) (interface{}, error) {
+		length, ellipsis := 15, "..."
+		if len(args) > 0 {
+			length, _ = toInt(args[0])
+		}
+		if len(args) > 1 {
+			ellipsis = toString(args[1])
+		}
+		words := strings.Fields(toString(v))
+		if len(words) <= length {
+			return strings.Join(words, " "), nil
+		}
+		if length < 1 {
+			length = 1
+		}
+		return strings.Join(words[:length], " ") + ellipsis, nil
+	},
+	"uniq": func(v interface{}, args []interface{}) (interface{}, error) {
+		key, err := propertyArg(args)
+		if err != nil {
+			return nil, err
+		}
+		var unique, seen []interface{}
+		for _, item := range toSlice(v) {
+			if k := key(item); !contains(seen, k) {
+				seen = append(seen, k)
+				unique = append(unique, item)
+			}
+		}
+		return unique, nil
+	},
+	"upcase": stringFilter(0, func(s string, _ []string) string {
+		return strings.ToUpper(s)
+	}),
+	"url_decode": func(v interface{}, _ []interface{}) (interface{}, error) {
+		return url.QueryUnescape(toString(v))
+	},
+	"url_encode": stringFilter(0, func(s string, _ []string) string {
+		return url.QueryEscape(s)
+	}),
+	"where": func(v interface{}, args []interface{}) (interface{}, error) {
+		match, err := matchArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		var matched []interface{}
+		for _, item := range toSlice(v) {
+			if match(item) {
+				matched = append(matched, item)
+			}
+		}
+		return matched, nil
+	},
+}
+
+// filterKeywords are the keyword arguments filters accept, such as
+// allow_false in default: "none", allow_false: true.
+var filterKeywords = map[string][]string{
+	"default": {"allow_false"},
+}
+
+// keywordArgs are the keyword arguments of a filter, which are given to it
+// after the others.
+type keywordArgs map[string]interface{}
+
+// splitKeywordArgs splits the arguments of a filter accepting keyword
+// arguments from the others.
+func splitKeywordArgs(args []interface{}) ([]interface{}, keywordArgs) {
+	if n := len(args); n > 0 {
+		if keywords, ok := args[n-1].(keywordArgs); ok {
+			return args[:n-1], keywords
+		}
+	}
+	return args, nil
+}
+
+var htmlTagRegexp = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+
+func checkArgs(args []interface{}, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d arguments, got %d", n, len(args))
+	}
+	return nil
+}
+
+// propertyArg returns a function giving the value of the property named by
+// the optional argument of filters such as sort, or the value itself without
+// argument.
+func propertyArg(args []interface{}) (func(interface{}) interface{}, error) {
+	switch len(args) {
+	case 0:
+		return func(item interface{}) interface{} { return item }, nil
+	case 1:
+		name := toString(args[0])
+		return func(item interface{}) interface{} { return property(item, name) }, nil
+	}
+	return nil, fmt.Errorf("expected at most 1 argument, got %d", len(args))
+}
+
+// matchArgs returns a function reporting whether an item matches the
+// arguments of filters such as where: its property named by the first
+// argument equals the second one, or is truthy without a second argument.
+func matchArgs(args []interface{}) (func(interface{}) bool, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("expected 1 or 2 arguments, got %d", len(args))
+	}
+	name := toString(args[0])
+	if len(args) == 1 {
+		return func(item interface{}) bool { return truthy(property(item, name)) }, nil
+	}
+	return func(item interface{}) bool { return equal(property(item, name), args[1]) }, nil
+}
+
+func replaceLast(s, old, new string) string {
+	i := strings.LastIndex(s, old)
+	if i < 0 {
+		return s
+	}
+	return s[:i] + new + s[i+len(old):]
+}
+
+// stringFilter returns a filter of strings taking n string arguments.
+func stringFilter(n int, fn func(s string, args []string) string) filterFunc {
+	return func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, n); err != nil {
+			return nil, err
+		}
+		s := make([]string, n)
+		for i, a := range args {
+			s[i] = toString(a)
+		}
+		return fn(toString(v), s), nil
+	}
+}
+
+// numberFilter returns a filter of numbers, giving an integer.
+func numberFilter(fn func(float64) float64) filterFunc {
+	return func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, 0); err != nil {
+			return nil, err
+		}
+		f, _ := toFloat(v)
+		r := fn(f)
+		if r == math.Trunc(r) {
+			return int(r), nil
+		}
+		return r, nil
+	}
+}
+
+// numberArgFilter returns a filter of numbers taking a number argument, giving
+// one of them, such as at_least.
+func numberArgFilter(fn func(a, b float64) float64) filterFunc {
+	return func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		a, _ := toFloat(v)
+		b, _ := toFloat(args[0])
+		r := fn(a, b)
+		if r == math.Trunc(r) && (r == a && isInt(v) || r == b && isInt(args[0])) {
+			return int(r), nil
+		}
+		return r, nil
+	}
+}
+
+// arithmeticFilter returns a filter of numbers taking a number argument. As
+// in Liquid, the result is an integer when both numbers are integers.
+func arithmeticFilter(fn func(a, b float64) (float64, error)) filterFunc {
+	return func(v interface{}, args []interface{}) (interface{}, error) {
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		a, _ := toFloat(v)
+		b, _ := toFloat(args[0])
+		r, err := fn(a, b)
+		if err != nil {
+			return nil, err
+		}
+		if isInt(v) && isInt(args[0]) {
+			return int(math.Floor(r)), nil
+		}
+		return r, nil
+	}
+}
+
+func sliceBounds(n, start, length int) (int, int) {
+	if start < 0 {
+		start += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > n {
+		start = n
+	}
+	end := start + length
+	if end > n {
+		end = n
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dateLayouts are the formats of the dates read by the date filter.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// now is the time of "now" and "today" for the date filter.
+var now = time.Now
+
+// filterDate formats a date with a strftime format. Dates are times, RFC 3339
+// strings, Unix timestamps, or "now" and "today".
+func filterDate(v interface{}, args []interface{}) (interface{}, error) {
+	if err := checkArgs(args, 1); err != nil {
+		return nil, err
+	}
+	var t time.Time
+	switch s := toString(v); {
+	case v == nil || s == "":
+		return v, nil
+	case s == "now" || s == "today":
+		t = now()
+	default:
+		if tv, ok := v.(time.Time); ok {
+			t = tv
+			break
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			t = time.Unix(n, 0).UTC()
+			break
+		}
+		parsed := false
+		for _, layout := range dateLayouts {
+			if pt, err := time.Parse(layout, s); err == nil {
+				t, parsed = pt, true
+				break
+			}
+		}
+		if !parsed {
+			// Liquid outputs values which are not dates as they are.
+			return v, nil
+		}
+	}
+	return strftime(t, toString(args[0])), nil
+}
+
+// strftime formats a time with the common directives of strftime.
+func strftime(t time.Time, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch format[i] {
+		case 'a':
+			b.WriteString(t.Format("Mon"))
+		case 'A':
+			b.WriteString(t.Format("Monday"))
+		case 'b', 'h':
+			b.WriteString(t.Format("Jan"))
+		case 'B':
+			b.WriteString(t.Format("January"))
+		case 'd':
+			b.WriteString(t.Format("02"))
+		case 'e':
+			fmt.Fprintf(&b, "%2d", t.Day())
+		case 'H':
+			b.WriteString(t.Format("15"))
+		case 'I':
+			b.WriteString(t.Format("03"))
+		case 'j':
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		case 'm':
+			b.WriteString(t.Format("01"))
+		case 'M':
+			b.WriteString(t.Format("04"))
+		case 'p':
+			b.WriteString(t.Format("PM"))
+		case 'S':
+			b.WriteString(t.Format("05"))
+		case 's':
+			fmt.Fprintf(&b, "%d", t.Unix())
+		case 'y':
+			b.WriteString(t.Format("06"))
+		case 'Y':
+			b.WriteString(t.Format("2006"))
+		case 'z':
+			b.WriteString(t.Format("-0700"))
+		case 'Z':
+			b.WriteString(t.Format("MST"))
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
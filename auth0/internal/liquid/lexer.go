@@ -0,0 +1,118 @@
+package liquid
+
+import (
+	"regexp"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	outputToken
+	tagToken
+)
+
+// token is a piece of text, an output ({{ }}) or a tag ({% %}) of a template.
+// The value of outputs and tags is their markup, without delimiters.
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// tagName returns the name of a tag, and the markup following it.
+func (t token) tagName() (string, string) {
+	i := strings.IndexAny(t.value, whitespace)
+	if i < 0 {
+		return t.value, ""
+	}
+	return t.value[:i], strings.TrimSpace(t.value[i:])
+}
+
+var (
+	delimiterRegexp  = regexp.MustCompile(`{[{%]`)
+	endRawRegexp     = regexp.MustCompile(`{%-?\s*endraw\s*-?%}`)
+	endCommentRegexp = regexp.MustCompile(`{%-?\s*endcomment\s*-?%}`)
+)
+
+const whitespace = " \t\r\n"
+
+// lex splits a template into tokens. The whitespace around outputs and tags
+// whose delimiters hold a hyphen, such as {%- -%}, is trimmed. The content of
+// raw tags is kept as text, and the content of comment tags is dropped.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	trimNext := false
+
+	// text adds a text token, trimming its leading whitespace if the previous
+	// delimiter asked for it. Lines are counted over the trimmed text as well.
+	text := func(s string, trimRight bool) {
+		start := line
+		line += strings.Count(s, "\n")
+		if trimNext {
+			trimmed := strings.TrimLeft(s, whitespace)
+			start += strings.Count(s[:len(s)-len(trimmed)], "\n")
+			s = trimmed
+			trimNext = false
+		}
+		if trimRight {
+			s = strings.TrimRight(s, whitespace)
+		}
+		if s != "" {
+			tokens = append(tokens, token{kind: textToken, value: s, line: start})
+		}
+	}
+
+	for {
+		loc := delimiterRegexp.FindStringIndex(src)
+		if loc == nil {
+			text(src, false)
+			return tokens, nil
+		}
+
+		kind, closing, what := outputToken, "}}", "output"
+		if src[loc[0]+1] == '%' {
+			kind, closing, what = tagToken, "%}", "tag"
+		}
+		markup := src[loc[1]:]
+		text(src[:loc[0]], strings.HasPrefix(markup, "-"))
+
+		end := strings.Index(markup, closing)
+		if end < 0 {
+			return nil, errorf(line, "%s is not closed with %q", what, closing)
+		}
+		src = markup[end+len(closing):]
+		markup = strings.TrimPrefix(markup[:end], "-")
+		if strings.HasSuffix(markup, "-") {
+			trimNext = true
+			markup = markup[:len(markup)-1]
+		}
+		t := token{kind: kind, value: strings.TrimSpace(markup), line: line}
+		line += strings.Count(markup, "\n")
+
+		if name, _ := t.tagName(); kind == tagToken && (name == "raw" || name == "comment") {
+			re := endRawRegexp
+			if name == "comment" {
+				re = endCommentRegexp
+			}
+			loc := re.FindStringIndex(src)
+			if loc == nil {
+				return nil, errorf(t.line, "%s tag was never closed", name)
+			}
+			content, endTag := src[:loc[0]], src[loc[0]:loc[1]]
+			src = src[loc[1]:]
+			if name == "raw" {
+				text(content, strings.HasPrefix(endTag, "{%-"))
+			} else {
+				trimNext = false
+				line += strings.Count(content, "\n")
+			}
+			line += strings.Count(endTag, "\n")
+			trimNext = strings.HasSuffix(endTag, "-%}")
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+}
@@ -0,0 +1,356 @@
+// Package liquid parses and renders Liquid templates, such as those of the
+// emails and pages hosted by Auth0. It supports the standard tags and
+// filters, along with custom tags such as those of Universal Login pages,
+// which is enough to check templates when planning and to preview them
+// locally.
+package liquid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is an error at a line of a template.
+type Error struct {
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+func errorf(line int, format string, a ...interface{}) error {
+	return &Error{Line: line, Message: fmt.Sprintf(format, a...)}
+}
+
+// Template is a parsed template.
+type Template struct {
+	nodes []node
+}
+
+// Parse parses a template, checking its syntax. Unknown tags and filters are
+// not errors, since Auth0 may support them: they are reported by Warnings.
+func Parse(src string) (*Template, error) {
+	return ParseTags(src)
+}
+
+// ParseTags parses a template like Parse, allowing the given custom tags as
+// well, such as the auth0:widget tag of Universal Login pages. Custom tags take
+// no arguments.
+func ParseTags(src string, custom ...string) (*Template, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, custom: map[string]bool{}}
+	for _, name := range custom {
+		p.custom[name] = true
+	}
+	nodes, end, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if end != nil {
+		name, _ := end.tagName()
+		return nil, errorf(end.line, "unexpected %s tag", name)
+	}
+	return &Template{nodes: nodes}, nil
+}
+
+// Render renders the template with the given variables. Undefined variables
+// are rendered empty.
+func (t *Template) Render(vars map[string]interface{}) (string, error) {
+	return t.RenderTags(vars, nil)
+}
+
+// RenderTags renders the template like Render, rendering custom tags as the
+// given content. Custom tags without content are rendered empty.
+func (t *Template) RenderTags(vars map[string]interface{}, tags map[string]string) (string, error) {
+	c := &context{
+		scopes:   []map[string]interface{}{vars, {}},
+		tags:     tags,
+		counters: map[string]int{},
+		cycles:   map[string]int{},
+	}
+	var b strings.Builder
+	if err := renderNodes(c, &b, t.nodes); err != nil && err != errBreak && err != errContinue {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Tag is a custom tag used by a template.
+type Tag struct {
+	Name string
+	Line int
+}
+
+// Tags returns the custom tags used by the template, in order of appearance.
+func (t *Template) Tags() []Tag {
+	var tags []Tag
+	walkCustomTags(t.nodes, func(n *customTagNode) {
+		tags = append(tags, Tag{Name: n.name, Line: n.line})
+	})
+	return tags
+}
+
+func walkCustomTags(nodes []node, fn func(*customTagNode)) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *customTagNode:
+			fn(n)
+		case *ifNode:
+			for _, b := range n.branches {
+				walkCustomTags(b.body, fn)
+			}
+			walkCustomTags(n.elseBody, fn)
+		case *caseNode:
+			for _, w := range n.whens {
+				walkCustomTags(w.body, fn)
+			}
+			walkCustomTags(n.elseBody, fn)
+		case *forNode:
+			walkCustomTags(n.body, fn)
+			walkCustomTags(n.elseBody, fn)
+		case *tablerowNode:
+			walkCustomTags(n.body, fn)
+		case *captureNode:
+			walkCustomTags(n.body, fn)
+		case *ifchangedNode:
+			walkCustomTags(n.body, fn)
+		case *liquidNode:
+			walkCustomTags(n.body, fn)
+		}
+	}
+}
+
+// Variable is a reference to a variable which is not defined by the template
+// itself.
+type Variable struct {
+	// Path is the dotted path of the variable, such as user.email, up to its
+	// first index which isn't a literal.
+	Path string
+	Line int
+}
+
+// Variables returns the variables the template references without defining
+// them with assign, capture or for tags, in order of appearance.
+func (t *Template) Variables() []Variable {
+	w := &templateWalker{bound: map[string]int{}, unknownTags: map[string]bool{}}
+	w.walk(t.nodes)
+	return w.variables
+}
+
+// Warnings returns the tags and filters used by the template which are not
+// supported, in order of appearance. They are rendered as if they were
+// absent: unknown tags are rendered empty, and unknown filters leave their
+// value as it is. The end tag of an unknown block tag, such as endform, isn't
+// reported.
+func (t *Template) Warnings() []*Error {
+	w := &templateWalker{bound: map[string]int{}, unknownTags: map[string]bool{}}
+	w.walk(t.nodes)
+	return w.warnings
+}
+
+type templateWalker struct {
+	bound       map[string]int
+	variables   []Variable
+	unknownTags map[string]bool
+	warnings    []*Error
+}
+
+func (w *templateWalker) bind(name string) {
+	w.bound[name]++
+}
+
+func (w *templateWalker) unbind(name string) {
+	w.bound[name]--
+}
+
+func (w *templateWalker) expression(e expression) {
+	switch e := e.(type) {
+	case *variable:
+		if w.bound[e.name] == 0 {
+			w.variables = append(w.variables, Variable{Path: e.staticPath(), Line: e.line})
+		}
+		for _, a := range e.path {
+			if a.index != nil {
+				w.expression(a.index)
+			}
+		}
+	case rangeExpression:
+		w.expression(e.from)
+		w.expression(e.to)
+	}
+}
+
+func (w *templateWalker) filtered(f *filtered) {
+	w.expression(f.value)
+	for _, call := range f.filters {
+		if call.fn == nil {
+			w.warnings = append(w.warnings, &Error{Line: f.line, Message: fmt.Sprintf("unknown filter %q", call.name)})
+		}
+		for _, a := range call.args {
+			w.expression(a)
+		}
+		for _, k := range call.keywords {
+			w.expression(k.value)
+		}
+	}
+}
+
+func (w *templateWalker) condition(c condition) {
+	switch c := c.(type) {
+	case comparison:
+		w.expression(c.left)
+		if c.right != nil {
+			w.expression(c.right)
+		}
+	case logical:
+		w.condition(c.left)
+		w.condition(c.right)
+	}
+}
+
+func (w *templateWalker) walk(nodes []node) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *outputNode:
+			w.filtered(n.value)
+		case *unknownTagNode:
+			if !strings.HasPrefix(n.name, "end") || !w.unknownTags[strings.TrimPrefix(n.name, "end")] {
+				w.warnings = append(w.warnings, &Error{Line: n.line, Message: fmt.Sprintf("unknown tag %q", n.name)})
+			}
+			w.unknownTags[n.name] = true
+		case *ifNode:
+			for _, b := range n.branches {
+				w.condition(b.condition)
+				w.walk(b.body)
+			}
+			w.walk(n.elseBody)
+		case *caseNode:
+			w.expression(n.value)
+			for _, b := range n.whens {
+				for _, v := range b.values {
+					w.expression(v)
+				}
+				w.walk(b.body)
+			}
+			w.walk(n.elseBody)
+		case *forNode:
+			w.expression(n.collection)
+			for _, e := range []expression{n.limit, n.offset} {
+				if e != nil {
+					w.expression(e)
+				}
+			}
+			w.bind(n.name)
+			w.bind("forloop")
+			w.walk(n.body)
+			w.unbind("forloop")
+			w.unbind(n.name)
+			w.walk(n.elseBody)
+		case *tablerowNode:
+			w.expression(n.collection)
+			for _, e := range []expression{n.limit, n.offset, n.cols} {
+				if e != nil {
+					w.expression(e)
+				}
+			}
+			w.bind(n.name)
+			w.bind("tablerowloop")
+			w.walk(n.body)
+			w.unbind("tablerowloop")
+			w.unbind(n.name)
+		case *cycleNode:
+			if n.group != nil {
+				w.expression(n.group)
+			}
+			for _, v := range n.values {
+				w.expression(v)
+			}
+		case *ifchangedNode:
+			w.walk(n.body)
+		case *liquidNode:
+			w.walk(n.body)
+		case *assignNode:
+			// Assigned variables are global, so they are bound for the rest of
+			// the template.
+			w.filtered(n.value)
+			w.bind(n.name)
+		case *captureNode:
+			w.walk(n.body)
+			w.bind(n.name)
+		}
+	}
+}
+
+// UnknownVariables returns the variables referenced by the template which
+// are not among the known ones. Known variables are dotted paths, where a
+// path ending with .* allows any property below it, such as
+// user.user_metadata.*. Referencing a part of a known path, such as user, is
+// allowed as well, and so are the size, first and last properties.
+func (t *Template) UnknownVariables(known []string) []Variable {
+	var unknown []Variable
+	for _, v := range t.Variables() {
+		if !isKnownVariable(v.Path, known) {
+			unknown = append(unknown, v)
+		}
+	}
+	return unknown
+}
+
+func isKnownVariable(path string, known []string) bool {
+	// The size, first and last properties are those of the value before them.
+	for _, p := range []string{".size", ".first", ".last"} {
+		if strings.HasSuffix(path, p) {
+			return isKnownVariable(strings.TrimSuffix(path, p), known)
+		}
+	}
+	for _, k := range known {
+		switch {
+		case path == k:
+			return true
+		case strings.HasSuffix(k, ".*") && strings.HasPrefix(path+".", strings.TrimSuffix(k, "*")):
+			return true
+		case strings.HasPrefix(k, path+"."):
+			return true
+		}
+	}
+	return false
+}
+
+// context holds the variables of a template being rendered, in scopes. The
+// first scope holds the variables given to Render, the second the assigned
+// ones, and the others those of for loops. It holds the content of custom tags
+// as well, and the state of the increment, decrement, cycle and ifchanged
+// tags.
+type context struct {
+	scopes    []map[string]interface{}
+	tags      map[string]string
+	counters  map[string]int
+	cycles    map[string]int
+	ifchanged string
+}
+
+func (c *context) lookup(name string) interface{} {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if v, ok := c.scopes[i][name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *context) assign(name string, v interface{}) {
+	c.scopes[1][name] = v
+}
+
+func (c *context) push(scope map[string]interface{}) {
+	c.scopes = append(c.scopes, scope)
+}
+
+func (c *context) pop() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
@@ -0,0 +1,305 @@
+package liquid
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testVars = map[string]interface{}{
+	"user": map[string]interface{}{
+		"name":  "Jane Doe",
+		"email": "jane@example.com",
+		"user_metadata": map[string]interface{}{
+			"plan": "pro",
+		},
+	},
+	"tenant":  "example",
+	"count":   3,
+	"price":   2.5,
+	"nothing": "",
+	"items":   []interface{}{"b", "a", "c"},
+	"numbers": []interface{}{3.0, 1.0, 2.0},
+	"products": []interface{}{
+		map[string]interface{}{"title": "Shirt", "type": "apparel", "price": 10, "available": true},
+		map[string]interface{}{"title": "mug", "type": "kitchen", "price": 5, "available": false},
+		map[string]interface{}{"title": "Hat", "type": "apparel", "price": 7.5},
+	},
+}
+
+func TestRender(t *testing.T) {
+	for _, tt := range []struct {
+		template string
+		expected string
+	}{
+		{"Hello {{ user.name }}!", "Hello Jane Doe!"},
+		{`{{ user["email"] }}`, "jane@example.com"},
+		{"{{ user.user_metadata.plan | upcase }}", "PRO"},
+		{"{{ missing }}|{{ missing.property }}", "|"},
+		{`{{ missing | default: "none" }}`, "none"},
+		{`{{ nothing | default: "none" }}`, "none"},
+		{`{{ "hello world" | capitalize }}`, "Hello world"},
+		{`{{ user.name | split: " " | first }}`, "Jane"},
+		{`{{ items | sort | join: ", " }}`, "a, b, c"},
+		{`{{ numbers | sort | last }}`, "3"},
+		{`{{ items.size }} {{ items | size }} {{ user.name.size }}`, "3 3 8"},
+		{`{{ count | plus: 2 }} {{ count | divided_by: 2 }} {{ price | times: 2 }}`, "5 1 5"},
+		{`{{ "<b>bold</b>" | escape }} {{ "<b>bold</b>" | strip_html }}`, "&lt;b&gt;bold&lt;/b&gt; bold"},
+		{`{{ "a long sentence" | truncate: 8 }}`, "a lon..."},
+		{`{{ "2021-04-01T10:00:00Z" | date: "%Y-%m-%d %H:%M" }}`, "2021-04-01 10:00"},
+		{`{% if user.email contains "@example.com" %}internal{% else %}external{% endif %}`, "internal"},
+		{`{% if count > 5 %}many{% elsif count > 1 %}some{% else %}one{% endif %}`, "some"},
+		{`{% unless missing %}unset{% endunless %}`, "unset"},
+		{`{% if nothing == empty and count %}empty{% endif %}`, "empty"},
+		{`{% if false and false or true %}left{% else %}right{% endif %}`, "right"},
+		{`{% case tenant %}{% when "other" %}other{% when "example", "test" %}example{% else %}none{% endcase %}`, "example"},
+		{`{% for item in items reversed %}{{ forloop.index }}{{ item }}{% unless forloop.last %},{% endunless %}{% endfor %}`, "1c,2a,3b"},
+		{`{% for i in (1..5) limit: 2 offset: 1 %}{{ i }}{% endfor %}`, "23"},
+		{`{% for i in (1..5) %}{% if i == 2 %}{% continue %}{% endif %}{% if i == 4 %}{% break %}{% endif %}{{ i }}{% endfor %}`, "13"},
+		{`{% for item in missing %}{{ item }}{% else %}nothing{% endfor %}`, "nothing"},
+		{`{% assign greeting = "Hi " | append: user.name %}{{ greeting }}`, "Hi Jane Doe"},
+		{`{% capture link %}https://{{ tenant }}.auth0.com{% endcapture %}{{ link }}`, "https://example.auth0.com"},
+		{"{% raw %}{{ user.name }}{% endraw %}", "{{ user.name }}"},
+		{"a{% comment %}{{ user.name }}{% endcomment %}b", "ab"},
+		{"<p>\n  {%- if true -%}\n  yes\n  {%- endif -%}\n</p>", "<p>yes</p>"},
+		{"{ not a tag }", "{ not a tag }"},
+		{`{% for i in (1..4) %}{% cycle "odd", "even" %} {% endfor %}`, "odd even odd even "},
+		{`{% cycle "g": "a", "b" %}{% cycle "g": "a", "b" %}{% cycle "h": "a", "b" %}`, "aba"},
+		{`{% increment n %}{% increment n %}{% decrement n %}{% decrement m %}`, "011-1"},
+		{`{% echo user.name | upcase %}`, "JANE DOE"},
+		{`{% for i in items %}{% ifchanged %}{{ i | size }}{% endifchanged %}{% endfor %}`, "1"},
+		{`{% tablerow i in (1..3) cols: 2 %}{{ i }}{% endtablerow %}`, "<tr class=\"row1\">\n<td class=\"col1\">1</td><td class=\"col2\">2</td></tr>\n<tr class=\"row2\"><td class=\"col1\">3</td></tr>\n"},
+		{`{% tablerow p in products limit: 2 %}{{ tablerowloop.col }}{{ p.title }}{% endtablerow %}`, "<tr class=\"row1\">\n<td class=\"col1\">1Shirt</td><td class=\"col2\">2mug</td></tr>\n"},
+		{"{% liquid\n  assign sorted = items | sort\n  # comment\n  for i in sorted\n    echo i\n  endfor\n%}", "abc"},
+		{"a{% # comment %}b", "ab"},
+		{`{{ items | concat: numbers | join: "," }}`, "b,a,c,3,1,2"},
+		{`{{ products | where: "type", "apparel" | map: "title" | join: ", " }}`, "Shirt, Hat"},
+		{`{{ products | where: "available" | size }} {{ products | reject: "available" | size }}`, "1 2"},
+		{`{% assign mug = products | find: "type", "kitchen" %}{{ mug.title }} {{ products | find_index: "title", "Hat" }} {{ products | has: "type", "toys" }}`, "mug 2 false"},
+		{`{{ 1 | at_least: 5 }} {{ 8 | at_most: 5 }} {{ 4.5 | at_least: 2 }}`, "5 5 4.5"},
+		{`{{ products | map: "title" | sort_natural | join: ", " }} | {{ products | sort_natural: "title" | map: "title" | first }}`, "Hat, mug, Shirt | Hat"},
+		{`{{ products | sum: "price" }} {{ (1..4) | sum }}`, "22.5 10"},
+		{`{{ products | uniq: "type" | size }} {{ products | compact: "available" | size }}`, "2 2"},
+		{`{{ false | default: "none", allow_false: true }} {{ false | default: "none" }}`, "false none"},
+		{`{{ "hello" | base64_encode }} {{ "aGVsbG8=" | base64_decode }} {{ "a?b" | base64_url_safe_encode }}`, "aGVsbG8= hello YT9i"},
+		{`{{ "a-b-a" | remove_last: "a" }} {{ "a-b-a" | replace_last: "a", "c" }}`, "a-b- a-b-c"},
+	} {
+		t.Run(tt.template, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := tmpl.Render(testVars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRenderDateNow(t *testing.T) {
+	now = func() time.Time { return time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	tmpl, err := Parse(`{{ "now" | date: "%B %e, %Y" }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, _ := tmpl.Render(nil); actual != "April  1, 2021" {
+		t.Errorf("expected the current date, got %q", actual)
+	}
+}
+
+func TestRenderError(t *testing.T) {
+	tmpl, err := Parse("\n{{ count | divided_by: 0 }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tmpl.Render(testVars)
+	if expected := "line 2: divided_by filter: divided by 0"; err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"UnclosedOutput", "<p>\n{{ user.name </p>", `line 2: output is not closed with "}}"`},
+		{"UnclosedTag", "{% if user %}\n{% endif", `line 2: tag is not closed with "%}"`},
+		{"UnclosedBlock", "\n\n{% if user %}\n<p>", "line 3: if tag was never closed"},
+		{"UnclosedRaw", "{% raw %}", "line 1: raw tag was never closed"},
+		{"UnexpectedEnd", "<p>\n</p>\n{% endif %}", "line 3: unexpected endif tag"},
+		{"MismatchedEnd", "{% for item in items %}\n{% endif %}", "line 2: unexpected endif tag in for tag"},
+		{"UnsupportedTag", "{% render 'header' %}", "line 1: render tag is not supported, as templates can't render other templates"},
+		{"UnknownKeyword", `{{ user.name | default: "none", allow_true: true }}`, "line 1: default filter has no allow_true argument"},
+		{"UnclosedTablerow", "{% tablerow i in items %}", "line 1: tablerow tag was never closed"},
+		{"InvalidCounter", "{% increment user.count %}", `line 1: invalid variable name "user.count"`},
+		{"LineInLiquid", "{% liquid\nassign x = 1\nfor %}", "line 3: expected a name, got end of markup"},
+		{"InvalidExpression", "{{ user. }}", "line 1: expected a name, got end of markup"},
+		{"TrailingMarkup", "{{ user.name user.email }}", `line 1: expected end of markup, got "user"`},
+		{"InvalidOperator", "{% if user = 1 %}{% endif %}", `line 1: unexpected "="`},
+		{"InvalidAssign", "{% assign = 1 %}", `line 1: invalid variable name ""`},
+		{"InvalidFor", "{% for item items %}{% endfor %}", `line 1: expected "in", got "items"`},
+		{"UnclosedString", `{{ "hello }}`, `line 1: string "hello is not closed`},
+		{"LineAfterTrim", "a\n\n{%- if true -%}\n\n{{ user. }}{% endif %}", "line 5: expected a name, got end of markup"},
+		{"LineAfterComment", "{% comment %}\n\n{% endcomment %}{{ user. }}", "line 3: expected a name, got end of markup"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.template)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		template string
+		expected []string
+		rendered string
+	}{
+		{"None", "{{ user.name | upcase }}", nil, "JANE DOE"},
+		{"UnknownTag", "a{% inclde 'header' %}b", []string{`line 1: unknown tag "inclde"`}, "ab"},
+		{"UnknownBlockTag", "{% form 'login' %}\n{{ user.name }}\n{% endform %}", []string{`line 1: unknown tag "form"`}, "\nJane Doe\n"},
+		{"UnknownEndTag", "{% endform %}", []string{`line 1: unknown tag "endform"`}, ""},
+		{"UnknownFilter", "\n{{ user.name | upcse }}", []string{`line 2: unknown filter "upcse"`}, "\nJane Doe"},
+		{"UnknownFilterArguments", `{{ user.name | t: "hello", count: 2 | upcase }}`, []string{`line 1: unknown filter "t"`}, "JANE DOE"},
+		{"Nested", "{% if user %}\n{% liquid\nassign x = user.name | pad\nwidget %}{% endif %}", []string{`line 3: unknown filter "pad"`, `line 4: unknown tag "widget"`}, "\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual []string
+			for _, w := range tmpl.Warnings() {
+				actual = append(actual, w.Error())
+			}
+			if !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("expected warnings %v, got %v", tt.expected, actual)
+			}
+			rendered, err := tmpl.Render(testVars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rendered != tt.rendered {
+				t.Errorf("expected %q, got %q", tt.rendered, rendered)
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	tmpl, err := Parse(`{{ user.name }}
+{% assign plan = user.user_metadata.plan %}{{ plan }}
+{% for item in user.items %}{{ item.name }}{{ forloop.index }}{% endfor %}{{ item }}
+{% if application.name == tenant %}{{ user[key] }}{% endif %}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Variable{
+		{Path: "user.name", Line: 1},
+		{Path: "user.user_metadata.plan", Line: 2},
+		{Path: "user.items", Line: 3},
+		{Path: "item", Line: 3},
+		{Path: "application.name", Line: 4},
+		{Path: "tenant", Line: 4},
+		{Path: "user", Line: 4},
+		{Path: "key", Line: 4},
+	}
+	if actual := tmpl.Variables(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestVariablesTags(t *testing.T) {
+	tmpl, err := Parse(`{% tablerow p in products cols: columns %}{{ p.title }}{{ tablerowloop.col }}{% endtablerow %}
+{% cycle group: first, "second" %}{% increment counter %}{% ifchanged %}{{ tenant }}{% endifchanged %}
+{% liquid
+  echo user.email | default: fallback, allow_false: allow
+%}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Variable{
+		{Path: "products", Line: 1},
+		{Path: "columns", Line: 1},
+		{Path: "group", Line: 2},
+		{Path: "first", Line: 2},
+		{Path: "tenant", Line: 2},
+		{Path: "user.email", Line: 4},
+		{Path: "fallback", Line: 4},
+		{Path: "allow", Line: 4},
+	}
+	if actual := tmpl.Variables(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestUnknownVariables(t *testing.T) {
+	tmpl, err := Parse(`{{ user.email }} {{ user.user_metadata.plan }} {% if user %}{{ user.emial }}{% endif %}
+{{ application.name }} {{ application.id }} {{ url }} {{ application.name.size }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	known := []string{"user.email", "user.user_metadata.*", "application.name", "url"}
+	expected := []Variable{
+		{Path: "user.emial", Line: 1},
+		{Path: "application.id", Line: 2},
+	}
+	if actual := tmpl.UnknownVariables(known); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestCustomTags(t *testing.T) {
+	tmpl, err := ParseTags("<head>{%- auth0:head -%}</head>\n{% if user %}{% auth0:widget %}{% endif %}", "auth0:head", "auth0:widget")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTags := []Tag{{Name: "auth0:head", Line: 1}, {Name: "auth0:widget", Line: 2}}
+	if actual := tmpl.Tags(); !reflect.DeepEqual(expectedTags, actual) {
+		t.Errorf("expected %v, got %v", expectedTags, actual)
+	}
+
+	actual, err := tmpl.RenderTags(testVars, map[string]string{"auth0:widget": "<main></main>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<head></head>\n<main></main>"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	tmpl, err = Parse("{% auth0:widget %}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := tmpl.Tags(); len(tags) != 0 {
+		t.Errorf("expected no custom tags, got %v", tags)
+	}
+	if warnings := tmpl.Warnings(); len(warnings) != 1 || warnings[0].Error() != `line 1: unknown tag "auth0:widget"` {
+		t.Errorf("expected an unknown tag warning, got %v", warnings)
+	}
+
+	for template, expected := range map[string]string{
+		"{% auth0:head title %}":   "line 1: auth0:head tag takes no arguments",
+		"{% auth0:head %}{% if %}": "line 1: expected a value, got end of markup",
+	} {
+		_, err := ParseTags(template, "auth0:head")
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected error %q, got %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,567 @@
+package liquid
+
+import (
+	"errors"
+	"strings"
+)
+
+// node is a part of a parsed template.
+type node interface {
+	render(c *context, b *strings.Builder) error
+}
+
+type textNode struct {
+	text string
+}
+
+type outputNode struct {
+	value *filtered
+}
+
+type branch struct {
+	condition condition
+	body      []node
+}
+
+// ifNode is an if tag, with its elsif branches, or an unless tag, whose
+// condition is negated.
+type ifNode struct {
+	branches []branch
+	elseBody []node
+	negate   bool
+}
+
+type when struct {
+	values []expression
+	body   []node
+}
+
+type caseNode struct {
+	value    expression
+	whens    []when
+	elseBody []node
+}
+
+type forNode struct {
+	name          string
+	collection    expression
+	limit, offset expression
+	reversed      bool
+	body          []node
+	elseBody      []node
+}
+
+// tablerowNode is a tablerow tag, which renders its body in the cells of an
+// HTML table, cols cells per row.
+type tablerowNode struct {
+	forNode
+	cols expression
+}
+
+// cycleNode is a cycle tag. Cycle tags with the same group, or with the same
+// values when they have no group, take turns outputting their values.
+type cycleNode struct {
+	group  expression
+	key    string
+	values []expression
+}
+
+// counterNode is an increment or a decrement tag. Counters are independent
+// from the variables defined with assign and capture.
+type counterNode struct {
+	name      string
+	decrement bool
+}
+
+// ifchangedNode is an ifchanged tag, whose body is only output when it
+// differs from the last time it was rendered.
+type ifchangedNode struct {
+	body []node
+}
+
+// liquidNode is a liquid tag, holding a tag on each line.
+type liquidNode struct {
+	body []node
+}
+
+type assignNode struct {
+	name  string
+	value *filtered
+}
+
+type captureNode struct {
+	name string
+	body []node
+}
+
+// customTagNode is a custom tag, rendered as the content given for it.
+type customTagNode struct {
+	name string
+	line int
+}
+
+// unknownTagNode is a tag which isn't supported, such as one added by another
+// implementation of Liquid. It is rendered empty, and reported by Warnings.
+type unknownTagNode struct {
+	name string
+	line int
+}
+
+type breakNode struct{}
+
+type continueNode struct{}
+
+var (
+	errBreak    = errors.New("break tag outside of a for loop")
+	errContinue = errors.New("continue tag outside of a for loop")
+)
+
+// parser builds the nodes of a template from its tokens.
+type parser struct {
+	tokens []token
+	pos    int
+	custom map[string]bool
+}
+
+// parse parses nodes until the end of the template, or until a tag which
+// isn't a node on its own, such as else or endif, which is returned.
+func (p *parser) parse() ([]node, *token, error) {
+	var nodes []node
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+		switch t.kind {
+		case textToken:
+			nodes = append(nodes, &textNode{t.value})
+		case outputToken:
+			e, err := newExprParser(t.value, t.line)
+			if err != nil {
+				return nil, nil, err
+			}
+			value, err := e.filtered()
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := e.expectEnd(); err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, &outputNode{value})
+		case tagToken:
+			name, markup := t.tagName()
+			if strings.HasPrefix(name, "#") {
+				// Inline comment.
+				continue
+			}
+			parse, ok := tags[name]
+			if !ok && p.custom[name] {
+				parse, ok = parseCustomTag, true
+			}
+			if !ok {
+				if blockEnds[name] {
+					return nodes, &t, nil
+				}
+				nodes = append(nodes, &unknownTagNode{name, t.line})
+				continue
+			}
+			n, err := parse(p, t, markup)
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil, nil
+}
+
+// parseBody parses the body of a block tag, up to one of the given tags.
+func (p *parser) parseBody(open token, ends ...string) ([]node, string, token, error) {
+	nodes, end, err := p.parse()
+	if err != nil {
+		return nil, "", token{}, err
+	}
+	openName, _ := open.tagName()
+	if end == nil {
+		return nil, "", token{}, errorf(open.line, "%s tag was never closed", openName)
+	}
+	name, _ := end.tagName()
+	for _, e := range ends {
+		if name == e {
+			return nodes, name, *end, nil
+		}
+	}
+	return nil, "", token{}, errorf(end.line, "unexpected %s tag in %s tag", name, openName)
+}
+
+// blockEnds are the tags ending or splitting the body of block tags.
+var blockEnds = map[string]bool{
+	"else": true, "elsif": true, "when": true,
+	"endif": true, "endunless": true, "endcase": true, "endfor": true, "endcapture": true,
+	"endtablerow": true, "endifchanged": true,
+}
+
+type tagParser func(p *parser, t token, markup string) (node, error)
+
+var tags map[string]tagParser
+
+func init() {
+	tags = map[string]tagParser{
+		"if":        parseIf,
+		"unless":    parseIf,
+		"case":      parseCase,
+		"for":       parseFor,
+		"assign":    parseAssign,
+		"capture":   parseCapture,
+		"break":     parseSimple(breakNode{}),
+		"continue":  parseSimple(continueNode{}),
+		"tablerow":  parseTablerow,
+		"cycle":     parseCycle,
+		"increment": parseCounter,
+		"decrement": parseCounter,
+		"echo":      parseEcho,
+		"ifchanged": parseIfchanged,
+		"liquid":    parseLiquid,
+		"include":   parseUnsupported,
+		"render":    parseUnsupported,
+	}
+}
+
+func parseSimple(n node) tagParser {
+	return func(p *parser, t token, markup string) (node, error) {
+		if markup != "" {
+			name, _ := t.tagName()
+			return nil, errorf(t.line, "%s tag takes no arguments", name)
+		}
+		return n, nil
+	}
+}
+
+func parseCustomTag(p *parser, t token, markup string) (node, error) {
+	name, _ := t.tagName()
+	if markup != "" {
+		return nil, errorf(t.line, "%s tag takes no arguments", name)
+	}
+	return &customTagNode{name, t.line}, nil
+}
+
+func parseCondition(markup string, line int) (condition, error) {
+	e, err := newExprParser(markup, line)
+	if err != nil {
+		return nil, err
+	}
+	c, err := e.condition()
+	if err != nil {
+		return nil, err
+	}
+	return c, e.expectEnd()
+}
+
+func parseIf(p *parser, t token, markup string) (node, error) {
+	open, _ := t.tagName()
+	n := &ifNode{negate: open == "unless"}
+	ends := []string{"elsif", "else", "end" + open}
+	if n.negate {
+		ends = []string{"else", "endunless"}
+	}
+
+	c, err := parseCondition(markup, t.line)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		body, end, endToken, err := p.parseBody(t, ends...)
+		if err != nil {
+			return nil, err
+		}
+		n.branches = append(n.branches, branch{c, body})
+		switch end {
+		case "elsif":
+			_, markup := endToken.tagName()
+			if c, err = parseCondition(markup, endToken.line); err != nil {
+				return nil, err
+			}
+			continue
+		case "else":
+			if n.elseBody, _, _, err = p.parseBody(t, "end"+open); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	}
+}
+
+func parseCase(p *parser, t token, markup string) (node, error) {
+	e, err := newExprParser(markup, t.line)
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := e.expectEnd(); err != nil {
+		return nil, err
+	}
+	n := &caseNode{value: value}
+
+	// Only whitespace may come before the first when tag.
+	body, end, endToken, err := p.parseBody(t, "when", "else", "endcase")
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range body {
+		if text, ok := b.(*textNode); !ok || strings.TrimSpace(text.text) != "" {
+			return nil, errorf(t.line, "case tag can only hold when and else tags")
+		}
+	}
+	for end == "when" {
+		_, markup := endToken.tagName()
+		e, err := newExprParser(markup, endToken.line)
+		if err != nil {
+			return nil, err
+		}
+		var w when
+		for {
+			v, err := e.expression()
+			if err != nil {
+				return nil, err
+			}
+			w.values = append(w.values, v)
+			if !e.accept(punctTok, ",") && !e.accept(identTok, "or") {
+				break
+			}
+		}
+		if err := e.expectEnd(); err != nil {
+			return nil, err
+		}
+		if w.body, end, endToken, err = p.parseBody(t, "when", "else", "endcase"); err != nil {
+			return nil, err
+		}
+		n.whens = append(n.whens, w)
+	}
+	if end == "else" {
+		if n.elseBody, _, _, err = p.parseBody(t, "endcase"); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func parseFor(p *parser, t token, markup string) (node, error) {
+	e, err := newExprParser(markup, t.line)
+	if err != nil {
+		return nil, err
+	}
+	n := &forNode{}
+	if err := parseLoop(e, n, nil); err != nil {
+		return nil, err
+	}
+
+	body, end, _, err := p.parseBody(t, "else", "endfor")
+	if err != nil {
+		return nil, err
+	}
+	n.body = body
+	if end == "else" {
+		if n.elseBody, _, _, err = p.parseBody(t, "endfor"); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func parseTablerow(p *parser, t token, markup string) (node, error) {
+	e, err := newExprParser(markup, t.line)
+	if err != nil {
+		return nil, err
+	}
+	n := &tablerowNode{}
+	if err := parseLoop(e, &n.forNode, &n.cols); err != nil {
+		return nil, err
+	}
+	if n.body, _, _, err = p.parseBody(t, "endtablerow"); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// parseLoop parses the markup of a for tag, or of a tablerow tag when cols is
+// not nil, which has a cols parameter and can't be reversed.
+func parseLoop(e *exprParser, n *forNode, cols *expression) error {
+	var err error
+	if n.name, err = e.ident(); err != nil {
+		return err
+	}
+	if err := e.expect(identTok, "in"); err != nil {
+		return err
+	}
+	if n.collection, err = e.expression(); err != nil {
+		return err
+	}
+	params := "reversed, limit or offset"
+	if cols != nil {
+		params = "cols, limit or offset"
+	}
+	for !e.done() {
+		var param *expression
+		switch {
+		case cols == nil && e.accept(identTok, "reversed"):
+			n.reversed = true
+			continue
+		case e.accept(identTok, "limit"):
+			param = &n.limit
+		case e.accept(identTok, "offset"):
+			param = &n.offset
+		case cols != nil && e.accept(identTok, "cols"):
+			param = cols
+		default:
+			return e.unexpected(params)
+		}
+		if err := e.expect(punctTok, ":"); err != nil {
+			return err
+		}
+		if *param, err = e.expression(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseCycle(p *parser, t token, markup string) (node, error) {
+	e, err := newExprParser(markup, t.line)
+	if err != nil {
+		return nil, err
+	}
+	n := &cycleNode{key: markup}
+	for {
+		v, err := e.expression()
+		if err != nil {
+			return nil, err
+		}
+		if n.group == nil && len(n.values) == 0 && e.accept(punctTok, ":") {
+			n.group = v
+			continue
+		}
+		n.values = append(n.values, v)
+		if !e.accept(punctTok, ",") {
+			break
+		}
+	}
+	return n, e.expectEnd()
+}
+
+func parseCounter(p *parser, t token, markup string) (node, error) {
+	name, _ := t.tagName()
+	if !isIdentifier(markup) {
+		return nil, errorf(t.line, "invalid variable name %q", markup)
+	}
+	return &counterNode{name: markup, decrement: name == "decrement"}, nil
+}
+
+func parseEcho(p *parser, t token, markup string) (node, error) {
+	e, err := newExprParser(markup, t.line)
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.filtered()
+	if err != nil {
+		return nil, err
+	}
+	return &outputNode{value}, e.expectEnd()
+}
+
+func parseIfchanged(p *parser, t token, markup string) (node, error) {
+	if markup != "" {
+		return nil, errorf(t.line, "ifchanged tag takes no arguments")
+	}
+	body, _, _, err := p.parseBody(t, "endifchanged")
+	if err != nil {
+		return nil, err
+	}
+	return &ifchangedNode{body}, nil
+}
+
+// parseLiquid parses a liquid tag, whose lines are tags without delimiters.
+// Lines starting with # are comments, and so are the lines between comment
+// and endcomment.
+func parseLiquid(p *parser, t token, markup string) (node, error) {
+	// The markup is trimmed, so count the lines dropped before it.
+	first := t.line + strings.Count(t.value[:strings.Index(t.value, markup)], "\n")
+	var tokens []token
+	comment := false
+	for i, line := range strings.Split(markup, "\n") {
+		line = strings.TrimSpace(line)
+		name, _ := token{value: line}.tagName()
+		switch {
+		case comment:
+			comment = name != "endcomment"
+		case name == "comment":
+			comment = true
+		case line != "" && !strings.HasPrefix(line, "#"):
+			tokens = append(tokens, token{kind: tagToken, value: line, line: first + i})
+		}
+	}
+	if comment {
+		return nil, errorf(t.line, "comment tag was never closed")
+	}
+
+	l := &parser{tokens: tokens, custom: p.custom}
+	body, end, err := l.parse()
+	if err != nil {
+		return nil, err
+	}
+	if end != nil {
+		name, _ := end.tagName()
+		return nil, errorf(end.line, "unexpected %s tag", name)
+	}
+	return &liquidNode{body}, nil
+}
+
+// parseUnsupported fails on tags rendering other templates, which templates
+// hosted by Auth0 can't do.
+func parseUnsupported(p *parser, t token, markup string) (node, error) {
+	name, _ := t.tagName()
+	return nil, errorf(t.line, "%s tag is not supported, as templates can't render other templates", name)
+}
+
+func parseAssign(p *parser, t token, markup string) (node, error) {
+	parts := strings.SplitN(markup, "=", 2)
+	if len(parts) != 2 {
+		return nil, errorf(t.line, "assign tag must be of the form {%% assign name = value %%}")
+	}
+	name := strings.TrimSpace(parts[0])
+	if !isIdentifier(name) {
+		return nil, errorf(t.line, "invalid variable name %q", name)
+	}
+	e, err := newExprParser(parts[1], t.line)
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.filtered()
+	if err != nil {
+		return nil, err
+	}
+	return &assignNode{name, value}, e.expectEnd()
+}
+
+func parseCapture(p *parser, t token, markup string) (node, error) {
+	if !isIdentifier(markup) {
+		return nil, errorf(t.line, "invalid variable name %q", markup)
+	}
+	body, _, _, err := p.parseBody(t, "endcapture")
+	if err != nil {
+		return nil, err
+	}
+	return &captureNode{markup, body}, nil
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if i == 0 && !isIdentStart(r) || !isIdentPart(r) {
+			return false
+		}
+	}
+	return true
+}
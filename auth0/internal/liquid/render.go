@@ -0,0 +1,239 @@
+package liquid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func renderNodes(c *context, b *strings.Builder, nodes []node) error {
+	for _, n := range nodes {
+		if err := n.render(c, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *textNode) render(c *context, b *strings.Builder) error {
+	b.WriteString(n.text)
+	return nil
+}
+
+func (n *outputNode) render(c *context, b *strings.Builder) error {
+	v, err := n.value.eval(c)
+	if err != nil {
+		return err
+	}
+	b.WriteString(toString(v))
+	return nil
+}
+
+func (n *ifNode) render(c *context, b *strings.Builder) error {
+	for _, br := range n.branches {
+		if br.condition.test(c) != n.negate {
+			return renderNodes(c, b, br.body)
+		}
+	}
+	return renderNodes(c, b, n.elseBody)
+}
+
+func (n *caseNode) render(c *context, b *strings.Builder) error {
+	v := n.value.eval(c)
+	for _, w := range n.whens {
+		for _, value := range w.values {
+			if equal(v, value.eval(c)) {
+				return renderNodes(c, b, w.body)
+			}
+		}
+	}
+	return renderNodes(c, b, n.elseBody)
+}
+
+// items returns the items a for or a tablerow tag iterates over.
+func (n *forNode) items(c *context) []interface{} {
+	collection := n.collection.eval(c)
+	items := toSlice(collection)
+	if m, ok := collection.(map[string]interface{}); ok {
+		// Maps are iterated as [key, value] pairs, sorted by key.
+		items = nil
+		for _, k := range sortedMapKeys(m) {
+			items = append(items, []interface{}{k, m[k]})
+		}
+	}
+	if n.offset != nil {
+		offset, _ := toInt(n.offset.eval(c))
+		if offset > len(items) {
+			offset = len(items)
+		}
+		if offset > 0 {
+			items = items[offset:]
+		}
+	}
+	if n.limit != nil {
+		if limit, _ := toInt(n.limit.eval(c)); limit >= 0 && limit < len(items) {
+			items = items[:limit]
+		}
+	}
+	if n.reversed {
+		reversed := make([]interface{}, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		items = reversed
+	}
+	return items
+}
+
+func (n *forNode) render(c *context, b *strings.Builder) error {
+	items := n.items(c)
+	if len(items) == 0 {
+		return renderNodes(c, b, n.elseBody)
+	}
+
+	for i, item := range items {
+		c.push(map[string]interface{}{
+			n.name: item,
+			"forloop": map[string]interface{}{
+				"index":   i + 1,
+				"index0":  i,
+				"rindex":  len(items) - i,
+				"rindex0": len(items) - i - 1,
+				"first":   i == 0,
+				"last":    i == len(items)-1,
+				"length":  len(items),
+			},
+		})
+		err := renderNodes(c, b, n.body)
+		c.pop()
+		if err == errBreak {
+			break
+		}
+		if err != nil && err != errContinue {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *tablerowNode) render(c *context, b *strings.Builder) error {
+	items := n.items(c)
+	cols := len(items)
+	if n.cols != nil {
+		if v, ok := toInt(n.cols.eval(c)); ok && v > 0 {
+			cols = v
+		}
+	}
+
+	b.WriteString("<tr class=\"row1\">\n")
+	for i, item := range items {
+		row, col := i/cols, i%cols
+		last := i == len(items)-1
+		c.push(map[string]interface{}{
+			n.name: item,
+			"tablerowloop": map[string]interface{}{
+				"index":     i + 1,
+				"index0":    i,
+				"rindex":    len(items) - i,
+				"rindex0":   len(items) - i - 1,
+				"first":     i == 0,
+				"last":      last,
+				"length":    len(items),
+				"row":       row + 1,
+				"col":       col + 1,
+				"col0":      col,
+				"col_first": col == 0,
+				"col_last":  col == cols-1,
+			},
+		})
+		fmt.Fprintf(b, "<td class=\"col%d\">", col+1)
+		err := renderNodes(c, b, n.body)
+		c.pop()
+		b.WriteString("</td>")
+		if err == errBreak {
+			break
+		}
+		if err != nil && err != errContinue {
+			return err
+		}
+		if col == cols-1 && !last {
+			fmt.Fprintf(b, "</tr>\n<tr class=\"row%d\">", row+2)
+		}
+	}
+	b.WriteString("</tr>\n")
+	return nil
+}
+
+func (n *cycleNode) render(c *context, b *strings.Builder) error {
+	key := n.key
+	if n.group != nil {
+		key = toString(n.group.eval(c))
+	}
+	i := c.cycles[key]
+	c.cycles[key] = i + 1
+	b.WriteString(toString(n.values[i%len(n.values)].eval(c)))
+	return nil
+}
+
+func (n *counterNode) render(c *context, b *strings.Builder) error {
+	v := c.counters[n.name]
+	if n.decrement {
+		v--
+		c.counters[n.name] = v
+	} else {
+		c.counters[n.name] = v + 1
+	}
+	b.WriteString(strconv.Itoa(v))
+	return nil
+}
+
+func (n *ifchangedNode) render(c *context, b *strings.Builder) error {
+	var rendered strings.Builder
+	if err := renderNodes(c, &rendered, n.body); err != nil {
+		return err
+	}
+	if s := rendered.String(); s != c.ifchanged {
+		c.ifchanged = s
+		b.WriteString(s)
+	}
+	return nil
+}
+
+func (n *liquidNode) render(c *context, b *strings.Builder) error {
+	return renderNodes(c, b, n.body)
+}
+
+func (n *assignNode) render(c *context, b *strings.Builder) error {
+	v, err := n.value.eval(c)
+	if err != nil {
+		return err
+	}
+	c.assign(n.name, v)
+	return nil
+}
+
+func (n *captureNode) render(c *context, b *strings.Builder) error {
+	var captured strings.Builder
+	if err := renderNodes(c, &captured, n.body); err != nil {
+		return err
+	}
+	c.assign(n.name, captured.String())
+	return nil
+}
+
+func (n *customTagNode) render(c *context, b *strings.Builder) error {
+	b.WriteString(c.tags[n.name])
+	return nil
+}
+
+func (*unknownTagNode) render(*context, *strings.Builder) error {
+	return nil
+}
+
+func (breakNode) render(*context, *strings.Builder) error {
+	return errBreak
+}
+
+func (continueNode) render(*context, *strings.Builder) error {
+	return errContinue
+}
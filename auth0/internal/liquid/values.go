@@ -0,0 +1,114 @@
+package liquid
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// toString renders a value the way Liquid outputs it.
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil, emptyValue:
+		return ""
+	case string:
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return toString(float64(v))
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		var b strings.Builder
+		for i := 0; i < rv.Len(); i++ {
+			b.WriteString(toString(rv.Index(i).Interface()))
+		}
+		return b.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// toFloat converts numbers, and strings holding numbers, to a float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toInt converts numbers, and strings holding numbers, to an int, truncating
+// them.
+func toInt(v interface{}) (int, bool) {
+	if i, ok := v.(int); ok {
+		return i, true
+	}
+	f, ok := toFloat(v)
+	return int(f), ok
+}
+
+// isInt reports whether a value is an integer, so that arithmetic on
+// integers gives integers, as in Liquid.
+func isInt(v interface{}) bool {
+	switch v := v.(type) {
+	case int, int64:
+		return true
+	case string:
+		_, err := strconv.Atoi(strings.TrimSpace(v))
+		return err == nil
+	}
+	return false
+}
+
+// toSlice converts arrays to a []interface{}. Other values are returned as
+// the single element of a slice, and nil as an empty slice.
+func toSlice(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	if s, ok := v.([]interface{}); ok {
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			s[i] = rv.Index(i).Interface()
+		}
+		return s
+	}
+	return []interface{}{v}
+}
+
+// isArray reports whether a value is an array.
+func isArray(v interface{}) bool {
+	kind := reflect.ValueOf(v).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// mapIndex returns the value held by a key of a map with string keys.
+func mapIndex(rv reflect.Value, key string) (interface{}, bool) {
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+	if !v.IsValid() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
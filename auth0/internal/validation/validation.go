@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 )
 
 // IsURLWithNoFragment is a SchemaValidateFunc which tests if the provided value
@@ -37,3 +38,22 @@ func IsURLWithNoFragment(i interface{}, k string) (warnings []string, errors []e
 
 	return
 }
+
+var hexColorRegexp = regexp.MustCompile(`^#(([0-9a-fA-F]{3}){1,2}|([0-9a-fA-F]{4}){1,2})$`)
+
+// IsHexColor is a SchemaValidateFunc which tests if the provided value is of
+// type string and a hex color, such as #fff, #ffffff or #ffffff80.
+func IsHexColor(i interface{}, k string) (warnings []string, errors []error) {
+
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if !hexColorRegexp.MatchString(v) {
+		errors = append(errors, fmt.Errorf("expected %q to be a hex color such as #ffffff, got %v", k, v))
+	}
+
+	return
+}
@@ -16,3 +16,26 @@ func TestIsURLWithNoFragment(t *testing.T) {
 		}
 	}
 }
+
+func TestIsHexColor(t *testing.T) {
+	for color, valid := range map[string]bool{
+		"#fff":      true,
+		"#FFFFFF":   true,
+		"#635dff":   true,
+		"#635dff80": true,
+		"#ffff":     true,
+		"635dff":    false,
+		"#635dfg":   false,
+		"#63":       false,
+		"#635dff8":  false,
+		"":          false,
+	} {
+		_, err := IsHexColor(color, "color")
+		if err != nil && valid {
+			t.Errorf("IsHexColor(%s) produced an unexpected error", color)
+		}
+		if err == nil && !valid {
+			t.Errorf("IsHexColor(%s) did not produce an error", color)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package auth0
 import (
 	"fmt"
 	"os"
+	"sort"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/mutexkv"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/meta"
 	"gopkg.in/auth0.v5"
@@ -12,8 +15,76 @@ import (
 	"github.com/alexkappa/terraform-provider-auth0/version"
 )
 
+// mutexKV is used to serialize operations which read, modify and write back
+// the same Auth0 object, so that concurrent resources don't overwrite each
+// others changes.
+var mutexKV = mutexkv.NewMutexKV()
+
+// config is the meta of the provider, which is passed to resources and data
+// sources.
+type config struct {
+	api     *management.Management
+	secrets *secretHasher
+
+	// claims are those of the resources planned with this configuration.
+	claims planClaims
+}
+
+// planClaims records what the planned resources claim, such as the domain
+// aliases of connections, so that two resources claiming the same thing are
+// reported. CustomizeDiff only sees one resource, so the claims of the others
+// are kept along with the provider, which is configured anew for each plan
+// and apply. A conflict is reported by the resource planned last.
+type planClaims struct {
+	sync.Mutex
+	owners map[string]map[string]map[string]bool
+}
+
+// claim records owner as an owner of key, among the claims of the given kind,
+// and returns the other owners of key, sorted.
+func (c *planClaims) claim(kind, key, owner string) []string {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.owners == nil {
+		c.owners = make(map[string]map[string]map[string]bool)
+	}
+	keys, ok := c.owners[kind]
+	if !ok {
+		keys = make(map[string]map[string]bool)
+		c.owners[kind] = keys
+	}
+	owners, ok := keys[key]
+	if !ok {
+		owners = make(map[string]bool)
+		keys[key] = owners
+	}
+	owners[owner] = true
+
+	var others []string
+	for other := range owners {
+		if other != owner {
+			others = append(others, other)
+		}
+	}
+	sort.Strings(others)
+	return others
+}
+
+// release removes the claims of owner of the given kind, so that a resource
+// planned again only holds its latest claims.
+func (c *planClaims) release(kind, owner string) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, owners := range c.owners[kind] {
+		delete(owners, owner)
+	}
+}
+
 // Provider returns a *schema.Provider.
 func Provider() *schema.Provider {
+	secrets := new(secretHasher)
 	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"domain": {
@@ -41,6 +112,12 @@ func Provider() *schema.Provider {
 				DefaultFunc:   schema.EnvDefaultFunc("AUTH0_API_TOKEN", nil),
 				ConflictsWith: []string{"client_id", "client_secret"},
 			},
+			"secrets_hmac_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("AUTH0_SECRETS_HMAC_KEY", nil),
+			},
 			"debug": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -57,41 +134,53 @@ func Provider() *schema.Provider {
 			"auth0_client":                     newClient(),
 			"auth0_global_client":              newGlobalClient(),
 			"auth0_client_grant":               newClientGrant(),
-			"auth0_connection":                 newConnection(),
+			"auth0_connection":                 newConnection(secrets),
+			"auth0_connection_client":          newConnectionClient(),
 			"auth0_custom_domain":              newCustomDomain(),
 			"auth0_custom_domain_verification": newCustomDomainVerification(),
 			"auth0_resource_server":            newResourceServer(),
+			"auth0_resource_server_scope":      newResourceServerScope(),
 			"auth0_rule":                       newRule(),
+			"auth0_rule_order":                 newRuleOrder(),
 			"auth0_rule_config":                newRuleConfig(),
-			"auth0_hook":                       newHook(),
+			"auth0_hook":                       newHook(secrets),
 			"auth0_prompt":                     newPrompt(),
 			"auth0_prompt_custom_text":         newPromptCustomText(),
-			"auth0_email":                      newEmail(),
+			"auth0_prompt_custom_texts":        newPromptCustomTexts(),
+			"auth0_prompt_partials":            newPromptPartials(),
+			"auth0_email":                      newEmail(secrets),
 			"auth0_email_template":             newEmailTemplate(),
 			"auth0_user":                       newUser(),
 			"auth0_tenant":                     newTenant(),
 			"auth0_role":                       newRole(),
 			"auth0_log_stream":                 newLogStream(),
 			"auth0_branding":                   newBranding(),
-			"auth0_guardian":                   newGuardian(),
+			"auth0_branding_theme":             newBrandingTheme(),
+			"auth0_attack_protection":          newAttackProtection(),
+			"auth0_guardian":                   newGuardian(secrets),
 			"auth0_organization":               newOrganization(),
-			"auth0_action":                     newAction(),
+			"auth0_pages":                      newPages(),
+			"auth0_action":                     newAction(secrets),
 			"auth0_trigger_binding":            newTriggerBinding(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"auth0_client":        newDataClient(),
-			"auth0_global_client": newDataGlobalClient(),
+			"auth0_client":                  newDataClient(),
+			"auth0_global_client":           newDataGlobalClient(),
+			"auth0_saml_metadata":           newDataSAMLMetadata(),
+			"auth0_email_template_preview":  newDataEmailTemplatePreview(),
+			"auth0_universal_login_preview": newDataUniversalLoginPreview(),
 		},
 	}
 
-	provider.ConfigureFunc = ConfigureProvider(provider.TerraformVersion)
+	provider.ConfigureFunc = ConfigureProvider(provider.TerraformVersion, secrets)
 
 	return provider
 }
 
 // ConfigureProvider will configure the *schema.Provider so that *management.Management
-// client is stored and passed into the subsequent resources as the meta parameter.
-func ConfigureProvider(terraformVersion string) func(data *schema.ResourceData) (interface{}, error) {
+// client is stored and passed into the subsequent resources as the meta parameter,
+// along with secrets, which is given the key secrets are hashed with.
+func ConfigureProvider(terraformVersion string, secrets *secretHasher) func(data *schema.ResourceData) (interface{}, error) {
 	return func(data *schema.ResourceData) (interface{}, error) {
 		providerVersion := version.ProviderVersion
 		sdkVersion := auth0.Version
@@ -110,6 +199,7 @@ func ConfigureProvider(terraformVersion string) func(data *schema.ResourceData)
 		clientID := data.Get("client_id").(string)
 		clientSecret := data.Get("client_secret").(string)
 		apiToken := data.Get("api_token").(string)
+		secrets.key = []byte(data.Get("secrets_hmac_key").(string))
 
 		authenticationOption := management.WithStaticToken(apiToken)
 		// if api_token is not specified, authenticate with client ID and client secret.
@@ -118,10 +208,14 @@ func ConfigureProvider(terraformVersion string) func(data *schema.ResourceData)
 			authenticationOption = management.WithClientCredentials(clientID, clientSecret)
 		}
 
-		return management.New(domain,
+		api, err := management.New(domain,
 			authenticationOption,
 			management.WithDebug(debug),
 			management.WithUserAgent(userAgent),
 		)
+		if err != nil {
+			return nil, err
+		}
+		return &config{api: api, secrets: secrets}, nil
 	}
 }
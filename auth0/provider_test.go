@@ -19,11 +19,15 @@ const wiremockHost = "localhost:8080"
 func providerWithTestingConfiguration() *schema.Provider {
 	provider := Provider()
 	provider.ConfigureFunc = func(data *schema.ResourceData) (interface{}, error) {
-		return management.New(
+		api, err := management.New(
 			wiremockHost,
 			management.WithInsecure(),
 			management.WithDebug(true),
 		)
+		if err != nil {
+			return nil, err
+		}
+		return &config{api: api}, nil
 	}
 	return provider
 }
@@ -34,7 +38,7 @@ func Auth0() (*management.Management, error) {
 	if err := p.Configure(c); err != nil {
 		return nil, err
 	}
-	return p.Meta().(*management.Management), nil
+	return p.Meta().(*config).api, nil
 }
 
 func TestMain(m *testing.M) {
@@ -47,6 +51,40 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProvider_secretsHMACKey(t *testing.T) {
+	configure := func(key string) *schema.Provider {
+		p := Provider()
+		err := p.Configure(terraform.NewResourceConfigRaw(map[string]interface{}{
+			"domain":           "example.auth0.com",
+			"api_token":        "token",
+			"secrets_hmac_key": key,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// Each provider hashes secrets with its own key, both when storing them
+	// and when diffing them.
+	a, b := configure("a"), configure("b")
+	hashA := a.Meta().(*config).secrets.hash("secret")
+	hashB := b.Meta().(*config).secrets.hash("secret")
+	if hashA == hashB {
+		t.Fatal("expected the hashes of providers with different keys to differ")
+	}
+
+	hookSecrets := func(p *schema.Provider) *schema.Schema {
+		return p.ResourcesMap["auth0_hook"].Schema["secrets"]
+	}
+	if !hookSecrets(a).DiffSuppressFunc("secrets.foo", hashA, "secret", nil) {
+		t.Error("expected the diff to be suppressed with the key of the provider")
+	}
+	if hookSecrets(b).DiffSuppressFunc("secrets.foo", hashA, "secret", nil) {
+		t.Error("expected the diff not to be suppressed with the key of another provider")
+	}
+}
+
 func TestProvider_debugDefaults(t *testing.T) {
 	for value, expected := range map[string]bool{
 		"1":     true,
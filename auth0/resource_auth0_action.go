@@ -1,11 +1,17 @@
 package auth0
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/hash"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -13,7 +19,7 @@ import (
 	"gopkg.in/auth0.v5/management"
 )
 
-func newAction() *schema.Resource {
+func newAction(secrets *secretHasher) *schema.Resource {
 	return &schema.Resource{
 
 		Create: createAction,
@@ -23,6 +29,7 @@ func newAction() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: customdiff.All(validateAction, diffActionCode),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -34,7 +41,6 @@ func newAction() *schema.Resource {
 				Type:     schema.TypeList,
 				Required: true,
 				MinItems: 1,
-				MaxItems: 1, // NOTE: Changes must be made together with expandAction()
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": {
@@ -49,14 +55,22 @@ func newAction() *schema.Resource {
 						},
 					},
 				},
-				Description: "List of triggers that this action supports. At " +
-					"this time, an action can only target a single trigger at" +
-					" a time",
+				Description: "List of triggers that this action supports. An " +
+					"action can only target more than one trigger if the " +
+					"triggers are compatible with each other",
 			},
 			"code": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The source code of the action.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"code", "code_path"},
+				Description:  "The source code of the action.",
+			},
+			"code_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "Path of a file containing the source code of the " +
+					"action, as an alternative to `code`. The file is read when planning",
 			},
 			"dependencies": {
 				Type:     schema.TypeSet,
@@ -101,10 +115,11 @@ func newAction() *schema.Resource {
 							Description: "Secret name",
 						},
 						"value": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Sensitive:   true,
-							Description: "Secret value",
+							Type:             schema.TypeString,
+							Required:         true,
+							Sensitive:        true,
+							DiffSuppressFunc: suppressActionSecretDiff(secrets),
+							Description:      "Secret value",
 						},
 					},
 				},
@@ -119,18 +134,163 @@ func newAction() *schema.Resource {
 					" to a trigger, then the system will begin executing the " +
 					"newly deployed version of the action immediately",
 			},
+			"deployed_version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "ID of a version of the action to deploy. This can " +
+					"be used to pin the action to, or roll back to, an earlier " +
+					"version. Can't be used together with `deploy`",
+			},
 			"version_id": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Version ID of the action. This value is available if `deploy` is set to true",
 			},
+			"deployed_code_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "SHA-256 hash of the code of the deployed version of " +
+					"the action, which can be used to detect changes made outside of Terraform",
+			},
+			"versions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Version ID",
+						},
+						"number": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Version number",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Build status of the version",
+						},
+						"deployed": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the version is the one currently deployed",
+						},
+						"runtime": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Node runtime of the version",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creation time of the version",
+						},
+					},
+				},
+				Description: "Latest versions of the action, most recent first. " +
+					"At most 10 versions are listed",
+			},
 		},
 	}
 }
 
+// actionVersion is a version of an action, along with the runtime it was built
+// with, which the management.ActionVersion type doesn't expose.
+type actionVersion struct {
+	management.ActionVersion
+	Runtime *string `json:"runtime,omitempty"`
+}
+
+type actionVersionList struct {
+	management.List
+	Versions []*actionVersion `json:"versions"`
+}
+
+// actionTriggerList lists the triggers available to actions, along with the
+// triggers each of them is compatible with.
+type actionTriggerList struct {
+	Triggers []*struct {
+		ID                 string `json:"id"`
+		Version            string `json:"version"`
+		CompatibleTriggers []*struct {
+			ID      string `json:"id"`
+			Version string `json:"version"`
+		} `json:"compatible_triggers"`
+	} `json:"triggers"`
+}
+
+func validateAction(d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("deploy").(bool) && d.Get("deployed_version").(string) != "" {
+		return fmt.Errorf("only one of deploy or deployed_version can be set")
+	}
+
+	if !d.NewValueKnown("supported_triggers") {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, t := range d.Get("supported_triggers").([]interface{}) {
+		trigger := t.(map[string]interface{})
+		id := trigger["id"].(string)
+		if seen[id] {
+			return fmt.Errorf("supported_triggers: trigger %q is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// diffActionCode reads the code of the action from code_path, and plans a new
+// deployment when deploy is set and the code, dependencies, runtime or secrets
+// change, or when the deployed code differs from the code of the action.
+func diffActionCode(d *schema.ResourceDiff, m interface{}) error {
+	if path, ok := d.GetOk("code_path"); ok {
+		if !d.NewValueKnown("code_path") {
+			return d.SetNewComputed("code")
+		}
+		code, err := ioutil.ReadFile(path.(string))
+		if err != nil {
+			return fmt.Errorf("failed to read code_path: %w", err)
+		}
+		if err := d.SetNew("code", string(code)); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() == "" || !d.Get("deploy").(bool) {
+		return nil
+	}
+
+	changed := d.HasChange("code") || d.HasChange("dependencies") ||
+		d.HasChange("runtime") || d.HasChange("secrets") || d.HasChange("deploy")
+	if !changed && d.NewValueKnown("code") {
+		o, _ := d.GetChange("deployed_code_hash")
+		changed = o.(string) != actionCodeHash(d.Get("code").(string))
+	}
+	if !changed {
+		return nil
+	}
+
+	for _, key := range []string{"version_id", "deployed_code_hash", "versions"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func actionCodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
 func createAction(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	a := expandAction(d)
+	api := m.(*config).api
+	a := expandAction(d, m.(*config).secrets)
+	if err := checkActionTriggers(api, a.SupportedTriggers); err != nil {
+		return err
+	}
 	err := api.Action.Create(a)
 	if err != nil {
 		return err
@@ -142,13 +302,17 @@ func createAction(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
+	err = deployActionVersion(d, m)
+	if err != nil {
+		return err
+	}
 	d.Partial(false)
 
 	return readAction(d, m)
 }
 
 func readAction(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	a, err := api.Action.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -166,16 +330,59 @@ func readAction(d *schema.ResourceData, m interface{}) error {
 	d.Set("dependencies", flattenActionDependencies(a.Dependencies))
 	d.Set("runtime", a.Runtime)
 
+	// Secret values are never returned, so they can only be read back when
+	// they are stored as hashes, in which case removed or renamed secrets are
+	// detected by name.
+	if hasher := m.(*config).secrets; hasher.enabled() {
+		d.Set("secrets", flattenActionSecretHashes(hasher, a.Secrets, d.Get("secrets").([]interface{})))
+	}
+
 	if a.DeployedVersion != nil {
 		d.Set("version_id", a.DeployedVersion.GetID())
 	}
 
+	versions, err := listActionVersions(api, d.Id())
+	if err != nil {
+		return err
+	}
+	d.Set("versions", flattenActionVersions(versions))
+
+	if a.DeployedVersion != nil && a.DeployedVersion.Code == nil {
+		v, err := findActionVersion(api, d.Id(), a.DeployedVersion.GetID(), versions)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			a.DeployedVersion.Code = v.Code
+		}
+	}
+	if a.DeployedVersion != nil {
+		d.Set("deployed_code_hash", actionCodeHash(a.DeployedVersion.GetCode()))
+	}
+
+	if pinned := d.Get("deployed_version").(string); pinned != "" && a.DeployedVersion != nil {
+		// The pinned version may be older than the versions listed.
+		v, err := findActionVersion(api, d.Id(), pinned, versions)
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			versions = append(versions, v)
+		}
+		d.Set("deployed_version", pinnedActionVersion(pinned, a.DeployedVersion, versions))
+	}
+
 	return nil
 }
 
 func updateAction(d *schema.ResourceData, m interface{}) error {
-	a := expandAction(d)
-	api := m.(*management.Management)
+	a := expandAction(d, m.(*config).secrets)
+	api := m.(*config).api
+	if d.HasChange("supported_triggers") {
+		if err := checkActionTriggers(api, a.SupportedTriggers); err != nil {
+			return err
+		}
+	}
 	err := api.Action.Update(d.Id(), a)
 	if err != nil {
 		return err
@@ -185,6 +392,10 @@ func updateAction(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
+	err = deployActionVersion(d, m)
+	if err != nil {
+		return err
+	}
 	d.Partial(false)
 	return readAction(d, m)
 }
@@ -193,7 +404,7 @@ func deployAction(d *schema.ResourceData, m interface{}) error {
 
 	if d.Get("deploy").(bool) == true {
 
-		api := m.(*management.Management)
+		api := m.(*config).api
 
 		err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 
@@ -224,8 +435,120 @@ func deployAction(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// deployActionVersion deploys the version set by deployed_version, if it isn't
+// already deployed.
+func deployActionVersion(d *schema.ResourceData, m interface{}) error {
+	version := d.Get("deployed_version").(string)
+	if version == "" || !(d.IsNewResource() || d.HasChange("deployed_version")) {
+		return nil
+	}
+
+	api := m.(*config).api
+	v, err := api.Action.DeployVersion(d.Id(), version)
+	if err != nil {
+		return err
+	}
+
+	d.Set("version_id", v.GetID())
+	return nil
+}
+
+// pinnedActionVersion returns the version to store as deployed_version, given
+// the version which is pinned and the one which is actually deployed.
+//
+// Deploying an earlier version creates a new version with the same code and
+// dependencies, so the pinned version is considered deployed as long as the
+// deployed version has the same contents.
+func pinnedActionVersion(pinned string, deployed *management.ActionVersion, versions []*actionVersion) string {
+	if deployed.GetID() == pinned {
+		return pinned
+	}
+	for _, v := range versions {
+		if v.GetID() != pinned {
+			continue
+		}
+		if v.GetCode() == deployed.GetCode() &&
+			reflect.DeepEqual(flattenActionDependencies(v.Dependencies), flattenActionDependencies(deployed.Dependencies)) {
+			return pinned
+		}
+	}
+	return deployed.GetID()
+}
+
+// checkActionTriggers returns an error if an action targets more than one
+// trigger and the triggers are not compatible with each other.
+func checkActionTriggers(api *management.Management, triggers []*management.ActionTrigger) error {
+	if len(triggers) < 2 {
+		return nil
+	}
+	var available actionTriggerList
+	if err := api.Request(http.MethodGet, api.URI("actions", "triggers"), &available); err != nil {
+		return err
+	}
+	return incompatibleActionTriggers(&available, triggers)
+}
+
+func incompatibleActionTriggers(available *actionTriggerList, triggers []*management.ActionTrigger) error {
+	compatible := make(map[string]map[string]bool)
+	for _, t := range available.Triggers {
+		key := t.ID + "/" + t.Version
+		compatible[key] = make(map[string]bool)
+		for _, c := range t.CompatibleTriggers {
+			compatible[key][c.ID+"/"+c.Version] = true
+		}
+	}
+
+	for i, a := range triggers {
+		for _, b := range triggers[i+1:] {
+			ka := a.GetID() + "/" + a.GetVersion()
+			kb := b.GetID() + "/" + b.GetVersion()
+			if !compatible[ka][kb] && !compatible[kb][ka] {
+				return fmt.Errorf("trigger %s (%s) is not compatible with trigger %s (%s), "+
+					"an action can only target more than one trigger if they are compatible",
+					a.GetID(), a.GetVersion(), b.GetID(), b.GetVersion())
+			}
+		}
+	}
+	return nil
+}
+
+// actionVersionsLimit is the number of versions listed in the versions
+// attribute. Every deploy creates a version, so only the latest ones are kept.
+const actionVersionsLimit = 10
+
+// listActionVersions returns the latest versions of an action, most recent
+// first.
+func listActionVersions(api *management.Management, id string) ([]*actionVersion, error) {
+	var l actionVersionList
+	err := api.Request(http.MethodGet, api.URI("actions", "actions", id, "versions"), &l, management.Page(0), management.PerPage(actionVersionsLimit))
+	if err != nil {
+		return nil, err
+	}
+	return l.Versions, nil
+}
+
+// findActionVersion returns the version of an action with the given ID,
+// looking it up in versions first, and reading it when it isn't listed. It
+// returns nil if the version doesn't exist.
+func findActionVersion(api *management.Management, id, versionID string, versions []*actionVersion) (*actionVersion, error) {
+	for _, v := range versions {
+		if v.GetID() == versionID {
+			return v, nil
+		}
+	}
+	var v actionVersion
+	err := api.Request(http.MethodGet, api.URI("actions", "actions", id, "versions", versionID), &v)
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok && mErr.Status() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
 func deleteAction(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Action.Delete(d.Id()); err != nil {
 		if mErr, ok := err.(management.Error); ok {
 			if mErr.Status() == http.StatusNotFound {
@@ -238,7 +561,7 @@ func deleteAction(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func expandAction(d *schema.ResourceData) *management.Action {
+func expandAction(d *schema.ResourceData, secrets *secretHasher) *management.Action {
 
 	a := &management.Action{
 		Name:    String(d, "name"),
@@ -247,12 +570,10 @@ func expandAction(d *schema.ResourceData) *management.Action {
 	}
 
 	List(d, "supported_triggers").Elem(func(d ResourceData) {
-		a.SupportedTriggers = []*management.ActionTrigger{
-			{
-				ID:      String(d, "id"),
-				Version: String(d, "version"),
-			},
-		}
+		a.SupportedTriggers = append(a.SupportedTriggers, &management.ActionTrigger{
+			ID:      String(d, "id"),
+			Version: String(d, "version"),
+		})
 	})
 
 	Set(d, "dependencies").Elem(func(d ResourceData) {
@@ -262,12 +583,16 @@ func expandAction(d *schema.ResourceData) *management.Action {
 		})
 	})
 
-	List(d, "secrets").Elem(func(d ResourceData) {
-		a.Secrets = append(a.Secrets, &management.ActionSecret{
-			Name:  String(d, "name"),
-			Value: String(d, "value"),
+	// Secrets whose hash is stored in state are sent without a value, which
+	// keeps their current value.
+	if !secrets.enabled() || d.IsNewResource() || d.HasChange("secrets") {
+		List(d, "secrets").Elem(func(d ResourceData) {
+			a.Secrets = append(a.Secrets, &management.ActionSecret{
+				Name:  String(d, "name"),
+				Value: Secret(d, "value"),
+			})
 		})
-	})
+	}
 
 	return a
 }
@@ -292,12 +617,70 @@ func flattenActionDependencies(dependencies []*management.ActionDependency) (ret
 	return
 }
 
-func flattenActionSecrets(secrets []*management.ActionSecret) (ret []interface{}) {
-	for _, secret := range secrets {
+func flattenActionVersions(versions []*actionVersion) (ret []interface{}) {
+	for _, v := range versions {
+		var createdAt string
+		if v.CreatedAt != nil {
+			createdAt = v.CreatedAt.Format(time.RFC3339)
+		}
 		ret = append(ret, map[string]interface{}{
-			"name":  secret.Name,
-			"value": secret.Value,
+			"id":         v.GetID(),
+			"number":     v.Number,
+			"status":     v.GetStatus(),
+			"deployed":   v.Deployed,
+			"runtime":    v.Runtime,
+			"created_at": createdAt,
 		})
 	}
 	return
 }
+
+// flattenActionSecretHashes flattens the secrets of an action, whose values
+// are not returned, using the hash, made with hasher, of the value found in
+// prior for the secret of the same name. Secrets which are not in prior get an
+// empty value.
+func flattenActionSecretHashes(hasher *secretHasher, secrets []*management.ActionSecret, prior []interface{}) (ret []interface{}) {
+	values := make(map[string]string)
+	var names []string
+	for _, p := range prior {
+		secret, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := secret["name"].(string)
+		values[name] = secret["value"].(string)
+		names = append(names, name)
+	}
+
+	exists := make(map[string]bool)
+	for _, secret := range secrets {
+		exists[secret.GetName()] = true
+	}
+
+	for _, name := range names {
+		if exists[name] {
+			ret = append(ret, map[string]interface{}{
+				"name":  name,
+				"value": hasher.hash(values[name]),
+			})
+			delete(exists, name)
+		}
+	}
+	for _, secret := range secrets {
+		if exists[secret.GetName()] {
+			ret = append(ret, map[string]interface{}{
+				"name":  secret.GetName(),
+				"value": "",
+			})
+		}
+	}
+	return
+}
+
+// suppressActionSecretDiff suppresses the diff of the value of a secret whose
+// hash is unchanged, unless the secret is renamed.
+func suppressActionSecretDiff(secrets *secretHasher) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		return secrets.suppressDiff(k, old, new, d) && !d.HasChange(strings.TrimSuffix(k, ".value")+".name")
+	}
+}
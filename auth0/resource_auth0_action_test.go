@@ -1,11 +1,21 @@
 package auth0
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/random"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
 )
 
 func TestAccAction(t *testing.T) {
@@ -40,8 +50,15 @@ func TestAccAction(t *testing.T) {
 					random.TestCheckResourceAttr("auth0_action.my_action", "name", "Test Action {{.random}}", rand),
 					resource.TestCheckResourceAttrSet("auth0_action.my_action", "version_id"),
 					resource.TestCheckResourceAttr("auth0_action.my_action", "secrets.#", "0"),
+					resource.TestCheckResourceAttr("auth0_action.my_action", "versions.#", "2"),
+					resource.TestCheckResourceAttr("auth0_action.my_action", "versions.0.deployed", "true"),
+					resource.TestCheckResourceAttr("auth0_action.my_action", "versions.0.status", "built"),
 				),
 			},
+			{
+				Config:      random.Template(testAccActionConfigDeployConflict, rand),
+				ExpectError: regexp.MustCompile("only one of deploy or deployed_version can be set"),
+			},
 		},
 	})
 }
@@ -95,3 +112,205 @@ resource auth0_action my_action {
 	deploy = true
 }
 `
+
+const testAccActionConfigDeployConflict = `
+
+resource auth0_action my_action {
+	name = "Test Action {{.random}}"
+	supported_triggers {
+		id = "post-login"
+		version = "v2"
+	}
+	code = "exports.onContinuePostLogin = async (event, api) => {};"
+	deploy = true
+	deployed_version = "00000000-0000-0000-0000-000000000000"
+}
+`
+
+func TestAccActionCodePath(t *testing.T) {
+
+	rand := random.String(6)
+
+	f, err := ioutil.TempFile("", "action-*.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	writeCode := func(code string) func() {
+		return func() {
+			if err := ioutil.WriteFile(f.Name(), []byte(code), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	config := strings.Replace(random.Template(testAccActionConfigCodePath, rand), "CODE_PATH", f.Name(), 1)
+	codeV1 := "exports.onExecutePostLogin = async (event, api) => {};"
+	codeV2 := "exports.onExecutePostLogin = async (event, api) => { console.log(event); };"
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: writeCode(codeV1),
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_action.my_action", "code", codeV1),
+					resource.TestCheckResourceAttr("auth0_action.my_action", "deployed_code_hash", actionCodeHash(codeV1)),
+				),
+			},
+			{
+				PreConfig: writeCode(codeV2),
+				Config:    config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_action.my_action", "code", codeV2),
+					resource.TestCheckResourceAttr("auth0_action.my_action", "deployed_code_hash", actionCodeHash(codeV2)),
+					resource.TestCheckResourceAttr("auth0_action.my_action", "versions.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccActionConfigCodePath = `
+
+resource auth0_action my_action {
+	name = "Test Action Code Path {{.random}}"
+	supported_triggers {
+		id = "post-login"
+		version = "v2"
+	}
+	code_path = "CODE_PATH"
+	deploy = true
+}
+`
+
+func TestActionCodeHash(t *testing.T) {
+	expected := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if h := actionCodeHash(""); h != expected {
+		t.Errorf("expected %q, got %q", expected, h)
+	}
+}
+
+func TestPinnedActionVersion(t *testing.T) {
+	versions := []*actionVersion{
+		{ActionVersion: management.ActionVersion{ID: auth0.String("v3"), Code: auth0.String("b")}},
+		{ActionVersion: management.ActionVersion{ID: auth0.String("v2"), Code: auth0.String("a")}},
+		{ActionVersion: management.ActionVersion{ID: auth0.String("v1"), Code: auth0.String("a")}},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		pinned   string
+		deployed *management.ActionVersion
+		expected string
+	}{
+		{"Deployed", "v2", &management.ActionVersion{ID: auth0.String("v2"), Code: auth0.String("a")}, "v2"},
+		{"RolledBack", "v1", &management.ActionVersion{ID: auth0.String("v2"), Code: auth0.String("a")}, "v1"},
+		{"Drifted", "v1", &management.ActionVersion{ID: auth0.String("v3"), Code: auth0.String("b")}, "v3"},
+		{"Unknown", "v0", &management.ActionVersion{ID: auth0.String("v3"), Code: auth0.String("b")}, "v3"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if v := pinnedActionVersion(tt.pinned, tt.deployed, versions); v != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, v)
+			}
+		})
+	}
+}
+
+func TestActionVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/actions/actions/act_1/versions":
+			if q := r.URL.Query(); q.Get("page") != "0" || q.Get("per_page") != "10" {
+				t.Errorf("expected the first 10 versions to be requested, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"versions": [{"id": "v12", "code": "c"}, {"id": "v11", "code": "b"}], "total": 12}`))
+		case "/api/v2/actions/actions/act_1/versions/v1":
+			w.Write([]byte(`{"id": "v1", "code": "a"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"statusCode": 404, "message": "Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	api, err := management.New(strings.TrimPrefix(server.URL, "http://"), management.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := listActionVersions(api, "act_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versions))
+	}
+
+	for _, tt := range []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"Listed", "v11", "b"},
+		{"NotListed", "v1", "a"},
+		{"Missing", "v0", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := findActionVersion(api, "act_1", tt.id, versions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.expected == "" {
+				if v != nil {
+					t.Errorf("expected no version, got %s", v.GetID())
+				}
+				return
+			}
+			if v == nil {
+				t.Fatalf("expected version %s, got none", tt.id)
+			}
+			if code := v.GetCode(); code != tt.expected {
+				t.Errorf("expected code %q, got %q", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestIncompatibleActionTriggers(t *testing.T) {
+	var available actionTriggerList
+	err := json.Unmarshal([]byte(`{"triggers": [
+		{"id": "post-login", "version": "v3", "compatible_triggers": [{"id": "credentials-exchange", "version": "v2"}]},
+		{"id": "credentials-exchange", "version": "v2"},
+		{"id": "send-phone-message", "version": "v2"}
+	]}`), &available)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trigger := func(id, version string) *management.ActionTrigger {
+		return &management.ActionTrigger{ID: auth0.String(id), Version: auth0.String(version)}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		triggers []*management.ActionTrigger
+		err      bool
+	}{
+		{"Single", []*management.ActionTrigger{trigger("send-phone-message", "v2")}, false},
+		{"Compatible", []*management.ActionTrigger{trigger("credentials-exchange", "v2"), trigger("post-login", "v3")}, false},
+		{"Incompatible", []*management.ActionTrigger{trigger("post-login", "v3"), trigger("send-phone-message", "v2")}, true},
+		{"VersionMismatch", []*management.ActionTrigger{trigger("post-login", "v2"), trigger("credentials-exchange", "v2")}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := incompatibleActionTriggers(&available, tt.triggers)
+			if (err != nil) != tt.err {
+				t.Errorf("expected error %t, got %v", tt.err, err)
+			}
+		})
+	}
+}
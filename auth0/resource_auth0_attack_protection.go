@@ -0,0 +1,319 @@
+package auth0
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	"gopkg.in/auth0.v5/management"
+)
+
+func newAttackProtection() *schema.Resource {
+	return &schema.Resource{
+
+		Create: createAttackProtection,
+		Read:   readAttackProtection,
+		Update: updateAttackProtection,
+		Delete: deleteAttackProtection,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"breached_password_detection": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Whether or not breached password detection is active",
+						},
+						"shields": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{
+									"block",
+									"user_notification",
+									"admin_notification",
+								}, false),
+							},
+							Description: "Action to take when a breached password is detected. " +
+								"Options include `block`, `user_notification` and `admin_notification`",
+						},
+						"admin_notification_frequency": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{
+									"immediately",
+									"daily",
+									"weekly",
+									"monthly",
+								}, false),
+							},
+							Description: "When `admin_notification` is enabled, determines how often email notifications are sent. " +
+								"Options include `immediately`, `daily`, `weekly` and `monthly`",
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"standard",
+								"enhanced",
+							}, false),
+							Description: "The subscription level for breached password detection methods. " +
+								"Use `enhanced` to enable Credential Guard",
+						},
+					},
+				},
+				Description: "Breached password detection protects your applications from bad actors logging in with stolen credentials",
+			},
+			"brute_force_protection": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Whether or not brute force protection is active",
+						},
+						"shields": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{
+									"block",
+									"user_notification",
+								}, false),
+							},
+							Description: "Action to take when a brute force protection threshold is violated. " +
+								"Options include `block` and `user_notification`",
+						},
+						"mode": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"count_per_identifier_and_ip",
+								"count_per_identifier",
+							}, false),
+							Description: "Account lockout: determines whether or not IP address is used when counting failed attempts. " +
+								"Options include `count_per_identifier_and_ip` and `count_per_identifier`",
+						},
+						"max_attempts": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Maximum number of unsuccessful attempts",
+						},
+						"allowlist": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of trusted IP addresses that will not have attack protection enforced against them",
+						},
+					},
+				},
+				Description: "Brute force protection safeguards against a single IP address attacking a single user account",
+			},
+			"suspicious_ip_throttling": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Whether or not suspicious IP throttling attack protections are active",
+						},
+						"shields": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateFunc: validation.StringInSlice([]string{
+									"block",
+									"admin_notification",
+								}, false),
+							},
+							Description: "Action to take when a suspicious IP throttling threshold is violated. " +
+								"Options include `block` and `admin_notification`",
+						},
+						"allowlist": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "List of trusted IP addresses that will not have attack protection enforced against them",
+						},
+						"pre_login": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Computed:    true,
+							MaxItems:    1,
+							Elem:        attackProtectionStageSchema(),
+							Description: "Configuration options that apply before every login attempt",
+						},
+						"pre_user_registration": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Computed:    true,
+							MaxItems:    1,
+							Elem:        attackProtectionStageSchema(),
+							Description: "Configuration options that apply before every user registration attempt",
+						},
+					},
+				},
+				Description: "Suspicious IP throttling blocks traffic from any IP address that rapidly attempts too many logins or signups",
+			},
+		},
+	}
+}
+
+func attackProtectionStageSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"max_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Total number of attempts allowed",
+			},
+			"rate": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Interval of time, given in milliseconds, at which new attempts are granted",
+			},
+		},
+	}
+}
+
+// The following types model the attack protection endpoints of the Management
+// API, which are not yet available in the management package.
+
+type attackProtectionBreachedPasswordDetection struct {
+	Enabled                    *bool     `json:"enabled,omitempty"`
+	Shields                    *[]string `json:"shields,omitempty"`
+	AdminNotificationFrequency *[]string `json:"admin_notification_frequency,omitempty"`
+	Method                     *string   `json:"method,omitempty"`
+}
+
+type attackProtectionBruteForceProtection struct {
+	Enabled     *bool     `json:"enabled,omitempty"`
+	Shields     *[]string `json:"shields,omitempty"`
+	AllowList   *[]string `json:"allowlist,omitempty"`
+	Mode        *string   `json:"mode,omitempty"`
+	MaxAttempts *int      `json:"max_attempts,omitempty"`
+}
+
+type attackProtectionSuspiciousIPThrottling struct {
+	Enabled   *bool                                       `json:"enabled,omitempty"`
+	Shields   *[]string                                   `json:"shields,omitempty"`
+	AllowList *[]string                                   `json:"allowlist,omitempty"`
+	Stage     map[string]*attackProtectionThrottlingStage `json:"stage,omitempty"`
+}
+
+type attackProtectionThrottlingStage struct {
+	MaxAttempts *int `json:"max_attempts,omitempty"`
+	Rate        *int `json:"rate,omitempty"`
+}
+
+const (
+	attackProtectionStagePreLogin            = "pre-login"
+	attackProtectionStagePreUserRegistration = "pre-user-registration"
+)
+
+func createAttackProtection(d *schema.ResourceData, m interface{}) error {
+	d.SetId(resource.UniqueId())
+	return updateAttackProtection(d, m)
+}
+
+func readAttackProtection(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+
+	bpd := &attackProtectionBreachedPasswordDetection{}
+	err := api.Request(http.MethodGet, api.URI("attack-protection", "breached-password-detection"), bpd)
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	bfp := &attackProtectionBruteForceProtection{}
+	err = api.Request(http.MethodGet, api.URI("attack-protection", "brute-force-protection"), bfp)
+	if err != nil {
+		return err
+	}
+
+	sit := &attackProtectionSuspiciousIPThrottling{}
+	err = api.Request(http.MethodGet, api.URI("attack-protection", "suspicious-ip-throttling"), sit)
+	if err != nil {
+		return err
+	}
+
+	d.Set("breached_password_detection", flattenAttackProtectionBreachedPasswordDetection(bpd))
+	d.Set("brute_force_protection", flattenAttackProtectionBruteForceProtection(bfp))
+	d.Set("suspicious_ip_throttling", flattenAttackProtectionSuspiciousIPThrottling(sit))
+	return nil
+}
+
+func updateAttackProtection(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+
+	if bpd := expandAttackProtectionBreachedPasswordDetection(d); bpd != nil {
+		err := api.Request(http.MethodPatch, api.URI("attack-protection", "breached-password-detection"), bpd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if bfp := expandAttackProtectionBruteForceProtection(d); bfp != nil {
+		err := api.Request(http.MethodPatch, api.URI("attack-protection", "brute-force-protection"), bfp)
+		if err != nil {
+			return err
+		}
+	}
+
+	if sit := expandAttackProtectionSuspiciousIPThrottling(d); sit != nil {
+		err := api.Request(http.MethodPatch, api.URI("attack-protection", "suspicious-ip-throttling"), sit)
+		if err != nil {
+			return err
+		}
+	}
+
+	return readAttackProtection(d, m)
+}
+
+func deleteAttackProtection(d *schema.ResourceData, m interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,101 @@
+package auth0
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccAttackProtection(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAttackProtectionCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "breached_password_detection.0.enabled", "true"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "breached_password_detection.0.shields.#", "2"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "breached_password_detection.0.admin_notification_frequency.#", "1"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "breached_password_detection.0.method", "standard"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.enabled", "true"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.mode", "count_per_identifier_and_ip"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.max_attempts", "5"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.allowlist.#", "1"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.enabled", "true"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.shields.#", "2"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.pre_login.0.max_attempts", "100"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.pre_login.0.rate", "864000"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.pre_user_registration.0.max_attempts", "50"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.pre_user_registration.0.rate", "1200"),
+				),
+			},
+			{
+				Config: testAccAttackProtectionUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "breached_password_detection.0.enabled", "false"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.mode", "count_per_identifier"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.max_attempts", "10"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "brute_force_protection.0.allowlist.#", "0"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.shields.#", "1"),
+					resource.TestCheckResourceAttr("auth0_attack_protection.attack_protection", "suspicious_ip_throttling.0.allowlist.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccAttackProtectionCreate = `
+
+resource "auth0_attack_protection" "attack_protection" {
+	breached_password_detection {
+		enabled = true
+		shields = ["block", "admin_notification"]
+		admin_notification_frequency = ["daily"]
+		method = "standard"
+	}
+	brute_force_protection {
+		enabled = true
+		shields = ["block", "user_notification"]
+		mode = "count_per_identifier_and_ip"
+		max_attempts = 5
+		allowlist = ["127.0.0.1"]
+	}
+	suspicious_ip_throttling {
+		enabled = true
+		shields = ["block", "admin_notification"]
+		pre_login {
+			max_attempts = 100
+			rate = 864000
+		}
+		pre_user_registration {
+			max_attempts = 50
+			rate = 1200
+		}
+	}
+}
+`
+
+const testAccAttackProtectionUpdate = `
+
+resource "auth0_attack_protection" "attack_protection" {
+	breached_password_detection {
+		enabled = false
+	}
+	brute_force_protection {
+		enabled = true
+		shields = ["block"]
+		mode = "count_per_identifier"
+		max_attempts = 10
+		allowlist = []
+	}
+	suspicious_ip_throttling {
+		enabled = true
+		shields = ["block"]
+		allowlist = ["127.0.0.1", "10.0.0.0/8"]
+	}
+}
+`
@@ -18,6 +18,7 @@ func newBranding() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateBranding,
 		Schema: map[string]*schema.Schema{
 			"colors": {
 				Type:     schema.TypeList,
@@ -69,9 +70,11 @@ func newBranding() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"body": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateLiquidWarnings(universalLoginHeadTag, universalLoginWidgetTag),
+							Description:  "Liquid template of the login pages, which must contain the {%- auth0:head -%} and {%- auth0:widget -%} tags",
 						},
 					},
 				},
@@ -80,13 +83,28 @@ func newBranding() *schema.Resource {
 	}
 }
 
+// validateBranding checks the Liquid syntax of the Universal Login template,
+// and that it holds the tags required by Auth0.
+func validateBranding(d *schema.ResourceDiff, m interface{}) error {
+	const key = "universal_login.0.body"
+	if !d.NewValueKnown(key) {
+		return nil
+	}
+	body, _ := d.Get(key).(string)
+	if body == "" {
+		return nil
+	}
+	_, err := parseUniversalLoginTemplate(key, body)
+	return err
+}
+
 func createBranding(d *schema.ResourceData, m interface{}) error {
 	d.SetId(resource.UniqueId())
 	return updateBranding(d, m)
 }
 
 func readBranding(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	branding, err := api.Branding.Read()
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -119,7 +137,7 @@ func readBranding(d *schema.ResourceData, m interface{}) error {
 }
 
 func updateBranding(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 
 	branding := buildBranding(d)
 	if err := api.Branding.Update(branding); err != nil {
@@ -137,7 +155,7 @@ func updateBranding(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteBranding(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	tenant, err := api.Tenant.Read()
 	if err != nil {
 		return err
@@ -190,7 +208,7 @@ func buildBrandingUniversalLogin(d *schema.ResourceData) *management.BrandingUni
 }
 
 func setUniversalLogin(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	universalLogin, err := api.Branding.UniversalLogin()
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
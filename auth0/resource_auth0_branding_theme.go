@@ -0,0 +1,445 @@
+package auth0
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	v "github.com/alexkappa/terraform-provider-auth0/auth0/internal/validation"
+	"gopkg.in/auth0.v5/management"
+)
+
+func newBrandingTheme() *schema.Resource {
+	return &schema.Resource{
+
+		Create: createBrandingTheme,
+		Read:   readBrandingTheme,
+		Update: updateBrandingTheme,
+		Delete: deleteBrandingTheme,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The display name of the theme",
+			},
+			"borders": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"button_border_radius": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      3.0,
+							ValidateFunc: validation.FloatBetween(1, 10),
+							Description:  "Button border radius",
+						},
+						"button_border_weight": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      1.0,
+							ValidateFunc: validation.FloatBetween(0, 10),
+							Description:  "Button border weight",
+						},
+						"buttons_style": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "rounded",
+							ValidateFunc: validation.StringInSlice(brandingThemeStyles, false),
+							Description:  "Buttons style. Options include `pill`, `rounded` and `sharp`",
+						},
+						"input_border_radius": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      3.0,
+							ValidateFunc: validation.FloatBetween(0, 10),
+							Description:  "Input border radius",
+						},
+						"input_border_weight": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      1.0,
+							ValidateFunc: validation.FloatBetween(0, 3),
+							Description:  "Input border weight",
+						},
+						"inputs_style": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "rounded",
+							ValidateFunc: validation.StringInSlice(brandingThemeStyles, false),
+							Description:  "Inputs style. Options include `pill`, `rounded` and `sharp`",
+						},
+						"show_widget_shadow": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether or not to show the widget shadow",
+						},
+						"widget_border_weight": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      0.0,
+							ValidateFunc: validation.FloatBetween(0, 10),
+							Description:  "Widget border weight",
+						},
+						"widget_corner_radius": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      5.0,
+							ValidateFunc: validation.FloatBetween(0, 50),
+							Description:  "Widget corner radius",
+						},
+					},
+				},
+			},
+			"colors": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"base_focus_color":          brandingThemeColorSchema("#635dff", "Base focus color"),
+						"base_hover_color":          brandingThemeColorSchema("#000000", "Base hover color"),
+						"body_text":                 brandingThemeColorSchema("#1e212a", "Body text"),
+						"error":                     brandingThemeColorSchema("#d03c38", "Error"),
+						"header":                    brandingThemeColorSchema("#1e212a", "Header"),
+						"icons":                     brandingThemeColorSchema("#65676e", "Icons"),
+						"input_background":          brandingThemeColorSchema("#ffffff", "Input background"),
+						"input_border":              brandingThemeColorSchema("#c9cace", "Input border"),
+						"input_filled_text":         brandingThemeColorSchema("#000000", "Input filled text"),
+						"input_labels_placeholders": brandingThemeColorSchema("#65676e", "Input labels and placeholders"),
+						"links_focused_components":  brandingThemeColorSchema("#635dff", "Links and focused components"),
+						"primary_button":            brandingThemeColorSchema("#635dff", "Primary button"),
+						"primary_button_label":      brandingThemeColorSchema("#ffffff", "Primary button label"),
+						"secondary_button_border":   brandingThemeColorSchema("#c9cace", "Secondary button border"),
+						"secondary_button_label":    brandingThemeColorSchema("#1e212a", "Secondary button label"),
+						"success":                   brandingThemeColorSchema("#13a688", "Success"),
+						"widget_background":         brandingThemeColorSchema("#ffffff", "Widget background"),
+						"widget_border":             brandingThemeColorSchema("#c9cace", "Widget border"),
+					},
+				},
+			},
+			"fonts": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"font_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "URL of the font used in the widget",
+						},
+						"links_style": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "normal",
+							ValidateFunc: validation.StringInSlice([]string{
+								"normal",
+								"underlined",
+							}, false),
+							Description: "Links style. Options include `normal` and `underlined`",
+						},
+						"reference_text_size": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      16.0,
+							ValidateFunc: validation.FloatBetween(12, 24),
+							Description:  "Reference text size, in pixels, that other font sizes are relative to",
+						},
+						"body_text":    brandingThemeTextSchema(87.5, false, "Body text"),
+						"buttons_text": brandingThemeTextSchema(100, false, "Buttons text"),
+						"input_labels": brandingThemeTextSchema(100, false, "Input labels"),
+						"links":        brandingThemeTextSchema(87.5, true, "Links"),
+						"subtitle":     brandingThemeTextSchema(87.5, false, "Subtitle"),
+						"title":        brandingThemeTextSchema(150, false, "Title"),
+					},
+				},
+			},
+			"page_background": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"background_color": brandingThemeColorSchema("#000000", "Background color"),
+						"background_image_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "URL of the background image",
+						},
+						"page_layout": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "center",
+							ValidateFunc: validation.StringInSlice(brandingThemeAlignments, false),
+							Description:  "Page layout. Options include `center`, `left` and `right`",
+						},
+					},
+				},
+			},
+			"widget": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"header_text_alignment": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "center",
+							ValidateFunc: validation.StringInSlice(brandingThemeAlignments, false),
+							Description:  "Header text alignment. Options include `center`, `left` and `right`",
+						},
+						"logo_height": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      52.0,
+							ValidateFunc: validation.FloatBetween(1, 100),
+							Description:  "Logo height, in pixels",
+						},
+						"logo_position": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "center",
+							ValidateFunc: validation.StringInSlice([]string{
+								"center",
+								"left",
+								"right",
+								"none",
+							}, false),
+							Description: "Logo position. Options include `center`, `left`, `right` and `none`",
+						},
+						"logo_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "URL of the logo",
+						},
+						"social_buttons_layout": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "bottom",
+							ValidateFunc: validation.StringInSlice([]string{
+								"bottom",
+								"top",
+							}, false),
+							Description: "Social buttons layout. Options include `bottom` and `top`",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var (
+	brandingThemeStyles     = []string{"pill", "rounded", "sharp"}
+	brandingThemeAlignments = []string{"center", "left", "right"}
+)
+
+func brandingThemeColorSchema(defaultColor, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      defaultColor,
+		ValidateFunc: v.IsHexColor,
+		Description:  description + " color",
+	}
+}
+
+func brandingThemeTextSchema(defaultSize float64, defaultBold bool, description string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Required: true,
+		MinItems: 1,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"bold": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Default:     defaultBold,
+					Description: "Whether or not the text is bold",
+				},
+				"size": {
+					Type:         schema.TypeFloat,
+					Optional:     true,
+					Default:      defaultSize,
+					ValidateFunc: validation.FloatBetween(0, 300),
+					Description:  "Text size, as a percentage of the reference text size",
+				},
+			},
+		},
+		Description: description + " font settings",
+	}
+}
+
+// The following types model the branding themes endpoints of the Management
+// API, which are not yet available in the management package.
+
+type brandingTheme struct {
+	ID             *string                      `json:"themeId,omitempty"`
+	DisplayName    *string                      `json:"displayName,omitempty"`
+	Borders        *brandingThemeBorders        `json:"borders,omitempty"`
+	Colors         *brandingThemeColors         `json:"colors,omitempty"`
+	Fonts          *brandingThemeFonts          `json:"fonts,omitempty"`
+	PageBackground *brandingThemePageBackground `json:"page_background,omitempty"`
+	Widget         *brandingThemeWidget         `json:"widget,omitempty"`
+}
+
+type brandingThemeBorders struct {
+	ButtonBorderRadius *float64 `json:"button_border_radius,omitempty"`
+	ButtonBorderWeight *float64 `json:"button_border_weight,omitempty"`
+	ButtonsStyle       *string  `json:"buttons_style,omitempty"`
+	InputBorderRadius  *float64 `json:"input_border_radius,omitempty"`
+	InputBorderWeight  *float64 `json:"input_border_weight,omitempty"`
+	InputsStyle        *string  `json:"inputs_style,omitempty"`
+	ShowWidgetShadow   *bool    `json:"show_widget_shadow,omitempty"`
+	WidgetBorderWeight *float64 `json:"widget_border_weight,omitempty"`
+	WidgetCornerRadius *float64 `json:"widget_corner_radius,omitempty"`
+}
+
+type brandingThemeColors struct {
+	BaseFocusColor          *string `json:"base_focus_color,omitempty"`
+	BaseHoverColor          *string `json:"base_hover_color,omitempty"`
+	BodyText                *string `json:"body_text,omitempty"`
+	Error                   *string `json:"error,omitempty"`
+	Header                  *string `json:"header,omitempty"`
+	Icons                   *string `json:"icons,omitempty"`
+	InputBackground         *string `json:"input_background,omitempty"`
+	InputBorder             *string `json:"input_border,omitempty"`
+	InputFilledText         *string `json:"input_filled_text,omitempty"`
+	InputLabelsPlaceholders *string `json:"input_labels_placeholders,omitempty"`
+	LinksFocusedComponents  *string `json:"links_focused_components,omitempty"`
+	PrimaryButton           *string `json:"primary_button,omitempty"`
+	PrimaryButtonLabel      *string `json:"primary_button_label,omitempty"`
+	SecondaryButtonBorder   *string `json:"secondary_button_border,omitempty"`
+	SecondaryButtonLabel    *string `json:"secondary_button_label,omitempty"`
+	Success                 *string `json:"success,omitempty"`
+	WidgetBackground        *string `json:"widget_background,omitempty"`
+	WidgetBorder            *string `json:"widget_border,omitempty"`
+}
+
+type brandingThemeFonts struct {
+	FontURL           *string            `json:"font_url,omitempty"`
+	LinksStyle        *string            `json:"links_style,omitempty"`
+	ReferenceTextSize *float64           `json:"reference_text_size,omitempty"`
+	BodyText          *brandingThemeText `json:"body_text,omitempty"`
+	ButtonsText       *brandingThemeText `json:"buttons_text,omitempty"`
+	InputLabels       *brandingThemeText `json:"input_labels,omitempty"`
+	Links             *brandingThemeText `json:"links,omitempty"`
+	Subtitle          *brandingThemeText `json:"subtitle,omitempty"`
+	Title             *brandingThemeText `json:"title,omitempty"`
+}
+
+type brandingThemeText struct {
+	Bold *bool    `json:"bold,omitempty"`
+	Size *float64 `json:"size,omitempty"`
+}
+
+type brandingThemePageBackground struct {
+	BackgroundColor    *string `json:"background_color,omitempty"`
+	BackgroundImageURL *string `json:"background_image_url,omitempty"`
+	PageLayout         *string `json:"page_layout,omitempty"`
+}
+
+type brandingThemeWidget struct {
+	HeaderTextAlignment *string  `json:"header_text_alignment,omitempty"`
+	LogoHeight          *float64 `json:"logo_height,omitempty"`
+	LogoPosition        *string  `json:"logo_position,omitempty"`
+	LogoURL             *string  `json:"logo_url,omitempty"`
+	SocialButtonsLayout *string  `json:"social_buttons_layout,omitempty"`
+}
+
+func createBrandingTheme(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+
+	// A tenant can only have a single theme. If one already exists, it is
+	// adopted and updated instead of failing to create a new one.
+	existing := &brandingTheme{}
+	err := api.Request(http.MethodGet, api.URI("branding", "themes", "default"), existing)
+	if err != nil {
+		if mErr, ok := err.(management.Error); !ok || mErr.Status() != http.StatusNotFound {
+			return err
+		}
+	}
+	if existing.ID != nil {
+		d.SetId(*existing.ID)
+		return updateBrandingTheme(d, m)
+	}
+
+	t := expandBrandingTheme(d)
+	if err := api.Request(http.MethodPost, api.URI("branding", "themes"), t); err != nil {
+		return err
+	}
+	d.SetId(t.GetID())
+	return readBrandingTheme(d, m)
+}
+
+func readBrandingTheme(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	t := &brandingTheme{}
+	err := api.Request(http.MethodGet, api.URI("branding", "themes", d.Id()), t)
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	d.Set("display_name", t.DisplayName)
+	d.Set("borders", flattenBrandingThemeBorders(t.Borders))
+	d.Set("colors", flattenBrandingThemeColors(t.Colors))
+	d.Set("fonts", flattenBrandingThemeFonts(t.Fonts))
+	d.Set("page_background", flattenBrandingThemePageBackground(t.PageBackground))
+	d.Set("widget", flattenBrandingThemeWidget(t.Widget))
+	return nil
+}
+
+func updateBrandingTheme(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	t := expandBrandingTheme(d)
+	if err := api.Request(http.MethodPatch, api.URI("branding", "themes", d.Id()), t); err != nil {
+		return err
+	}
+	return readBrandingTheme(d, m)
+}
+
+func deleteBrandingTheme(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	err := api.Request(http.MethodDelete, api.URI("branding", "themes", d.Id()), nil)
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+// GetID returns the ID field if it's non-nil, zero value otherwise.
+func (t *brandingTheme) GetID() string {
+	if t == nil || t.ID == nil {
+		return ""
+	}
+	return *t.ID
+}
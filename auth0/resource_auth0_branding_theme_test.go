@@ -0,0 +1,165 @@
+package auth0
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccBrandingTheme(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBrandingThemeConfigCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "borders.0.buttons_style", "pill"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "borders.0.widget_border_weight", "0"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "colors.0.primary_button", "#0059d6"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "colors.0.body_text", "#1e212a"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "fonts.0.title.0.bold", "true"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "fonts.0.title.0.size", "175"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "fonts.0.links.0.bold", "true"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "page_background.0.page_layout", "left"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "widget.0.social_buttons_layout", "top"),
+				),
+			},
+			{
+				Config: testAccBrandingThemeConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "display_name", "My Theme"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "borders.0.buttons_style", "sharp"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "colors.0.primary_button", "#ffa629"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "fonts.0.title.0.bold", "false"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "fonts.0.title.0.size", "150"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "page_background.0.page_layout", "center"),
+					resource.TestCheckResourceAttr("auth0_branding_theme.my_theme", "widget.0.logo_position", "none"),
+				),
+			},
+			{
+				Config:      testAccBrandingThemeConfigInvalidColor,
+				ExpectError: regexp.MustCompile("to be a hex color"),
+			},
+		},
+	})
+}
+
+const testAccBrandingThemeConfigCreate = `
+resource "auth0_branding_theme" "my_theme" {
+	borders {
+		buttons_style = "pill"
+	}
+	colors {
+		primary_button = "#0059d6"
+	}
+	fonts {
+		body_text {}
+		buttons_text {}
+		input_labels {}
+		links {}
+		subtitle {}
+		title {
+			bold = true
+			size = 175
+		}
+	}
+	page_background {
+		page_layout = "left"
+	}
+	widget {
+		social_buttons_layout = "top"
+	}
+}
+`
+
+const testAccBrandingThemeConfigUpdate = `
+resource "auth0_branding_theme" "my_theme" {
+	display_name = "My Theme"
+	borders {
+		buttons_style = "sharp"
+	}
+	colors {
+		primary_button = "#ffa629"
+	}
+	fonts {
+		body_text {}
+		buttons_text {}
+		input_labels {}
+		links {}
+		subtitle {}
+		title {}
+	}
+	page_background {}
+	widget {
+		logo_position = "none"
+	}
+}
+`
+
+const testAccBrandingThemeConfigInvalidColor = `
+resource "auth0_branding_theme" "my_theme" {
+	borders {}
+	colors {
+		primary_button = "blue"
+	}
+	fonts {
+		body_text {}
+		buttons_text {}
+		input_labels {}
+		links {}
+		subtitle {}
+		title {}
+	}
+	page_background {}
+	widget {}
+}
+`
+
+func TestExpandBrandingTheme(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, newBrandingTheme().Schema, map[string]interface{}{
+		"borders": []interface{}{
+			map[string]interface{}{"show_widget_shadow": false},
+		},
+		"colors": []interface{}{map[string]interface{}{}},
+		"fonts": []interface{}{
+			map[string]interface{}{
+				"body_text":    []interface{}{map[string]interface{}{}},
+				"buttons_text": []interface{}{map[string]interface{}{}},
+				"input_labels": []interface{}{map[string]interface{}{}},
+				"links":        []interface{}{map[string]interface{}{}},
+				"subtitle":     []interface{}{map[string]interface{}{}},
+				"title":        []interface{}{map[string]interface{}{"size": 175.0}},
+			},
+		},
+		"page_background": []interface{}{map[string]interface{}{}},
+		"widget":          []interface{}{map[string]interface{}{}},
+	})
+
+	theme := expandBrandingTheme(d)
+
+	// Zero values must be sent as the Management API expects a complete theme.
+	if theme.Borders.ShowWidgetShadow == nil || *theme.Borders.ShowWidgetShadow {
+		t.Errorf("expected show_widget_shadow to be false, got %v", theme.Borders.ShowWidgetShadow)
+	}
+	if theme.Borders.WidgetBorderWeight == nil || *theme.Borders.WidgetBorderWeight != 0 {
+		t.Errorf("expected widget_border_weight to be 0, got %v", theme.Borders.WidgetBorderWeight)
+	}
+	if theme.Fonts.Links.Bold == nil || !*theme.Fonts.Links.Bold {
+		t.Errorf("expected links to be bold by default, got %v", theme.Fonts.Links.Bold)
+	}
+	if theme.Fonts.Title.Size == nil || *theme.Fonts.Title.Size != 175 {
+		t.Errorf("expected title size to be 175, got %v", theme.Fonts.Title.Size)
+	}
+	if theme.Colors.PrimaryButton == nil || *theme.Colors.PrimaryButton != "#635dff" {
+		t.Errorf("expected default primary_button color, got %v", theme.Colors.PrimaryButton)
+	}
+	if theme.Widget.LogoURL == nil || *theme.Widget.LogoURL != "" {
+		t.Errorf("expected empty logo_url, got %v", theme.Widget.LogoURL)
+	}
+}
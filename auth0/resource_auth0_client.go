@@ -589,7 +589,7 @@ func newClient() *schema.Resource {
 
 func createClient(d *schema.ResourceData, m interface{}) error {
 	c := expandClient(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Client.Create(c); err != nil {
 		return err
 	}
@@ -598,7 +598,7 @@ func createClient(d *schema.ResourceData, m interface{}) error {
 }
 
 func readClient(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	c, err := api.Client.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -611,7 +611,7 @@ func readClient(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.Set("client_id", c.ClientID)
-	d.Set("client_secret", c.ClientSecret)
+	d.Set("client_secret", m.(*config).secrets.hash(c.GetClientSecret()))
 	d.Set("name", c.Name)
 	d.Set("description", c.Description)
 	d.Set("app_type", c.AppType)
@@ -649,7 +649,7 @@ func readClient(d *schema.ResourceData, m interface{}) error {
 
 func updateClient(d *schema.ResourceData, m interface{}) error {
 	c := expandClient(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if clientHasChange(c) {
 		err := api.Client.Update(d.Id(), c)
 		if err != nil {
@@ -666,7 +666,7 @@ func updateClient(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteClient(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Client.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -701,8 +701,8 @@ func expandClient(d *schema.ResourceData) *management.Client {
 		SSODisabled:                    Bool(d, "sso_disabled"),
 		CrossOriginAuth:                Bool(d, "cross_origin_auth"),
 		CrossOriginLocation:            String(d, "cross_origin_loc"),
-		CustomLoginPageOn:              Bool(d, "custom_login_page_on"),
-		CustomLoginPage:                String(d, "custom_login_page"),
+		CustomLoginPageOn:              Bool(d, "custom_login_page_on", IsNewResource(), HasChange()),
+		CustomLoginPage:                String(d, "custom_login_page", IsNewResource(), HasChange()),
 		FormTemplate:                   String(d, "form_template"),
 		TokenEndpointAuthMethod:        String(d, "token_endpoint_auth_method"),
 		InitiateLoginURI:               String(d, "initiate_login_uri"),
@@ -865,12 +865,12 @@ func buildClientAddon(d map[string]interface{}) map[string]interface{} {
 
 func rotateClientSecret(d *schema.ResourceData, m interface{}) error {
 	if d.HasChange("client_secret_rotation_trigger") {
-		api := m.(*management.Management)
+		api := m.(*config).api
 		c, err := api.Client.RotateSecret(d.Id())
 		if err != nil {
 			return err
 		}
-		d.Set("client_secret", c.ClientSecret)
+		d.Set("client_secret", m.(*config).secrets.hash(c.GetClientSecret()))
 	}
 	d.SetPartial("client_secret_rotation_trigger")
 	return nil
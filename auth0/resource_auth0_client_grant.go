@@ -42,7 +42,7 @@ func newClientGrant() *schema.Resource {
 
 func createClientGrant(d *schema.ResourceData, m interface{}) error {
 	g := buildClientGrant(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.ClientGrant.Create(g); err != nil {
 		return err
 	}
@@ -51,7 +51,7 @@ func createClientGrant(d *schema.ResourceData, m interface{}) error {
 }
 
 func readClientGrant(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	g, err := api.ClientGrant.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -73,7 +73,7 @@ func updateClientGrant(d *schema.ResourceData, m interface{}) error {
 	g := buildClientGrant(d)
 	g.Audience = nil
 	g.ClientID = nil
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.ClientGrant.Update(d.Id(), g)
 	if err != nil {
 		return err
@@ -82,7 +82,7 @@ func updateClientGrant(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteClientGrant(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.ClientGrant.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -1,18 +1,25 @@
 package auth0
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
 	"gopkg.in/auth0.v5"
 	"gopkg.in/auth0.v5/management"
 )
 
-func newConnection() *schema.Resource {
+func newConnection(secrets *secretHasher) *schema.Resource {
 	return &schema.Resource{
 
 		Create: createConnection,
@@ -22,8 +29,13 @@ func newConnection() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
-		Schema:        connectionSchema,
-		SchemaVersion: 2,
+		CustomizeDiff: customdiff.All(
+			validateConnection,
+			validateConnectionDomainAliases,
+			diffConnectionCustomScripts,
+		),
+		Schema:        withConnectionSecrets(connectionSchema, secrets),
+		SchemaVersion: 3,
 		StateUpgraders: []schema.StateUpgrader{
 			{
 				Type:    connectionSchemaV0().CoreConfigSchema().ImpliedType(),
@@ -35,11 +47,16 @@ func newConnection() *schema.Resource {
 				Upgrade: connectionSchemaUpgradeV1,
 				Version: 1,
 			},
+			{
+				Type:    connectionSchemaV2().CoreConfigSchema().ImpliedType(),
+				Upgrade: connectionSchemaUpgradeV2,
+				Version: 2,
+			},
 		},
 	}
 }
 
-var connectionSchema = map[string]*schema.Schema{
+var connectionSchema = withConnectionOptionsBlocks(map[string]*schema.Schema{
 	"name": {
 		Type:        schema.TypeString,
 		Required:    true,
@@ -67,7 +84,7 @@ var connectionSchema = map[string]*schema.Schema{
 			"evernote-sandbox", "evernote", "exact", "facebook",
 			"fitbit", "flickr", "github", "google-apps",
 			"google-oauth2", "guardian", "instagram", "ip", "linkedin",
-			"miicard", "oauth1", "oauth2", "office365", "oidc", "paypal",
+			"miicard", "oauth1", "oauth2", "office365", "oidc", "okta", "paypal",
 			"paypal-sandbox", "pingfederate", "planningcenter",
 			"renren", "salesforce-community", "salesforce-sandbox",
 			"salesforce", "samlp", "sharepoint", "shopify", "sms",
@@ -79,597 +96,814 @@ var connectionSchema = map[string]*schema.Schema{
 		ForceNew:    true,
 		Description: "Type of the connection, which indicates the identity provider",
 	},
-	"options": {
-		Type:     schema.TypeList,
+	"options_json": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		Sensitive:        true,
+		ValidateFunc:     validation.StringIsJSON,
+		DiffSuppressFunc: structure.SuppressJsonDiff,
+		Description:      "Connection options as a JSON object, sent as they are. Required to configure the options of strategies without an options block",
+	},
+	"enabled_clients": {
+		Type:        schema.TypeSet,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Optional:    true,
+		Computed:    true,
+		Description: "IDs of the clients for which the connection is enabled",
+	},
+	"enabled_clients_authoritative": {
+		Type:     schema.TypeBool,
 		Optional: true,
+		Default:  true,
+		Description: "Whether `enabled_clients` lists every client for which the " +
+			"connection is enabled. When false, only the listed clients are " +
+			"enabled or disabled, and the clients enabled by others are left in place",
+	},
+	"custom_scripts_hashes": {
+		Type:     schema.TypeMap,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Computed: true,
+		Description: "SHA-256 hashes of the custom database action scripts " +
+			"of `auth0` connections, by name",
+	},
+	"realms": {
+		Type:        schema.TypeList,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Optional:    true,
+		Computed:    true,
+		Description: "Defines the realms for which the connection will be used (i.e., email domains). If not specified, the connection name is added as the realm",
+	},
+})
+
+// withConnectionSecrets returns a copy of s whose secret options suppress
+// their diff using secrets. The schema is shared by every provider, each of
+// which has its own secrets.
+func withConnectionSecrets(s map[string]*schema.Schema, secrets *secretHasher) map[string]*schema.Schema {
+	c := make(map[string]*schema.Schema, len(s))
+	for k, v := range s {
+		c[k] = v
+		r, ok := v.Elem.(*schema.Resource)
+		if !ok || r.Schema["twilio_token"] == nil {
+			continue
+		}
+		options := make(map[string]*schema.Schema, len(r.Schema))
+		for key, option := range r.Schema {
+			options[key] = option
+		}
+		token := *options["twilio_token"]
+		token.DiffSuppressFunc = secrets.suppressDiff
+		options["twilio_token"] = &token
+
+		elem := *r
+		elem.Schema = options
+		block := *v
+		block.Elem = &elem
+		c[k] = &block
+	}
+	return c
+}
+
+// withConnectionOptionsBlocks adds a block to s for the options of each
+// strategy with typed options, holding only the options the strategy uses.
+func withConnectionOptionsBlocks(s map[string]*schema.Schema) map[string]*schema.Schema {
+	strategies := make(map[string][]string)
+	for strategy, block := range connectionOptionsBlocks {
+		strategies[block] = append(strategies[block], strategy)
+	}
+
+	for block, st := range strategies {
+		sort.Strings(st)
+		options := make(map[string]*schema.Schema)
+		for _, strategy := range st {
+			for key := range connectionOptionsKeys[strategy] {
+				options[key] = connectionOptionsSchema[key]
+			}
+			for _, key := range connectionOptionsComputed[strategy] {
+				options[key] = connectionOptionsSchema[key]
+			}
+		}
+		s[block] = &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: options,
+			},
+			Description: fmt.Sprintf("Configuration settings for the options of the `%s` strategy",
+				strings.Join(st, "`, `")),
+		}
+	}
+
+	s["options_json"].ConflictsWith = connectionOptionsBlockNames()
+	return s
+}
+
+// connectionOptionsSchema holds the options of every strategy with typed
+// options. Each strategy has a block holding only the options it uses, see
+// connectionOptionsBlocks.
+var connectionOptionsSchema = map[string]*schema.Schema{
+	"validation": {
+		Type:     schema.TypeList,
 		MaxItems: 1,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
-				"validation": {
-					Type:     schema.TypeList,
-					MaxItems: 1,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"username": {
-								Optional: true,
-								Type:     schema.TypeList,
-								MaxItems: 1,
-								Elem: &schema.Resource{
-									Schema: map[string]*schema.Schema{
-										"min": {
-											Type:         schema.TypeInt,
-											Optional:     true,
-											ValidateFunc: validation.IntAtLeast(1),
-										},
-										"max": {
-											Type:         schema.TypeInt,
-											Optional:     true,
-											ValidateFunc: validation.IntAtLeast(1),
-										},
-									},
-								},
-							},
-						},
-					},
-					Optional: true,
-				},
-				"password_policy": {
-					Type:     schema.TypeString,
-					Optional: true,
-					Computed: true,
-					ValidateFunc: validation.StringInSlice([]string{
-						"none", "low", "fair", "good", "excellent",
-					}, false),
-					Description: "Indicates level of password strength to enforce during authentication. A strong password policy will make it difficult, if not improbable, for someone to guess a password through either manual or automated means. Options include `none`, `low`, `fair`, `good`, `excellent`",
-				},
-				"password_history": {
-					Type:     schema.TypeList,
-					Optional: true,
-					Computed: true,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"enable": {
-								Type:     schema.TypeBool,
-								Optional: true,
-							},
-							"size": {
-								Type:     schema.TypeInt,
-								Optional: true,
-							},
-						},
-					},
-					Description: "Configuration settings for the password history that is maintained for each user to prevent the reuse of passwords",
-				},
-				"password_no_personal_info": {
-					Type:     schema.TypeList,
+				"username": {
 					Optional: true,
-					MaxItems: 1,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"enable": {
-								Type:     schema.TypeBool,
-								Optional: true,
-							},
-						},
-					},
-					Description: "Configuration settings for the password personal info check, which does not allow passwords that contain any part of the user's personal data, including user's name, username, nickname, user_metadata.name, user_metadata.first, user_metadata.last, user's email, or firstpart of the user's email",
-				},
-				"password_dictionary": {
 					Type:     schema.TypeList,
-					Optional: true,
 					MaxItems: 1,
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
-							"enable": {
-								Type:     schema.TypeBool,
-								Optional: true,
-							},
-							"dictionary": {
-								Type:     schema.TypeSet,
-								Elem:     &schema.Schema{Type: schema.TypeString},
-								Optional: true,
+							"min": {
+								Type:         schema.TypeInt,
+								Optional:     true,
+								ValidateFunc: validation.IntAtLeast(1),
 							},
-						},
-					},
-					Description: "Configuration settings for the password dictionary check, which does not allow passwords that are part of the password dictionary",
-				},
-				"password_complexity_options": {
-					Type:     schema.TypeList,
-					Optional: true,
-					MaxItems: 1,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"min_length": {
+							"max": {
 								Type:         schema.TypeInt,
 								Optional:     true,
 								ValidateFunc: validation.IntAtLeast(1),
 							},
 						},
 					},
-					Description: "Configuration settings for password complexity",
-				},
-				"enabled_database_customization": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "",
-				},
-				"brute_force_protection": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "Indicates whether or not to enable brute force protection, which will limit the number of signups and failed logins from a suspicious IP address",
-				},
-				"import_mode": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "Indicates whether or not you have a legacy user store and want to gradually migrate those users to the Auth0 user store",
-				},
-				"disable_signup": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "Indicates whether or not to allow user sign-ups to your application",
-				},
-				"requires_username": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "Indicates whether or not the user is required to provide a username in addition to an email address",
-				},
-				"custom_scripts": {
-					Type:        schema.TypeMap,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Description: "",
-				},
-				"scripts": {
-					Type:        schema.TypeMap,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Description: "",
-				},
-				"configuration": {
-					Type:        schema.TypeMap,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Sensitive:   true,
-					Optional:    true,
-					Description: "",
-				},
-				"client_id": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"client_secret": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					Description: "",
-				},
-				"allowed_audiences": {
-					Type:        schema.TypeSet,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Description: "",
 				},
-				"api_enable_users": {
+			},
+		},
+		Optional: true,
+	},
+	"password_policy": {
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ValidateFunc: validation.StringInSlice([]string{
+			"none", "low", "fair", "good", "excellent",
+		}, false),
+		Description: "Indicates level of password strength to enforce during authentication. A strong password policy will make it difficult, if not improbable, for someone to guess a password through either manual or automated means. Options include `none`, `low`, `fair`, `good`, `excellent`",
+	},
+	"password_history": {
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enable": {
 					Type:     schema.TypeBool,
 					Optional: true,
 				},
-				"app_id": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"app_domain": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-					Deprecated:  "Use domain instead",
-				},
-				"domain": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"domain_aliases": {
-					Type:        schema.TypeSet,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Description: "",
-				},
-				"max_groups_to_retrieve": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"tenant_domain": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"use_wsfed": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "",
-				},
-				"waad_protocol": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"waad_common_endpoint": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "",
-				},
-				"icon_url": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"identity_api": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"ips": {
-					Type:        schema.TypeSet,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Description: "",
-				},
-				"use_cert_auth": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "",
-				},
-				"use_kerberos": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "",
-				},
-				"disable_cache": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "",
-				},
-				"name": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"twilio_sid": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"twilio_token": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					DefaultFunc: schema.EnvDefaultFunc("TWILIO_TOKEN", nil),
-					Description: "",
-				},
-				"from": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"syntax": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"subject": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"template": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"totp": {
-					Type:     schema.TypeList,
+				"size": {
+					Type:     schema.TypeInt,
 					Optional: true,
-					MaxItems: 1,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"time_step": {
-								Type:     schema.TypeInt,
-								Optional: true,
-							},
-							"length": {
-								Type:     schema.TypeInt,
-								Optional: true,
-							},
-						},
-					},
-					Description: "",
 				},
-				"messaging_service_sid": {
-					Type:     schema.TypeString,
+			},
+		},
+		Description: "Configuration settings for the password history that is maintained for each user to prevent the reuse of passwords",
+	},
+	"password_no_personal_info": {
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enable": {
+					Type:     schema.TypeBool,
 					Optional: true,
 				},
-				"mfa": {
-					Type:     schema.TypeList,
-					MaxItems: 1,
+			},
+		},
+		Description: "Configuration settings for the password personal info check, which does not allow passwords that contain any part of the user's personal data, including user's name, username, nickname, user_metadata.name, user_metadata.first, user_metadata.last, user's email, or firstpart of the user's email",
+	},
+	"password_dictionary": {
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"enable": {
+					Type:     schema.TypeBool,
 					Optional: true,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"active": {
-								Type:     schema.TypeBool,
-								Optional: true,
-							},
-							"return_enroll_settings": {
-								Type:     schema.TypeBool,
-								Optional: true,
-							},
-						},
-					},
 				},
-
-				// custom sms gateway options
-				"provider": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Defines the custom sms_gateway provider",
-					ValidateFunc: validation.StringInSlice([]string{
-						"sms_gateway",
-					}, false),
+				"dictionary": {
+					Type:     schema.TypeSet,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Optional: true,
 				},
-				"gateway_url": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Defines a custom sms gateway to use instead of twilio",
+			},
+		},
+		Description: "Configuration settings for the password dictionary check, which does not allow passwords that are part of the password dictionary",
+	},
+	"password_complexity_options": {
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"min_length": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
 				},
-				"gateway_authentication": {
-					Type:        schema.TypeList,
-					MaxItems:    1,
-					Optional:    true,
-					Description: "Defines the parameters used to generate the auth token for the custom gateway",
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"method": {
-								Type:        schema.TypeString,
-								Optional:    true,
-								Description: "Authentication method (default is bearer token)",
-							},
-							"subject": {
-								Type:        schema.TypeString,
-								Optional:    true,
-								Description: "Subject claim for the HS256 token sent to gateway_url",
-							},
-							"audience": {
-								Type:        schema.TypeString,
-								Optional:    true,
-								Description: "Audience claim for the HS256 token sent to gateway_url",
-							},
-							"secret": {
-								Type:        schema.TypeString,
-								Optional:    true,
-								Description: "Secret used to sign the HS256 token sent to gateway_url",
-							},
-							"secret_base64_encoded": {
-								Type:        schema.TypeBool,
-								Optional:    true,
-								Description: "Specifies whether or not the secret is base64 encoded",
-							},
-						},
-					},
-				},
-				"forward_request_info": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "Specifies whether or not request info should be forwarded to sms gateway",
-				},
-
-				"set_user_root_attributes": {
-					Type:     schema.TypeString,
-					Optional: true,
-					Computed: true,
-					ValidateFunc: validation.StringInSlice([]string{
-						"on_each_login", "on_first_login",
-					}, false),
-					Description: "Determines whether the 'name', 'given_name', 'family_name', 'nickname', and 'picture' attributes can be independently updated when using an external IdP. Possible values are 'on_each_login' (default value, it configures the connection to automatically update the root attributes from the external IdP with each user login. When this setting is used, root attributes cannot be independently updated), 'on_first_login' (configures the connection to only set the root attributes on first login, allowing them to be independently updated thereafter)",
-				},
-				"non_persistent_attrs": {
-					Type:        schema.TypeSet,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Computed:    true,
-					Description: "If there are user fields that should not be stored in Auth0 databases due to privacy reasons, you can add them to the DenyList here",
-				},
-				"should_trust_email_verified_connection": {
-					Type:     schema.TypeString,
-					Optional: true,
-					ValidateFunc: validation.StringInSlice([]string{
-						"never_set_emails_as_verified", "always_set_emails_as_verified",
-					}, false),
-					Description: "Choose how Auth0 sets the email_verified field in the user profile.",
-				},
-
-				// apple options
-				"team_id": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Apple Team ID",
-				},
-				"key_id": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Apple Key ID",
-				},
-
-				// adfs options
-				"adfs_server": {
-					Type:     schema.TypeString,
+			},
+		},
+		Description: "Configuration settings for password complexity",
+	},
+	"enabled_database_customization": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "",
+	},
+	"brute_force_protection": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Indicates whether or not to enable brute force protection, which will limit the number of signups and failed logins from a suspicious IP address",
+	},
+	"import_mode": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Indicates whether or not you have a legacy user store and want to gradually migrate those users to the Auth0 user store",
+	},
+	"disable_signup": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Indicates whether or not to allow user sign-ups to your application",
+	},
+	"requires_username": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Indicates whether or not the user is required to provide a username in addition to an email address",
+	},
+	"custom_scripts": {
+		Type:         schema.TypeMap,
+		Elem:         &schema.Schema{Type: schema.TypeString},
+		Optional:     true,
+		ValidateFunc: validateConnectionCustomScripts,
+		Description: "Custom database action scripts, by name. Supported " +
+			"names are " + strings.Join(connectionCustomScripts, ", "),
+	},
+	"custom_scripts_directory": {
+		Type:          schema.TypeString,
+		Optional:      true,
+		ConflictsWith: []string{"auth0_options.0.custom_scripts"},
+		Description: "Path of a directory holding the custom database action " +
+			"scripts, as an alternative to `custom_scripts`. Each script is " +
+			"read from the file named after it, such as `login.js`, when planning",
+	},
+	"scripts": {
+		Type:        schema.TypeMap,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Optional:    true,
+		Description: "",
+	},
+	"configuration": {
+		Type:        schema.TypeMap,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Sensitive:   true,
+		Optional:    true,
+		Description: "",
+	},
+	"client_id": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"client_secret": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Sensitive:   true,
+		Description: "",
+	},
+	"allowed_audiences": {
+		Type:        schema.TypeSet,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Optional:    true,
+		Description: "",
+	},
+	"api_enable_users": {
+		Type:     schema.TypeBool,
+		Optional: true,
+	},
+	"app_id": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"app_domain": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+		Deprecated:  "Use domain instead",
+	},
+	"domain": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"domain_aliases": {
+		Type:     schema.TypeSet,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Optional: true,
+		Description: "Domains of the users who authenticate with the connection, " +
+			"used by home realm discovery. A domain can only be an alias of a single connection",
+	},
+	"max_groups_to_retrieve": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"tenant_domain": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"use_wsfed": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "",
+	},
+	"waad_protocol": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"waad_common_endpoint": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "",
+	},
+	"icon_url": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "URL of the icon shown on the login button of the connection",
+	},
+	"show_as_button": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Indicates whether the connection is shown as a button on the login page, rather than only through home realm discovery",
+	},
+	"identity_api": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"ips": {
+		Type:        schema.TypeSet,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Optional:    true,
+		Description: "",
+	},
+	"use_cert_auth": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "",
+	},
+	"use_kerberos": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "",
+	},
+	"disable_cache": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "",
+	},
+	"name": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"twilio_sid": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"twilio_token": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Sensitive:   true,
+		DefaultFunc: schema.EnvDefaultFunc("TWILIO_TOKEN", nil),
+		Description: "",
+	},
+	"from": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateLiquidWarnings(),
+		Description:  "",
+	},
+	"syntax": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"subject": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateLiquidWarnings(),
+		Description:  "",
+	},
+	"template": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateLiquidWarnings(),
+		Description:  "",
+	},
+	"totp": {
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"time_step": {
+					Type:     schema.TypeInt,
 					Optional: true,
 				},
-
-				// salesforce options
-				"community_base_url": {
-					Type:     schema.TypeString,
+				"length": {
+					Type:     schema.TypeInt,
 					Optional: true,
 				},
-
-				"strategy_version": {
-					Type:     schema.TypeInt,
+			},
+		},
+		Description: "",
+	},
+	"messaging_service_sid": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"mfa": {
+		Type:     schema.TypeList,
+		MaxItems: 1,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"active": {
+					Type:     schema.TypeBool,
 					Optional: true,
-					Computed: true,
 				},
-
-				"scopes": {
-					Type:     schema.TypeSet,
+				"return_enroll_settings": {
+					Type:     schema.TypeBool,
 					Optional: true,
-					Elem:     &schema.Schema{Type: schema.TypeString},
 				},
+			},
+		},
+	},
 
-				// OIDC options
-				"type": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"issuer": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"jwks_uri": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "",
-				},
-				"discovery_url": {
+	// custom sms gateway options
+	"provider": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Defines the custom sms_gateway provider",
+		ValidateFunc: validation.StringInSlice([]string{
+			"sms_gateway",
+		}, false),
+	},
+	"gateway_url": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Defines a custom sms gateway to use instead of twilio",
+	},
+	"gateway_authentication": {
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Optional:    true,
+		Description: "Defines the parameters used to generate the auth token for the custom gateway",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"method": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					Description: "",
+					Description: "Authentication method (default is bearer token)",
 				},
-				"token_endpoint": {
+				"subject": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					Description: "",
+					Description: "Subject claim for the HS256 token sent to gateway_url",
 				},
-				"userinfo_endpoint": {
+				"audience": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					Description: "",
+					Description: "Audience claim for the HS256 token sent to gateway_url",
 				},
-				"authorization_endpoint": {
+				"secret": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					Description: "",
+					Description: "Secret used to sign the HS256 token sent to gateway_url",
 				},
-				// SAML options
-				"debug": {
+				"secret_base64_encoded": {
 					Type:        schema.TypeBool,
 					Optional:    true,
-					Description: "When enabled, additional debug information will be generated.",
-				},
-				"signing_cert": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "X.509 signing certificate (encoded in PEM or CER) you retrieved from the IdP, Base64-encoded",
-				},
-				"protocol_binding": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "The SAML Response Binding: how the SAML token is received by Auth0 from IdP",
-					ValidateFunc: validation.StringInSlice([]string{
-						"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect",
-						"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST",
-					}, true),
-				},
-				"request_template": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Template that formats the SAML request.",
-				},
-				"user_id_attribute": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Attribute in the SAML token that will be mapped to the user_id property in Auth0.",
-				},
-				"idp_initiated": {
-					Type:     schema.TypeList,
-					MaxItems: 1,
-					Required: false,
-					Optional: true,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"client_id": {
-								Type:     schema.TypeString,
-								Optional: true,
-							},
-							"client_protocol": {
-								Type:     schema.TypeString,
-								Optional: true,
-							},
-							"client_authorize_query": {
-								Type:     schema.TypeString,
-								Optional: true,
-							},
-						},
-					},
+					Description: "Specifies whether or not the secret is base64 encoded",
 				},
-				"sign_in_endpoint": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "SAML single login URL for the connection.",
+			},
+		},
+	},
+	"forward_request_info": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Specifies whether or not request info should be forwarded to sms gateway",
+	},
+
+	"set_user_root_attributes": {
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ValidateFunc: validation.StringInSlice([]string{
+			"on_each_login", "on_first_login",
+		}, false),
+		Description: "Determines whether the 'name', 'given_name', 'family_name', 'nickname', and 'picture' attributes can be independently updated when using an external IdP. Possible values are 'on_each_login' (default value, it configures the connection to automatically update the root attributes from the external IdP with each user login. When this setting is used, root attributes cannot be independently updated), 'on_first_login' (configures the connection to only set the root attributes on first login, allowing them to be independently updated thereafter)",
+	},
+	"non_persistent_attrs": {
+		Type:        schema.TypeSet,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Optional:    true,
+		Computed:    true,
+		Description: "If there are user fields that should not be stored in Auth0 databases due to privacy reasons, you can add them to the DenyList here",
+	},
+	"should_trust_email_verified_connection": {
+		Type:     schema.TypeString,
+		Optional: true,
+		ValidateFunc: validation.StringInSlice([]string{
+			"never_set_emails_as_verified", "always_set_emails_as_verified",
+		}, false),
+		Description: "Choose how Auth0 sets the email_verified field in the user profile.",
+	},
+
+	// apple options
+	"team_id": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Apple Team ID",
+	},
+	"key_id": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Apple Key ID",
+	},
+
+	// adfs options
+	"adfs_server": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+
+	// salesforce options
+	"community_base_url": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+
+	"strategy_version": {
+		Type:     schema.TypeInt,
+		Optional: true,
+		Computed: true,
+	},
+
+	"scopes": {
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	},
+
+	// OIDC options
+	"type": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"issuer": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"jwks_uri": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"discovery_url": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"token_endpoint": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"userinfo_endpoint": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"authorization_endpoint": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "",
+	},
+	"upstream_params": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateFunc:     validation.StringIsJSON,
+		DiffSuppressFunc: structure.SuppressJsonDiff,
+		Description:      "JSON object of additional parameters to send to the identity provider, either static or mapped from the authorization request",
+	},
+	"attribute_map": {
+		Type:     schema.TypeList,
+		MaxItems: 1,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"mapping_mode": {
+					Type:     schema.TypeString,
+					Required: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"use_map", "bind_all", "basic_profile",
+					}, false),
+					Description: "Method used to map the claims of the identity provider to the user profile. Options include `use_map`, `bind_all` and `basic_profile`",
 				},
-				"sign_out_endpoint": {
+				"userinfo_scope": {
 					Type:        schema.TypeString,
 					Optional:    true,
-					Description: "SAML single logout URL for the connection.",
-				},
-				"fields_map": {
-					Type:        schema.TypeMap,
-					Elem:        &schema.Schema{Type: schema.TypeString},
-					Optional:    true,
-					Description: "If you're configuring a SAML enterprise connection for a non-standard PingFederate Server, you must update the attribute mappings.",
+					Description: "Scopes to request from the userinfo endpoint, space separated",
 				},
-				"sign_saml_request": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Description: "When enabled, the SAML authentication request will be signed.",
+				"attributes": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					ValidateFunc:     validation.StringIsJSON,
+					DiffSuppressFunc: structure.SuppressJsonDiff,
+					Description:      "JSON object mapping user profile attributes to the claims of the identity provider",
 				},
-				"signature_algorithm": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Sign Request Algorithm",
+			},
+		},
+		Description: "Mapping of the claims of the identity provider to the user profile",
+	},
+
+	// OAuth1 options
+	"request_token_endpoint": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "URL used to obtain the OAuth1 request token",
+	},
+	"access_token_endpoint": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "URL used to exchange the OAuth1 request token for an access token",
+	},
+	"signature_method": {
+		Type:     schema.TypeString,
+		Optional: true,
+		ValidateFunc: validation.StringInSlice([]string{
+			"HMAC-SHA1", "RSA-SHA1", "PLAINTEXT",
+		}, false),
+		Description: "Method used to sign OAuth1 requests. Options include `HMAC-SHA1`, `RSA-SHA1` and `PLAINTEXT`",
+	},
+
+	// SAML options
+	"debug": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "When enabled, additional debug information will be generated.",
+	},
+	"signing_cert": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "X.509 signing certificate (encoded in PEM or CER) you retrieved from the IdP, Base64-encoded",
+	},
+	"protocol_binding": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The SAML Response Binding: how the SAML token is received by Auth0 from IdP",
+		ValidateFunc: validation.StringInSlice([]string{
+			"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect",
+			"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST",
+		}, true),
+	},
+	"request_template": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Template that formats the SAML request.",
+	},
+	"user_id_attribute": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Attribute in the SAML token that will be mapped to the user_id property in Auth0.",
+	},
+	"idp_initiated": {
+		Type:     schema.TypeList,
+		MaxItems: 1,
+		Required: false,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"client_id": {
+					Type:     schema.TypeString,
+					Optional: true,
 				},
-				"digest_algorithm": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Sign Request Algorithm Digest",
+				"client_protocol": {
+					Type:     schema.TypeString,
+					Optional: true,
 				},
-				"entity_id": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Description: "Custom Entity ID for the connection",
+				"client_authorize_query": {
+					Type:     schema.TypeString,
+					Optional: true,
 				},
 			},
 		},
-		Description: "Configuration settings for connection options",
 	},
-	"enabled_clients": {
-		Type:        schema.TypeSet,
-		Elem:        &schema.Schema{Type: schema.TypeString},
+	"sign_in_endpoint": {
+		Type:        schema.TypeString,
 		Optional:    true,
-		Computed:    true,
-		Description: "IDs of the clients for which the connection is enabled",
+		Description: "SAML single login URL for the connection.",
 	},
-	"realms": {
-		Type:        schema.TypeList,
+	"sign_out_endpoint": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "SAML single logout URL for the connection.",
+	},
+	"fields_map": {
+		Type:        schema.TypeMap,
 		Elem:        &schema.Schema{Type: schema.TypeString},
 		Optional:    true,
+		Description: "If you're configuring a SAML enterprise connection for a non-standard PingFederate Server, you must update the attribute mappings.",
+	},
+	"sign_saml_request": {
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "When enabled, the SAML authentication request will be signed.",
+	},
+	"signature_algorithm": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Sign Request Algorithm",
+	},
+	"digest_algorithm": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Sign Request Algorithm Digest",
+	},
+	"entity_id": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Custom Entity ID for the connection",
+	},
+	"metadata_xml": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateSAMLMetadata,
+		Description: "SAML metadata of the identity provider. The sign in and " +
+			"sign out endpoints, the signing certificate and the protocol " +
+			"binding which are not set are read from it",
+	},
+	"signing_cert_expires_at": {
+		Type:        schema.TypeString,
 		Computed:    true,
-		Description: "Defines the realms for which the connection will be used (i.e., email domains). If not specified, the connection name is added as the realm",
+		Description: "Expiry date of the signing certificate, in RFC 3339 format",
+	},
+	"signing_cert_fingerprint": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "SHA-1 fingerprint of the signing certificate, hex encoded",
+	},
+	"signing_cert_subject": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Subject of the signing certificate",
+	},
+
+	// PingFederate options
+	"ping_federate_base_url": {
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Base URL of the PingFederate server",
 	},
 }
 
+// connectionSchemaV2 returns the schema of the connection before the options
+// of each strategy were moved to their own block, when every option was held
+// by the options block.
+func connectionSchemaV2() *schema.Resource {
+	s := make(map[string]*schema.Schema)
+	for k, v := range connectionSchema {
+		s[k] = v
+	}
+	for _, block := range connectionOptionsBlockNames() {
+		delete(s, block)
+	}
+	delete(s, "enabled_clients_authoritative")
+	optionsJSON := *s["options_json"]
+	optionsJSON.ConflictsWith = []string{"options"}
+	s["options_json"] = &optionsJSON
+	s["options"] = &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"options_json"},
+		Elem: &schema.Resource{
+			Schema: connectionOptionsSchema,
+		},
+		Description: "Configuration settings for connection options",
+	}
+	return &schema.Resource{Schema: s}
+}
+
 func connectionSchemaV0() *schema.Resource {
-	s := connectionSchema
+	s := connectionSchemaV2().Schema
 	s["strategy_version"] = &schema.Schema{
 		Type:     schema.TypeString,
 		Optional: true,
@@ -679,7 +913,7 @@ func connectionSchemaV0() *schema.Resource {
 }
 
 func connectionSchemaV1() *schema.Resource {
-	s := connectionSchema
+	s := connectionSchemaV2().Schema
 	s["validation"] = &schema.Schema{
 		Type:     schema.TypeMap,
 		Elem:     &schema.Schema{Type: schema.TypeString},
@@ -758,19 +992,305 @@ func connectionSchemaUpgradeV1(state map[string]interface{}, meta interface{}) (
 	return state, nil
 }
 
+func connectionSchemaUpgradeV2(state map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+
+	o, ok := state["options"]
+	if !ok {
+		return state, nil
+	}
+	delete(state, "options")
+
+	l, ok := o.([]interface{})
+	if ok && len(l) > 0 {
+
+		m, ok := l[0].(map[string]interface{})
+		if !ok {
+			return state, nil
+		}
+
+		strategy, _ := state["strategy"].(string)
+		block, ok := connectionOptionsBlocks[strategy]
+		if !ok {
+			log.Printf("[DEBUG] Schema upgrade: options have been removed, as the %q strategy has no options block", strategy)
+			return state, nil
+		}
+
+		for k := range m {
+			if !connectionOptionsKeys[strategy][k] {
+				delete(m, k)
+			}
+		}
+
+		state[block] = []interface{}{m}
+
+		log.Printf("[DEBUG] Schema upgrade: options have been migrated to %s", block)
+	}
+
+	return state, nil
+}
+
+// connectionOptionsComputed are the option keys which are only read back, for
+// each strategy.
+var connectionOptionsComputed = map[string][]string{
+	management.ConnectionStrategySAML: {"signing_cert_expires_at", "signing_cert_fingerprint", "signing_cert_subject"},
+}
+
+// connectionOptionsRequired are the option keys which must be set for each
+// strategy.
+var connectionOptionsRequired = map[string][]string{
+	management.ConnectionStrategySAML: {"signing_cert", "sign_in_endpoint"},
+	connectionStrategyOkta:            {"client_id", "domain"},
+	connectionStrategyPingFederate:    {"ping_federate_base_url", "signing_cert"},
+	connectionStrategyOAuth1:          {"request_token_endpoint", "access_token_endpoint", "authorization_endpoint"},
+}
+
+// connectionOptionsDependencies are the option keys which can only be set
+// along with other keys, for each strategy.
+var connectionOptionsDependencies = map[string][]connectionOptionDependency{
+	management.ConnectionStrategyAuth0: {
+		{"import_mode", []string{"enabled_database_customization"}},
+		{"validation", []string{"requires_username"}},
+	},
+	management.ConnectionStrategySMS: {
+		{"provider", []string{"gateway_url"}},
+		{"gateway_url", []string{"provider"}},
+		{"gateway_authentication", []string{"provider", "gateway_url"}},
+		{"forward_request_info", []string{"provider", "gateway_url"}},
+	},
+}
+
+// connectionOptionsRequiredFrom are the option keys which, when set, provide
+// the required options of a strategy.
+var connectionOptionsRequiredFrom = map[string]string{
+	management.ConnectionStrategySAML: "metadata_xml",
+}
+
+type connectionOptionDependency struct {
+	key      string
+	requires []string
+}
+
+func validateConnection(d *schema.ResourceDiff, m interface{}) error {
+	strategy := d.Get("strategy").(string)
+	block, ok := connectionOptionsBlocks[strategy]
+
+	var errs *multierror.Error
+	for _, b := range connectionOptionsBlockNames() {
+		if _, set := d.GetOk(b); !set || b == block {
+			continue
+		}
+		if ok {
+			errs = multierror.Append(errs, fmt.Errorf("%s can't be used with the %q strategy, use %s instead", b, strategy, block))
+		} else {
+			errs = multierror.Append(errs, fmt.Errorf("%s can't be used with the %q strategy, use options_json instead", b, strategy))
+		}
+	}
+	_, set := d.GetOk("options_json")
+	if !ok && !set && d.NewValueKnown("strategy") && d.NewValueKnown("options_json") {
+		errs = multierror.Append(errs, fmt.Errorf("the %q strategy has no options block, its options must be set with options_json", strategy))
+	}
+	if !ok || set {
+		return errs.ErrorOrNil()
+	}
+
+	for _, key := range connectionOptionsRequired[strategy] {
+		if from, ok := connectionOptionsRequiredFrom[strategy]; ok && connectionOptionSet(d, block, from) {
+			break
+		}
+		if !connectionOptionSet(d, block, key) {
+			errs = multierror.Append(errs, fmt.Errorf("%s.%s is required by the %q strategy", block, key, strategy))
+		}
+	}
+	for _, dependency := range connectionOptionsDependencies[strategy] {
+		if !connectionOptionSet(d, block, dependency.key) {
+			continue
+		}
+		for _, key := range dependency.requires {
+			if !connectionOptionSet(d, block, key) {
+				errs = multierror.Append(errs, fmt.Errorf("%s.%s requires %s.%s to be set", block, dependency.key, block, key))
+			}
+		}
+	}
+	if strategy == management.ConnectionStrategyAuth0 {
+		if err := validateConnectionCustomScriptsRequired(d); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if template, ok := connectionTemplates[strategy]; ok {
+		if err := validateConnectionTemplate(d, strategy, block, template); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// connectionTemplates are the templates of the passwordless strategies, whose
+// variables are known.
+var connectionTemplates = map[string]string{
+	management.ConnectionStrategyEmail: passwordlessEmailTemplate,
+	management.ConnectionStrategySMS:   passwordlessSMSTemplate,
+}
+
+// validateConnectionTemplate checks the Liquid syntax of the template of a
+// passwordless connection with the liquid syntax, and that it only uses the
+// variables available to it.
+func validateConnectionTemplate(d *schema.ResourceDiff, strategy, block, template string) error {
+	if syntax, _ := d.Get(block + ".0.syntax").(string); syntax != "liquid" {
+		return nil
+	}
+	var errs *multierror.Error
+	for _, key := range []string{"template", "subject", "from"} {
+		k := block + ".0." + key
+		if !connectionOptionsKeys[strategy][key] || !d.NewValueKnown(k) {
+			continue
+		}
+		src, _ := d.Get(k).(string)
+		if _, err := parseLiquidTemplate(k, template, src); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// validateConnectionDomainAliases checks that the domain aliases of an
+// enterprise connection are not claimed by another connection of the
+// configuration. Home realm discovery finds a single connection for a domain,
+// so an alias can't be shared by two connections.
+func validateConnectionDomainAliases(d *schema.ResourceDiff, m interface{}) error {
+	strategy := d.Get("strategy").(string)
+	if !connectionStrategiesEnterprise[strategy] {
+		return nil
+	}
+	key := connectionOptionsBlocks[strategy] + ".0.domain_aliases"
+	if !d.NewValueKnown("name") || !d.NewValueKnown(key) {
+		return nil
+	}
+	name := d.Get("name").(string)
+
+	var aliases []string
+	if v, ok := d.GetOk(key); ok {
+		for _, alias := range v.(*schema.Set).List() {
+			aliases = append(aliases, strings.ToLower(alias.(string)))
+		}
+	}
+	sort.Strings(aliases)
+
+	claims := &m.(*config).claims
+	claims.release("domain_alias", name)
+
+	var errs *multierror.Error
+	for _, alias := range aliases {
+		for _, other := range claims.claim("domain_alias", alias, name) {
+			errs = multierror.Append(errs, fmt.Errorf("domain alias %q is already used by the %q connection", alias, other))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// connectionCustomScriptsRequired are the custom database scripts required by
+// the options of the auth0 strategy which make use of them.
+var connectionCustomScriptsRequired = []connectionOptionDependency{
+	{"enabled_database_customization", []string{"login"}},
+	{"import_mode", []string{"login", "get_user"}},
+}
+
+func validateConnectionCustomScriptsRequired(d *schema.ResourceDiff) error {
+	scripts, known, err := plannedConnectionCustomScripts(d)
+	if err != nil || !known {
+		return err
+	}
+
+	var errs *multierror.Error
+	missing := make(map[string]bool)
+	for _, dependency := range connectionCustomScriptsRequired {
+		if !connectionOptionSet(d, "auth0_options", dependency.key) {
+			continue
+		}
+		for _, name := range dependency.requires {
+			if _, ok := scripts[name]; !ok && !missing[name] {
+				missing[name] = true
+				errs = multierror.Append(errs, fmt.Errorf("auth0_options.%s requires the %s custom script", dependency.key, name))
+			}
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// diffConnectionCustomScripts plans the hashes of the custom database scripts
+// of auth0 connections, so that changes to the files of custom_scripts_directory
+// and to the scripts outside of Terraform are detected.
+func diffConnectionCustomScripts(d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("strategy").(string) != management.ConnectionStrategyAuth0 {
+		return nil
+	}
+	if _, ok := d.GetOk("options_json"); ok {
+		return nil
+	}
+	scripts, known, err := plannedConnectionCustomScripts(d)
+	if err != nil {
+		return err
+	}
+	if !known {
+		return d.SetNewComputed("custom_scripts_hashes")
+	}
+	return d.SetNew("custom_scripts_hashes", connectionCustomScriptsHashes(scripts))
+}
+
+// plannedConnectionCustomScripts returns the custom database scripts of an
+// auth0 connection once the plan is applied, reading them from
+// custom_scripts_directory when it is set. known is false when they can't be
+// known until then.
+func plannedConnectionCustomScripts(d *schema.ResourceDiff) (scripts map[string]interface{}, known bool, err error) {
+	for _, key := range []string{"auth0_options.0.custom_scripts", "auth0_options.0.custom_scripts_directory"} {
+		if !d.NewValueKnown(key) {
+			return nil, false, nil
+		}
+	}
+	if dir, ok := d.GetOk("auth0_options.0.custom_scripts_directory"); ok {
+		scripts, err := readConnectionCustomScripts(dir.(string))
+		return scripts, err == nil, err
+	}
+	scripts, _ = d.Get("auth0_options.0.custom_scripts").(map[string]interface{})
+	return scripts, true, nil
+}
+
+// connectionOptionSet reports whether an option is set, or will be once its
+// value is known. Computed options are only considered set when they change,
+// as their value is kept in state when they are removed from the
+// configuration, and is unknown until the connection is created. Options set
+// to their default value are not considered set.
+func connectionOptionSet(d *schema.ResourceDiff, block, key string) bool {
+	s := connectionOptionsSchema[key]
+	k := block + ".0." + key
+	v, ok := d.GetOk(k)
+	if s.Computed {
+		return ok && d.HasChange(k)
+	}
+	if s.DefaultFunc != nil {
+		if dv, err := s.DefaultFunc(); err == nil && dv != nil && v == dv {
+			return false
+		}
+	}
+	return ok || !d.NewValueKnown(k)
+}
+
 func createConnection(d *schema.ResourceData, m interface{}) error {
 	c := expandConnection(d)
-	api := m.(*management.Management)
-	if err := api.Connection.Create(c); err != nil {
+	api := m.(*config).api
+	if err := api.Request(http.MethodPost, api.URI("connections"), c); err != nil {
 		return err
 	}
 	d.SetId(auth0.StringValue(c.ID))
+	if err := updateManagedEnabledClients(d, m, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
 	return readConnection(d, m)
 }
 
 func readConnection(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	c, err := api.Connection.Read(d.Id())
+	api := m.(*config).api
+	c := &connection{}
+	err := api.Request(http.MethodGet, api.URI("connections", d.Id()), c)
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
 			if mErr.Status() == http.StatusNotFound {
@@ -781,29 +1301,115 @@ func readConnection(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
+	if err := unmarshalConnectionOptions(c.Connection); err != nil {
+		return err
+	}
+
 	d.SetId(auth0.StringValue(c.ID))
 	d.Set("name", c.Name)
 	d.Set("display_name", c.DisplayName)
 	d.Set("is_domain_connection", c.IsDomainConnection)
 	d.Set("strategy", c.Strategy)
-	d.Set("options", flattenConnectionOptions(d, c.Options))
-	d.Set("enabled_clients", c.EnabledClients)
+	if v, ok := d.GetOk("options_json"); ok {
+		o, err := flattenConnectionOptionsJSON(string(c.RawOptions), v.(string))
+		if err != nil {
+			return err
+		}
+		d.Set("options_json", o)
+	} else if block, ok := connectionOptionsBlocks[c.GetStrategy()]; ok {
+		options := flattenConnectionOptions(d, c.Options, m.(*config).secrets)
+		if o, ok := options[0].(map[string]interface{}); ok && connectionStrategiesEnterprise[c.GetStrategy()] {
+			o["show_as_button"] = c.GetShowAsButton()
+		}
+		d.Set(block, options)
+	}
+	if o, ok := c.Options.(*management.ConnectionOptions); ok {
+		d.Set("custom_scripts_hashes", connectionCustomScriptsHashes(o.CustomScripts))
+	}
+	if _, ok := d.GetOkExists("enabled_clients_authoritative"); !ok {
+		d.Set("enabled_clients_authoritative", true)
+	}
+	if d.Get("enabled_clients_authoritative").(bool) {
+		d.Set("enabled_clients", c.EnabledClients)
+	} else {
+		d.Set("enabled_clients", managedEnabledClients(d, c.EnabledClients))
+	}
 	d.Set("realms", c.Realms)
 	return nil
 }
 
+// managedEnabledClients returns the enabled clients of a connection which are
+// listed in its configuration, so that those enabled by others aren't shown as
+// changes when enabled_clients isn't authoritative.
+func managedEnabledClients(d *schema.ResourceData, enabled []interface{}) []interface{} {
+	listed := d.Get("enabled_clients").(*schema.Set)
+	managed := make([]interface{}, 0, listed.Len())
+	for _, id := range enabled {
+		if listed.Contains(id) {
+			managed = append(managed, id)
+		}
+	}
+	return managed
+}
+
+// updateManagedEnabledClients enables and disables the clients added to and
+// removed from enabled_clients, one at a time, leaving the other clients of
+// the connection in place. It is used instead of sending the list when
+// enabled_clients isn't authoritative.
+func updateManagedEnabledClients(d *schema.ResourceData, m interface{}, timeout time.Duration) error {
+	if d.Get("enabled_clients_authoritative").(bool) || !d.HasChange("enabled_clients") {
+		return nil
+	}
+	o, n := d.GetChange("enabled_clients")
+	removed := o.(*schema.Set).Difference(n.(*schema.Set))
+	added := n.(*schema.Set).Difference(o.(*schema.Set))
+
+	var errs *multierror.Error
+	for _, id := range removed.List() {
+		errs = multierror.Append(errs, updateConnectionEnabledClients(timeout, m, d.Id(), id.(string), false))
+	}
+	for _, id := range added.List() {
+		errs = multierror.Append(errs, updateConnectionEnabledClients(timeout, m, d.Id(), id.(string), true))
+	}
+	return errs.ErrorOrNil()
+}
+
 func updateConnection(d *schema.ResourceData, m interface{}) error {
 	c := expandConnection(d)
-	api := m.(*management.Management)
-	err := api.Connection.Update(d.Id(), c)
+	api := m.(*config).api
+	if err := keepConnectionTwilioToken(d, api, c.Connection); err != nil {
+		return err
+	}
+	err := api.Request(http.MethodPatch, api.URI("connections", d.Id()), c)
 	if err != nil {
 		return err
 	}
+	if err := updateManagedEnabledClients(d, m, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return err
+	}
 	return readConnection(d, m)
 }
 
+// keepConnectionTwilioToken sets the Twilio token of an sms connection to its
+// current value when only its hash is known. The options of a connection are
+// replaced as a whole, so the token can't simply be omitted.
+func keepConnectionTwilioToken(d *schema.ResourceData, api *management.Management, c *management.Connection) error {
+	o, ok := c.Options.(*management.ConnectionOptionsSMS)
+	if !ok || !isSecretHash(d.Get("sms_options.0.twilio_token").(string)) {
+		return nil
+	}
+	current, err := api.Connection.Read(d.Id())
+	if err != nil {
+		return err
+	}
+	if co, ok := current.Options.(*management.ConnectionOptionsSMS); ok {
+		o.TwilioToken = co.TwilioToken
+	}
+	return nil
+}
+
 func deleteConnection(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Connection.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
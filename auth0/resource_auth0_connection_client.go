@@ -0,0 +1,229 @@
+package auth0
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"gopkg.in/auth0.v5/management"
+)
+
+var (
+	errEmptyConnectionClientID         = fmt.Errorf("ID cannot be empty")
+	errInvalidConnectionClientIDFormat = fmt.Errorf("ID must be formated as connection_id:client_id")
+)
+
+func newConnectionClient() *schema.Resource {
+	return &schema.Resource{
+
+		Create: createConnectionClient,
+		Read:   readConnectionClient,
+		Delete: deleteConnectionClient,
+		Importer: &schema.ResourceImporter{
+			State: importConnectionClient,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"connection_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the connection on which to enable the client",
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the client for which the connection is enabled",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the connection",
+			},
+			"strategy": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the connection, which indicates the identity provider",
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(2 * time.Minute),
+			Delete: schema.DefaultTimeout(2 * time.Minute),
+		},
+	}
+}
+
+func importConnectionClient(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	connectionID, clientID, err := getConnectionAndClientID(d)
+	if err != nil {
+		return []*schema.ResourceData{}, err
+	}
+
+	d.Set("connection_id", connectionID)
+	d.Set("client_id", clientID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func createConnectionClient(d *schema.ResourceData, m interface{}) error {
+	connectionID := d.Get("connection_id").(string)
+	clientID := d.Get("client_id").(string)
+
+	err := updateConnectionEnabledClients(d.Timeout(schema.TimeoutCreate), m, connectionID, clientID, true)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(connectionID + ":" + clientID)
+	return readConnectionClient(d, m)
+}
+
+func readConnectionClient(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	c, err := api.Connection.Read(d.Get("connection_id").(string))
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	if !containsClientID(c.EnabledClients, d.Get("client_id").(string)) {
+		log.Printf("[WARN] Client %s is no longer enabled on connection %s, removing from state", d.Get("client_id"), c.GetID())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", c.Name)
+	d.Set("strategy", c.Strategy)
+	return nil
+}
+
+func deleteConnectionClient(d *schema.ResourceData, m interface{}) error {
+	connectionID := d.Get("connection_id").(string)
+	clientID := d.Get("client_id").(string)
+
+	err := updateConnectionEnabledClients(d.Timeout(schema.TimeoutDelete), m, connectionID, clientID, false)
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// connectionEnabledClients is the payload used to patch the enabled clients of
+// a connection. Unlike management.Connection it allows sending an empty list,
+// which is needed to disable the last client of a connection.
+type connectionEnabledClients struct {
+	EnabledClients []string `json:"enabled_clients"`
+}
+
+// updateConnectionEnabledClients adds or removes clientID from the enabled
+// clients of a connection.
+//
+// The Management API offers no way to add or remove a single client, so the
+// full list is read, modified and written back. Writes coming from this
+// provider are serialized per connection. Writes made elsewhere between our
+// read and write are detected by reading the list back after the write, in
+// which case the operation is retried.
+func updateConnectionEnabledClients(timeout time.Duration, m interface{}, connectionID, clientID string, enable bool) error {
+
+	mutexKV.Lock(connectionID)
+	defer mutexKV.Unlock(connectionID)
+
+	api := m.(*config).api
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+
+		c, err := api.Connection.Read(connectionID, management.IncludeFields("id", "enabled_clients"))
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if containsClientID(c.EnabledClients, clientID) == enable {
+			return nil
+		}
+
+		expected := make([]string, 0, len(c.EnabledClients)+1)
+		for _, v := range c.EnabledClients {
+			if id, ok := v.(string); ok && id != clientID {
+				expected = append(expected, id)
+			}
+		}
+		if enable {
+			expected = append(expected, clientID)
+		}
+
+		log.Printf("[DEBUG] Updating enabled clients of connection %s: %v", connectionID, expected)
+
+		payload := &connectionEnabledClients{expected}
+		err = api.Request(http.MethodPatch, api.URI("connections", connectionID), payload)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		c, err = api.Connection.Read(connectionID, management.IncludeFields("id", "enabled_clients"))
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if !sameClientIDs(c.EnabledClients, expected) {
+			return resource.RetryableError(fmt.Errorf(
+				"enabled clients of connection %s were modified concurrently, retrying", connectionID))
+		}
+
+		return nil
+	})
+}
+
+func getConnectionAndClientID(d *schema.ResourceData) (string, string, error) {
+	rawID := d.Id()
+	if rawID == "" {
+		return "", "", errEmptyConnectionClientID
+	}
+
+	idPair := strings.Split(rawID, ":")
+	if len(idPair) != 2 || idPair[0] == "" || idPair[1] == "" {
+		return "", "", errInvalidConnectionClientIDFormat
+	}
+
+	return idPair[0], idPair[1], nil
+}
+
+func containsClientID(clients []interface{}, clientID string) bool {
+	for _, v := range clients {
+		if id, ok := v.(string); ok && id == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func sameClientIDs(clients []interface{}, expected []string) bool {
+	if len(clients) != len(expected) {
+		return false
+	}
+	for _, id := range expected {
+		if !containsClientID(clients, id) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package auth0
+
+import (
+	"testing"
+
+	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/random"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccConnectionClient(t *testing.T) {
+
+	rand := random.String(6)
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: random.Template(testAccConnectionClientConfigCreate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					random.TestCheckResourceAttr("auth0_connection_client.my_connection_client", "name", "Acceptance-Test-Connection-Client-{{.random}}", rand),
+					resource.TestCheckResourceAttr("auth0_connection_client.my_connection_client", "strategy", "auth0"),
+					resource.TestCheckResourceAttrPair("auth0_connection_client.my_connection_client", "client_id", "auth0_client.my_client", "id"),
+					resource.TestCheckResourceAttrPair("auth0_connection_client.my_other_connection_client", "client_id", "auth0_client.my_other_client", "id"),
+				),
+			},
+			{
+				Config: random.Template(testAccConnectionClientConfigUpdate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("auth0_connection_client.my_connection_client", "client_id", "auth0_client.my_client", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccConnectionClientAuxConfig = `
+
+resource "auth0_connection" "my_connection" {
+	name = "Acceptance-Test-Connection-Client-{{.random}}"
+	strategy = "auth0"
+	lifecycle {
+		ignore_changes = [ enabled_clients ]
+	}
+}
+
+resource "auth0_client" "my_client" {
+	name = "Acceptance Test - Connection Client - {{.random}}"
+}
+
+resource "auth0_client" "my_other_client" {
+	name = "Acceptance Test - Connection Client Other - {{.random}}"
+}
+`
+
+const testAccConnectionClientConfigCreate = testAccConnectionClientAuxConfig + `
+
+resource "auth0_connection_client" "my_connection_client" {
+	connection_id = "${auth0_connection.my_connection.id}"
+	client_id = "${auth0_client.my_client.id}"
+}
+
+resource "auth0_connection_client" "my_other_connection_client" {
+	connection_id = "${auth0_connection.my_connection.id}"
+	client_id = "${auth0_client.my_other_client.id}"
+}
+`
+
+const testAccConnectionClientConfigUpdate = testAccConnectionClientAuxConfig + `
+
+resource "auth0_connection_client" "my_connection_client" {
+	connection_id = "${auth0_connection.my_connection.id}"
+	client_id = "${auth0_client.my_client.id}"
+}
+`
+
+func TestConnectionClientIDs(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		clients  []interface{}
+		expected []string
+		same     bool
+	}{
+		{
+			name:     "Empty",
+			clients:  []interface{}{},
+			expected: []string{},
+			same:     true,
+		},
+		{
+			name:     "SameOrder",
+			clients:  []interface{}{"foo", "bar"},
+			expected: []string{"foo", "bar"},
+			same:     true,
+		},
+		{
+			name:     "DifferentOrder",
+			clients:  []interface{}{"bar", "foo"},
+			expected: []string{"foo", "bar"},
+			same:     true,
+		},
+		{
+			name:     "Added",
+			clients:  []interface{}{"foo", "bar", "baz"},
+			expected: []string{"foo", "bar"},
+			same:     false,
+		},
+		{
+			name:     "Removed",
+			clients:  []interface{}{"foo"},
+			expected: []string{"foo", "bar"},
+			same:     false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := sameClientIDs(tt.clients, tt.expected); same != tt.same {
+				t.Fatalf("expected sameClientIDs(%v, %v) to be %v", tt.clients, tt.expected, tt.same)
+			}
+		})
+	}
+}
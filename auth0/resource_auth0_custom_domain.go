@@ -1,7 +1,9 @@
 package auth0
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -15,6 +17,7 @@ func newCustomDomain() *schema.Resource {
 
 		Create: createCustomDomain,
 		Read:   readCustomDomain,
+		Update: updateCustomDomain,
 		Delete: deleteCustomDomain,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -64,13 +67,76 @@ func newCustomDomain() *schema.Resource {
 					},
 				},
 			},
+			"verification_records": customDomainVerificationRecordsSchema(),
+			"custom_client_ip_header": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"true-client-ip",
+					"cf-connecting-ip",
+					"x-forwarded-for",
+					"x-azure-clientip",
+				}, false),
+				Description: "The HTTP header to fetch the client's IP address from. " +
+					"Options include `true-client-ip`, `cf-connecting-ip`, `x-forwarded-for` and `x-azure-clientip`",
+			},
+			"tls_policy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"compatible",
+					"recommended",
+				}, false),
+				Description: "The TLS version policy. Options include `compatible` and `recommended`",
+			},
+			"origin_domain_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The domain name the custom domain should point to, for example with a CNAME record. Only set for self_managed_certs",
+			},
+		},
+	}
+}
+
+func customDomainVerificationRecordsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The type of the DNS record, either `CNAME` or `TXT`",
+				},
+				"name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The name of the DNS record",
+				},
+				"value": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The value of the DNS record",
+				},
+			},
 		},
+		Description: "The DNS records to create in order to verify the custom domain",
 	}
 }
 
+// customDomain extends management.CustomDomain with the fields which are not
+// yet available in the management package.
+type customDomain struct {
+	management.CustomDomain
+	OriginDomainName *string `json:"origin_domain_name,omitempty"`
+	CNAMEAPIKey      *string `json:"cname_api_key,omitempty"`
+}
+
 func createCustomDomain(d *schema.ResourceData, m interface{}) error {
 	c := buildCustomDomain(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.CustomDomain.Create(c); err != nil {
 		return err
 	}
@@ -79,8 +145,9 @@ func createCustomDomain(d *schema.ResourceData, m interface{}) error {
 }
 
 func readCustomDomain(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	c, err := api.CustomDomain.Read(d.Id())
+	api := m.(*config).api
+	c := &customDomain{}
+	err := api.Request(http.MethodGet, api.URI("custom-domains", d.Id()), c)
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
 			if mErr.Status() == http.StatusNotFound {
@@ -96,18 +163,37 @@ func readCustomDomain(d *schema.ResourceData, m interface{}) error {
 	d.Set("type", c.Type)
 	d.Set("primary", c.Primary)
 	d.Set("status", c.Status)
+	d.Set("custom_client_ip_header", c.CustomClientIPHeader)
+	d.Set("tls_policy", c.TLSPolicy)
+	d.Set("origin_domain_name", c.OriginDomainName)
 
 	if c.Verification != nil {
 		d.Set("verification", []map[string]interface{}{
 			{"methods": c.Verification.Methods},
 		})
 	}
+	d.Set("verification_records", flattenCustomDomainVerificationRecords(c))
 
 	return nil
 }
 
+func updateCustomDomain(d *schema.ResourceData, m interface{}) error {
+	c := &management.CustomDomain{
+		TLSPolicy: String(d, "tls_policy", HasChange()),
+	}
+	// An empty header is sent as is, so that it can be unset.
+	if d.HasChange("custom_client_ip_header") {
+		c.CustomClientIPHeader = auth0.String(d.Get("custom_client_ip_header").(string))
+	}
+	api := m.(*config).api
+	if err := api.CustomDomain.Update(d.Id(), c); err != nil {
+		return err
+	}
+	return readCustomDomain(d, m)
+}
+
 func deleteCustomDomain(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.CustomDomain.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -122,8 +208,33 @@ func deleteCustomDomain(d *schema.ResourceData, m interface{}) error {
 
 func buildCustomDomain(d *schema.ResourceData) *management.CustomDomain {
 	return &management.CustomDomain{
-		Domain:             String(d, "domain"),
-		Type:               String(d, "type"),
-		VerificationMethod: String(d, "verification_method"),
+		Domain:               String(d, "domain"),
+		Type:                 String(d, "type"),
+		VerificationMethod:   String(d, "verification_method"),
+		TLSPolicy:            String(d, "tls_policy"),
+		CustomClientIPHeader: String(d, "custom_client_ip_header"),
+	}
+}
+
+// flattenCustomDomainVerificationRecords turns the verification methods of the
+// custom domain into DNS records, which can be fed into DNS provider resources.
+func flattenCustomDomainVerificationRecords(c *customDomain) []interface{} {
+	var records []interface{}
+	if c.Verification == nil {
+		return records
+	}
+	for _, method := range c.Verification.Methods {
+		record := map[string]interface{}{
+			"type":  strings.ToUpper(fmt.Sprint(method["name"])),
+			"name":  c.GetDomain(),
+			"value": method["record"],
+		}
+		// The TXT record is usually created on a sub domain of the custom
+		// domain, which is given by the domain of the method.
+		if domain, ok := method["domain"].(string); ok && domain != "" {
+			record["name"] = domain
+		}
+		records = append(records, record)
 	}
+	return records
 }
@@ -2,6 +2,7 @@ package auth0
 
 import (
 	"log"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -9,6 +10,9 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
 )
 
 func init() {
@@ -52,6 +56,26 @@ func TestAccCustomDomain(t *testing.T) {
 					random.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "domain", "{{.random}}.auth.uat.alexkappa.com", rand),
 					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "type", "auth0_managed_certs"),
 					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "status", "pending_verification"),
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "verification_records.0.type", "CNAME"),
+					random.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "verification_records.0.name", "{{.random}}.auth.uat.alexkappa.com", rand),
+				),
+			},
+			{
+				Config: random.Template(testAccCustomDomainSelfManaged, rand),
+				Check: resource.ComposeTestCheckFunc(
+					random.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "domain", "{{.random}}.auth.uat.alexkappa.com", rand),
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "type", "self_managed_certs"),
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "custom_client_ip_header", "cf-connecting-ip"),
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "tls_policy", "compatible"),
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "verification_records.0.type", "TXT"),
+					resource.TestCheckResourceAttrSet("auth0_custom_domain.my_custom_domain", "origin_domain_name"),
+				),
+			},
+			{
+				Config: random.Template(testAccCustomDomainSelfManagedUpdate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "custom_client_ip_header", ""),
+					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "tls_policy", "recommended"),
 				),
 			},
 		},
@@ -65,3 +89,42 @@ resource "auth0_custom_domain" "my_custom_domain" {
   type = "auth0_managed_certs"
 }
 `
+
+const testAccCustomDomainSelfManaged = `
+
+resource "auth0_custom_domain" "my_custom_domain" {
+  domain = "{{.random}}.auth.uat.alexkappa.com"
+  type = "self_managed_certs"
+  custom_client_ip_header = "cf-connecting-ip"
+  tls_policy = "compatible"
+}
+`
+
+const testAccCustomDomainSelfManagedUpdate = `
+
+resource "auth0_custom_domain" "my_custom_domain" {
+  domain = "{{.random}}.auth.uat.alexkappa.com"
+  type = "self_managed_certs"
+  tls_policy = "recommended"
+}
+`
+
+func TestFlattenCustomDomainVerificationRecords(t *testing.T) {
+	c := &customDomain{}
+	c.Domain = auth0.String("login.example.com")
+	c.Verification = &management.CustomDomainVerification{
+		Methods: []map[string]interface{}{
+			{"name": "cname", "record": "example-cd-xxxx.edge.tenants.auth0.com"},
+			{"name": "txt", "record": "auth0-domain-verification=xxxx", "domain": "_cf-custom-hostname.login.example.com"},
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"type": "CNAME", "name": "login.example.com", "value": "example-cd-xxxx.edge.tenants.auth0.com"},
+		map[string]interface{}{"type": "TXT", "name": "_cf-custom-hostname.login.example.com", "value": "auth0-domain-verification=xxxx"},
+	}
+
+	if records := flattenCustomDomainVerificationRecords(c); !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %v, got %v", expected, records)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -28,6 +29,18 @@ func newCustomDomainVerification() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"origin_domain_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The domain name the custom domain should point to. Only set for self_managed_certs",
+			},
+			"cname_api_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The value of the cname-api-key header to send when forwarding requests. Only set for self_managed_certs",
+			},
+			"verification_records": customDomainVerificationRecordsSchema(),
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -37,24 +50,45 @@ func newCustomDomainVerification() *schema.Resource {
 }
 
 func createCustomDomainVerification(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
+
+	// The ID and the records are set before waiting for the verification, so
+	// that they are kept in state should it time out.
+	d.SetId(d.Get("custom_domain_id").(string))
+
 	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		c, err := api.CustomDomain.Verify(d.Get("custom_domain_id").(string))
+		c := &customDomain{}
+		err := api.Request(http.MethodPost, api.URI("custom-domains", d.Id(), "verify"), c)
 		if err != nil {
 			return resource.NonRetryableError(err)
 		}
+		d.Set("verification_records", flattenCustomDomainVerificationRecords(c))
 		if c.GetStatus() != "ready" {
-			return resource.RetryableError(fmt.Errorf("Custom domain has status %q", c.GetStatus()))
+			err := fmt.Errorf("Custom domain has status %q, waiting for the DNS records %s",
+				c.GetStatus(), formatCustomDomainVerificationRecords(c))
+			log.Printf("[INFO] %s", err)
+			return resource.RetryableError(err)
 		}
 		log.Printf("[INFO] Custom domain %s verified", c.GetDomain())
-		d.SetId(c.GetID())
+		// The key is only returned when verifying the custom domain.
+		d.Set("cname_api_key", c.CNAMEAPIKey)
 		return resource.NonRetryableError(readCustomDomainVerification(d, m))
 	})
 }
 
+func formatCustomDomainVerificationRecords(c *customDomain) string {
+	var records []string
+	for _, r := range flattenCustomDomainVerificationRecords(c) {
+		record := r.(map[string]interface{})
+		records = append(records, fmt.Sprintf("%s %s %v", record["type"], record["name"], record["value"]))
+	}
+	return strings.Join(records, ", ")
+}
+
 func readCustomDomainVerification(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	c, err := api.CustomDomain.Read(d.Id())
+	api := m.(*config).api
+	c := &customDomain{}
+	err := api.Request(http.MethodGet, api.URI("custom-domains", d.Id()), c)
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
 			if mErr.Status() == http.StatusNotFound {
@@ -65,6 +99,8 @@ func readCustomDomainVerification(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 	d.Set("custom_domain_id", c.GetID())
+	d.Set("origin_domain_name", c.OriginDomainName)
+	d.Set("verification_records", flattenCustomDomainVerificationRecords(c))
 	return nil
 }
 
@@ -19,6 +19,7 @@ func TestAccCustomDomainVerification(t *testing.T) {
 					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "domain", "terraform-provider.auth0.com"),
 					resource.TestCheckResourceAttr("auth0_custom_domain.my_custom_domain", "type", "auth0_managed_certs"),
 					resource.TestCheckResourceAttrSet("auth0_custom_domain_verification.my_custom_domain_verification", "custom_domain_id"),
+					resource.TestCheckResourceAttr("auth0_custom_domain_verification.my_custom_domain_verification", "verification_records.0.type", "CNAME"),
 				),
 			},
 		},
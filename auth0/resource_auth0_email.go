@@ -1,15 +1,18 @@
 package auth0
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
 	"gopkg.in/auth0.v5"
 	"gopkg.in/auth0.v5/management"
 )
 
-func newEmail() *schema.Resource {
+func newEmail(secrets *secretHasher) *schema.Resource {
 	return &schema.Resource{
 
 		Create: createEmail,
@@ -19,11 +22,20 @@ func newEmail() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateEmail,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"mailgun",
+					"mandrill",
+					"sendgrid",
+					"ses",
+					"smtp",
+					"sparkpost",
+				}, false),
 			},
 			"enabled": {
 				Type:     schema.TypeBool,
@@ -44,22 +56,25 @@ func newEmail() *schema.Resource {
 							Optional: true,
 						},
 						"api_key": {
-							Type:      schema.TypeString,
-							Optional:  true,
-							Sensitive: true,
-							ForceNew:  true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressEmailSecretDiff(secrets),
 						},
 						"access_key_id": {
-							Type:      schema.TypeString,
-							Optional:  true,
-							Sensitive: true,
-							ForceNew:  true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressEmailSecretDiff(secrets),
 						},
 						"secret_access_key": {
-							Type:      schema.TypeString,
-							Optional:  true,
-							Sensitive: true,
-							ForceNew:  true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressEmailSecretDiff(secrets),
 						},
 						"region": {
 							Type:     schema.TypeString,
@@ -82,10 +97,11 @@ func newEmail() *schema.Resource {
 							Optional: true,
 						},
 						"smtp_pass": {
-							Type:      schema.TypeString,
-							Optional:  true,
-							Sensitive: true,
-							ForceNew:  true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							Sensitive:        true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressEmailSecretDiff(secrets),
 						},
 					},
 				},
@@ -96,7 +112,7 @@ func newEmail() *schema.Resource {
 
 func createEmail(d *schema.ResourceData, m interface{}) error {
 	e := buildEmail(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Email.Create(e); err != nil {
 		return err
 	}
@@ -105,7 +121,8 @@ func createEmail(d *schema.ResourceData, m interface{}) error {
 }
 
 func readEmail(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
+	secrets := m.(*config).secrets
 	e, err := api.Email.Read()
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -123,17 +140,19 @@ func readEmail(d *schema.ResourceData, m interface{}) error {
 	d.Set("default_from_address", e.DefaultFromAddress)
 
 	if credentials := e.Credentials; credentials != nil {
+		// Secret credentials are not returned, so the configured values, or
+		// their hashes, are kept.
 		credentialsMap := make(map[string]interface{})
 		credentialsMap["api_user"] = credentials.APIUser
-		credentialsMap["api_key"] = d.Get("credentials.0.api_key")
-		credentialsMap["access_key_id"] = d.Get("credentials.0.access_key_id")
-		credentialsMap["secret_access_key"] = d.Get("credentials.0.secret_access_key")
+		credentialsMap["api_key"] = secrets.hash(d.Get("credentials.0.api_key").(string))
+		credentialsMap["access_key_id"] = secrets.hash(d.Get("credentials.0.access_key_id").(string))
+		credentialsMap["secret_access_key"] = secrets.hash(d.Get("credentials.0.secret_access_key").(string))
 		credentialsMap["region"] = credentials.Region
 		credentialsMap["domain"] = credentials.Domain
 		credentialsMap["smtp_host"] = credentials.SMTPHost
 		credentialsMap["smtp_port"] = credentials.SMTPPort
 		credentialsMap["smtp_user"] = credentials.SMTPUser
-		credentialsMap["smtp_pass"] = d.Get("credentials.0.smtp_pass")
+		credentialsMap["smtp_pass"] = secrets.hash(d.Get("credentials.0.smtp_pass").(string))
 		d.Set("credentials", []map[string]interface{}{credentialsMap})
 	}
 
@@ -142,7 +161,7 @@ func readEmail(d *schema.ResourceData, m interface{}) error {
 
 func updateEmail(d *schema.ResourceData, m interface{}) error {
 	e := buildEmail(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Email.Update(e)
 	if err != nil {
 		return err
@@ -151,7 +170,7 @@ func updateEmail(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteEmail(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Email.Delete()
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -171,36 +190,118 @@ func buildEmail(d *schema.ResourceData) *management.Email {
 		DefaultFromAddress: String(d, "default_from_address"),
 	}
 
-	List(d, "credentials").Elem(func(d ResourceData) {
-		// e.Credentials = buildEmailCredentials(v.(map[string]interface{}))
+	// Secret credentials whose hash is stored in state are not known, so the
+	// credentials are only sent when they change, in which case the secrets
+	// are known, see suppressEmailSecretDiff.
+	List(d, "credentials", IsNewResource(), HasChange()).Elem(func(d ResourceData) {
 		e.Credentials = &management.EmailCredentials{
 			APIUser:         String(d, "api_user"),
-			APIKey:          String(d, "api_key"),
-			AccessKeyID:     String(d, "access_key_id"),
-			SecretAccessKey: String(d, "secret_access_key"),
+			APIKey:          Secret(d, "api_key"),
+			AccessKeyID:     Secret(d, "access_key_id"),
+			SecretAccessKey: Secret(d, "secret_access_key"),
 			Region:          String(d, "region"),
 			Domain:          String(d, "domain"),
 			SMTPHost:        String(d, "smtp_host"),
 			SMTPPort:        Int(d, "smtp_port"),
 			SMTPUser:        String(d, "smtp_user"),
-			SMTPPass:        String(d, "smtp_pass"),
+			SMTPPass:        Secret(d, "smtp_pass"),
 		}
 	})
 
 	return e
 }
 
-func buildEmailCredentials(m map[string]interface{}) *management.EmailCredentials {
-	return &management.EmailCredentials{
-		APIUser:         String(MapData(m), "api_user"),
-		APIKey:          String(MapData(m), "api_key"),
-		AccessKeyID:     String(MapData(m), "access_key_id"),
-		SecretAccessKey: String(MapData(m), "secret_access_key"),
-		Region:          String(MapData(m), "region"),
-		Domain:          String(MapData(m), "domain"),
-		SMTPHost:        String(MapData(m), "smtp_host"),
-		SMTPPort:        Int(MapData(m), "smtp_port"),
-		SMTPUser:        String(MapData(m), "smtp_user"),
-		SMTPPass:        String(MapData(m), "smtp_pass"),
+// suppressEmailSecretDiff suppresses the diff of a secret credential whose
+// hash is unchanged, unless other credentials change. The credentials are
+// sent as a whole, and the value of the secret is only known when its diff is
+// kept.
+func suppressEmailSecretDiff(secrets *secretHasher) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if !secrets.suppressDiff(k, old, new, d) {
+			return false
+		}
+		for _, key := range []string{"api_user", "region", "domain", "smtp_host", "smtp_port", "smtp_user"} {
+			if d.HasChange("credentials.0." + key) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// emailProviderCredentials lists, for each supported email provider, the
+// credential fields which are required and those which are accepted.
+var emailProviderCredentials = map[string]struct {
+	required []string
+	optional []string
+}{
+	"mailgun":   {required: []string{"api_key", "domain"}, optional: []string{"region"}},
+	"mandrill":  {required: []string{"api_key"}},
+	"sendgrid":  {required: []string{"api_key"}, optional: []string{"api_user"}},
+	"ses":       {required: []string{"access_key_id", "secret_access_key", "region"}},
+	"smtp":      {required: []string{"smtp_host", "smtp_port", "smtp_user", "smtp_pass"}},
+	"sparkpost": {required: []string{"api_key"}, optional: []string{"region"}},
+}
+
+var emailCredentialFields = []string{
+	"api_user",
+	"api_key",
+	"access_key_id",
+	"secret_access_key",
+	"region",
+	"domain",
+	"smtp_host",
+	"smtp_port",
+	"smtp_user",
+	"smtp_pass",
+}
+
+func validateEmail(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("name") {
+		return nil
+	}
+	name := d.Get("name").(string)
+	return validateEmailCredentials(name, func(field string) (set bool, known bool) {
+		key := "credentials.0." + field
+		if !d.NewValueKnown(key) {
+			return false, false
+		}
+		_, ok := d.GetOk(key)
+		return ok, true
+	})
+}
+
+// validateEmailCredentials checks that every credential field required by the
+// email provider is set, and that no field which the provider doesn't accept
+// is. Fields whose value is not yet known are skipped.
+func validateEmailCredentials(name string, isSet func(field string) (set bool, known bool)) error {
+	provider, ok := emailProviderCredentials[name]
+	if !ok {
+		return nil
+	}
+
+	var errs []string
+	for _, field := range provider.required {
+		if set, known := isSet(field); known && !set {
+			errs = append(errs, fmt.Sprintf("credentials.%s is required when using the %q email provider", field, name))
+		}
+	}
+
+	accepted := make(map[string]bool)
+	for _, field := range append(provider.required, provider.optional...) {
+		accepted[field] = true
 	}
+	for _, field := range emailCredentialFields {
+		if accepted[field] {
+			continue
+		}
+		if set, _ := isSet(field); set {
+			errs = append(errs, fmt.Sprintf("credentials.%s is not supported by the %q email provider", field, name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid email provider configuration:\n\t%s", strings.Join(errs, "\n\t"))
+	}
+	return nil
 }
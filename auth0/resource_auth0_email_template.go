@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
@@ -21,6 +22,7 @@ func newEmailTemplate() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateEmailTemplate,
 
 		Schema: map[string]*schema.Schema{
 			"template": {
@@ -41,20 +43,23 @@ func newEmailTemplate() *schema.Resource {
 				}, true),
 			},
 			"body": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLiquidWarnings(),
 			},
 			"from": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLiquidWarnings(),
 			},
 			"result_url": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 			"subject": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLiquidWarnings(),
 			},
 			"syntax": {
 				Type:     schema.TypeString,
@@ -72,9 +77,29 @@ func newEmailTemplate() *schema.Resource {
 	}
 }
 
+// validateEmailTemplate checks the Liquid syntax of the templates with the
+// liquid syntax, and that they only use the variables of their template.
+func validateEmailTemplate(d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("syntax").(string) != "liquid" || !d.NewValueKnown("template") {
+		return nil
+	}
+	template := d.Get("template").(string)
+
+	var errs *multierror.Error
+	for _, key := range []string{"body", "subject", "from"} {
+		if !d.NewValueKnown(key) {
+			continue
+		}
+		if _, err := parseLiquidTemplate(key, template, d.Get(key).(string)); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
 func createEmailTemplate(d *schema.ResourceData, m interface{}) error {
 	e := buildEmailTemplate(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 
 	// The email template resource doesn't allow deleting templates, so in order
 	// to avoid conflicts, we first attempt to read the template. If it exists
@@ -101,7 +126,7 @@ func createEmailTemplate(d *schema.ResourceData, m interface{}) error {
 }
 
 func readEmailTemplate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	e, err := api.EmailTemplate.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -126,7 +151,7 @@ func readEmailTemplate(d *schema.ResourceData, m interface{}) error {
 
 func updateEmailTemplate(d *schema.ResourceData, m interface{}) error {
 	e := buildEmailTemplate(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.EmailTemplate.Update(d.Id(), e)
 	if err != nil {
 		return err
@@ -135,7 +160,7 @@ func updateEmailTemplate(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteEmailTemplate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	t := &management.EmailTemplate{
 		Template: auth0.String(d.Id()),
 		Enabled:  auth0.Bool(false),
@@ -1,9 +1,12 @@
 package auth0
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
@@ -92,6 +95,195 @@ func TestAccEmail(t *testing.T) {
 					resource.TestCheckResourceAttr("auth0_email.my_email_provider", "credentials.0.region", "eu"),
 				),
 			},
+			{
+				Config: `
+				resource "auth0_email" "my_email_provider" {
+					name = "smtp"
+					enabled = true
+					default_from_address = "accounts@example.com"
+					credentials {
+						smtp_host = "smtp.example.com"
+						smtp_port = 587
+						smtp_user = "accounts"
+						smtp_pass = "SMTPPASSXXXXXXXXXXXXXX"
+					}
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_email.my_email_provider", "name", "smtp"),
+					resource.TestCheckResourceAttr("auth0_email.my_email_provider", "credentials.0.smtp_host", "smtp.example.com"),
+					resource.TestCheckResourceAttr("auth0_email.my_email_provider", "credentials.0.smtp_port", "587"),
+					resource.TestCheckResourceAttr("auth0_email.my_email_provider", "credentials.0.smtp_user", "accounts"),
+				),
+			},
+			{
+				Config: `
+				resource "auth0_email" "my_email_provider" {
+					name = "mailgun"
+					enabled = true
+					default_from_address = "accounts@example.com"
+					credentials {
+						api_key = "MAILGUNXXXXXXXXXXXXXXX"
+					}
+				}
+				`,
+				ExpectError: regexp.MustCompile("credentials.domain is required"),
+			},
+		},
+	})
+}
+
+func TestValidateEmailCredentials(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		provider string
+		fields   map[string]bool
+		err      string
+	}{
+		{
+			name:     "SESComplete",
+			provider: "ses",
+			fields:   map[string]bool{"access_key_id": true, "secret_access_key": true, "region": true},
 		},
+		{
+			name:     "SESMissingRegion",
+			provider: "ses",
+			fields:   map[string]bool{"access_key_id": true, "secret_access_key": true},
+			err:      "credentials.region is required",
+		},
+		{
+			name:     "MailgunComplete",
+			provider: "mailgun",
+			fields:   map[string]bool{"api_key": true, "domain": true, "region": true},
+		},
+		{
+			name:     "MailgunUnsupportedSMTPHost",
+			provider: "mailgun",
+			fields:   map[string]bool{"api_key": true, "domain": true, "smtp_host": true},
+			err:      "credentials.smtp_host is not supported",
+		},
+		{
+			name:     "SparkPostComplete",
+			provider: "sparkpost",
+			fields:   map[string]bool{"api_key": true},
+		},
+		{
+			name:     "SMTPMissingPassword",
+			provider: "smtp",
+			fields:   map[string]bool{"smtp_host": true, "smtp_port": true, "smtp_user": true},
+			err:      "credentials.smtp_pass is required",
+		},
+		{
+			name:     "SendGridComplete",
+			provider: "sendgrid",
+			fields:   map[string]bool{"api_key": true, "api_user": true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEmailCredentials(tt.provider, func(field string) (bool, bool) {
+				return tt.fields[field], true
+			})
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateEmailCredentialsUnknown(t *testing.T) {
+	// Values computed from other resources are not known at plan time and
+	// should not be reported as missing.
+	err := validateEmailCredentials("ses", func(field string) (bool, bool) {
+		return false, field != "secret_access_key"
 	})
+	if err == nil || strings.Contains(err.Error(), "secret_access_key") {
+		t.Fatalf("expected only known fields to be reported, got %v", err)
+	}
+}
+
+func TestBuildEmailHashedCredentials(t *testing.T) {
+	hasher := &secretHasher{key: []byte("key")}
+	r := newEmail(hasher)
+	state := &terraform.InstanceState{
+		ID: "mailgun",
+		Attributes: map[string]string{
+			"id":                    "mailgun",
+			"name":                  "mailgun",
+			"enabled":               "true",
+			"default_from_address":  "accounts@example.com",
+			"credentials.#":         "1",
+			"credentials.0.api_key": hasher.hash("MAILGUNXXXXXXXXXXXXXXX"),
+			"credentials.0.domain":  "example.com",
+			"credentials.0.region":  "eu",
+		},
+	}
+
+	for _, tt := range []struct {
+		name        string
+		config      map[string]interface{}
+		credentials bool
+		replaced    bool
+	}{
+		{
+			name: "CredentialsUnchanged",
+			config: map[string]interface{}{
+				"name":                 "mailgun",
+				"enabled":              false,
+				"default_from_address": "accounts@example.com",
+				"credentials": []interface{}{map[string]interface{}{
+					"api_key": "MAILGUNXXXXXXXXXXXXXXX",
+					"domain":  "example.com",
+					"region":  "eu",
+				}},
+			},
+		},
+		{
+			name: "DomainChanged",
+			config: map[string]interface{}{
+				"name":                 "mailgun",
+				"enabled":              true,
+				"default_from_address": "accounts@example.com",
+				"credentials": []interface{}{map[string]interface{}{
+					"api_key": "MAILGUNXXXXXXXXXXXXXXX",
+					"domain":  "mail.example.com",
+					"region":  "eu",
+				}},
+			},
+			credentials: true,
+			replaced:    true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := r.Diff(state, terraform.NewResourceConfigRaw(tt.config), &config{secrets: hasher})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff.RequiresNew() != tt.replaced {
+				t.Errorf("expected the email provider to be replaced: %t, got %t", tt.replaced, diff.RequiresNew())
+			}
+			if tt.replaced {
+				// The credentials are then sent by the create, where the
+				// secrets have their configured value.
+				if v := diff.Attributes["credentials.0.api_key"]; v == nil || v.New != "MAILGUNXXXXXXXXXXXXXXX" {
+					t.Errorf("expected the api_key to be known, got %v", v)
+				}
+				return
+			}
+
+			d, err := schema.InternalMap(r.Schema).Data(state, diff)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := buildEmail(d)
+			if (e.Credentials != nil) != tt.credentials {
+				t.Errorf("expected credentials to be sent: %t, got %v", tt.credentials, e.Credentials)
+			}
+		})
+	}
 }
@@ -10,10 +10,14 @@ import (
 
 func newGlobalClient() *schema.Resource {
 	client := newClient()
-	client.Create = createGlobalClient
+	client.Create = readLegacyPages(createGlobalClient, "custom_login_page")
+	client.Read = readLegacyPages(client.Read, "custom_login_page")
+	client.Update = readLegacyPages(client.Update, "custom_login_page")
 	client.Delete = deleteGlobalClient
 
-	exclude := []string{"client_secret_rotation_trigger"}
+	// The custom login page is only kept in state when it is configured, see
+	// validateLegacyPages.
+	exclude := []string{"client_secret_rotation_trigger", "custom_login_page"}
 
 	// Mark all values computed and optional. This because the global client has
 	// already been created for all tenants.
@@ -29,6 +33,15 @@ func newGlobalClient() *schema.Resource {
 		client.Schema[key].Computed = true
 	}
 
+	// The custom login page is managed by auth0_pages.
+	for _, key := range []string{"custom_login_page_on", "custom_login_page"} {
+		client.Schema[key].Deprecated = "Use the login page of the auth0_pages resource instead"
+	}
+	client.CustomizeDiff = validateLegacyPages("auth0_global_client", client.Schema, map[string]string{
+		"custom_login_page_on": "login",
+		"custom_login_page":    "login",
+	})
+
 	return client
 }
 
@@ -49,16 +62,23 @@ func createGlobalClient(d *schema.ResourceData, m interface{}) error {
 }
 
 func readGlobalClientId(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	clients, err := api.Client.List(management.Parameter("is_global", "true"), management.WithFields("client_id"))
+	id, err := globalClientID(m.(*config).api)
 	if err != nil {
 		return err
 	}
+	d.SetId(id)
+	return nil
+}
+
+func globalClientID(api *management.Management) (string, error) {
+	clients, err := api.Client.List(management.Parameter("is_global", "true"), management.WithFields("client_id"))
+	if err != nil {
+		return "", err
+	}
 	if len(clients.Clients) == 0 {
-		return errors.New("no auth0 global client found")
+		return "", errors.New("no auth0 global client found")
 	}
-	d.SetId(clients.Clients[0].GetClientID())
-	return nil
+	return clients.Clients[0].GetClientID(), nil
 }
 
 func deleteGlobalClient(d *schema.ResourceData, m interface{}) error {
@@ -1,13 +1,15 @@
 package auth0
 
 import (
+	"net/http"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"gopkg.in/auth0.v5/management"
 )
 
-func newGuardian() *schema.Resource {
+func newGuardian(secrets *secretHasher) *schema.Resource {
 	return &schema.Resource{
 
 		Create: createGuardian,
@@ -77,9 +79,10 @@ func newGuardian() *schema.Resource {
 										Optional: true,
 									},
 									"auth_token": {
-										Type:      schema.TypeString,
-										Sensitive: true,
-										Optional:  true,
+										Type:             schema.TypeString,
+										Sensitive:        true,
+										Optional:         true,
+										DiffSuppressFunc: secrets.suppressDiff,
 									},
 									"sid": {
 										Type:     schema.TypeString,
@@ -101,17 +104,210 @@ func newGuardian() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"webauthn_roaming": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				MinItems: 0,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_verification": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"discouraged",
+								"preferred",
+								"required",
+							}, false),
+						},
+						"override_relying_party": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+						},
+						"relying_party_identifier": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"webauthn_platform": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				MinItems: 0,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"override_relying_party": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+						},
+						"relying_party_identifier": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"push": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				MinItems: 0,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"provider": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"guardian",
+								"sns",
+								"direct",
+							}, false),
+						},
+						"amazon_sns": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"aws_access_key_id": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"aws_secret_access_key": {
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+									"aws_region": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"sns_apns_platform_application_arn": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"sns_gcm_platform_application_arn": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"direct_apns": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"bundle_id": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"p12": {
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+									"sandbox": {
+										Type:     schema.TypeBool,
+										Optional: true,
+									},
+								},
+							},
+						},
+						"direct_fcm": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"server_key": {
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"duo": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				MinItems: 0,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"integration_key": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"secret_key": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"recovery_code": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
 
+// The following types model the guardian factor settings which are not yet
+// available in the management package.
+
+type multiFactorWebAuthnSettings struct {
+	UserVerification       *string `json:"userVerification,omitempty"`
+	OverrideRelyingParty   *bool   `json:"overrideRelyingParty,omitempty"`
+	RelyingPartyIdentifier *string `json:"relyingPartyIdentifier,omitempty"`
+}
+
+type multiFactorDUOSettings struct {
+	Hostname       *string `json:"host,omitempty"`
+	IntegrationKey *string `json:"ikey,omitempty"`
+	SecretKey      *string `json:"skey,omitempty"`
+}
+
+type multiFactorPushProvider struct {
+	Provider *string `json:"provider,omitempty"`
+}
+
+type multiFactorProviderAPNS struct {
+	Sandbox  *bool   `json:"sandbox,omitempty"`
+	BundleID *string `json:"bundle_id,omitempty"`
+	P12      *string `json:"p12,omitempty"`
+}
+
+type multiFactorProviderFCM struct {
+	ServerKey *string `json:"server_key,omitempty"`
+}
+
 func createGuardian(d *schema.ResourceData, m interface{}) error {
 	d.SetId(resource.UniqueId())
 	return updateGuardian(d, m)
 }
 
 func deleteGuardian(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Guardian.MultiFactor.Phone.Enable(false); err != nil {
 		return err
 	}
@@ -121,12 +317,27 @@ func deleteGuardian(d *schema.ResourceData, m interface{}) error {
 	if err := api.Guardian.MultiFactor.OTP.Enable(false); err != nil {
 		return err
 	}
+	if err := api.Guardian.MultiFactor.WebAuthnRoaming.Enable(false); err != nil {
+		return err
+	}
+	if err := enableFactor(api, "webauthn-platform", false); err != nil {
+		return err
+	}
+	if err := api.Guardian.MultiFactor.Push.Enable(false); err != nil {
+		return err
+	}
+	if err := api.Guardian.MultiFactor.DUO.Enable(false); err != nil {
+		return err
+	}
+	if err := enableFactor(api, "recovery-code", false); err != nil {
+		return err
+	}
 	d.SetId("")
 	return nil
 }
 
 func updateGuardian(d *schema.ResourceData, m interface{}) (err error) {
-	api := m.(*management.Management)
+	api := m.(*config).api
 
 	if d.HasChange("policy") {
 		p := d.Get("policy").(string)
@@ -146,6 +357,21 @@ func updateGuardian(d *schema.ResourceData, m interface{}) (err error) {
 	if err := updateOTPFactor(d, api); err != nil {
 		return err
 	}
+	if err := updateWebAuthnRoamingFactor(d, api); err != nil {
+		return err
+	}
+	if err := updateWebAuthnPlatformFactor(d, api); err != nil {
+		return err
+	}
+	if err := updatePushFactor(d, api); err != nil {
+		return err
+	}
+	if err := updateDUOFactor(d, api); err != nil {
+		return err
+	}
+	if err := updateRecoveryCodeFactor(d, api); err != nil {
+		return err
+	}
 	return readGuardian(d, m)
 }
 
@@ -179,6 +405,140 @@ func updateOTPFactor(d *schema.ResourceData, api *management.Management) error {
 	return nil
 }
 
+func updateWebAuthnRoamingFactor(d *schema.ResourceData, api *management.Management) (err error) {
+	ok, err := factorShouldBeUpdated(d, "webauthn_roaming")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return api.Guardian.MultiFactor.WebAuthnRoaming.Enable(false)
+	}
+	if err := api.Guardian.MultiFactor.WebAuthnRoaming.Enable(true); err != nil {
+		return err
+	}
+	List(d, "webauthn_roaming").Elem(func(d ResourceData) {
+		err = api.Request(http.MethodPut, api.URI("guardian", "factors", "webauthn-roaming", "settings"), &multiFactorWebAuthnSettings{
+			UserVerification:       String(d, "user_verification"),
+			OverrideRelyingParty:   Bool(d, "override_relying_party"),
+			RelyingPartyIdentifier: String(d, "relying_party_identifier"),
+		})
+	})
+	return err
+}
+
+func updateWebAuthnPlatformFactor(d *schema.ResourceData, api *management.Management) (err error) {
+	ok, err := factorShouldBeUpdated(d, "webauthn_platform")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return enableFactor(api, "webauthn-platform", false)
+	}
+	if err := enableFactor(api, "webauthn-platform", true); err != nil {
+		return err
+	}
+	List(d, "webauthn_platform").Elem(func(d ResourceData) {
+		err = api.Request(http.MethodPut, api.URI("guardian", "factors", "webauthn-platform", "settings"), &multiFactorWebAuthnSettings{
+			OverrideRelyingParty:   Bool(d, "override_relying_party"),
+			RelyingPartyIdentifier: String(d, "relying_party_identifier"),
+		})
+	})
+	return err
+}
+
+func updatePushFactor(d *schema.ResourceData, api *management.Management) (err error) {
+	ok, err := factorShouldBeUpdated(d, "push")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return api.Guardian.MultiFactor.Push.Enable(false)
+	}
+	if err := api.Guardian.MultiFactor.Push.Enable(true); err != nil {
+		return err
+	}
+	List(d, "push").Elem(func(d ResourceData) {
+		if err = configurePushProviders(d, api); err != nil {
+			return
+		}
+		if p := String(d, "provider"); p != nil {
+			err = api.Request(http.MethodPut, api.URI("guardian", "factors", "push-notification", "selected-provider"), &multiFactorPushProvider{
+				Provider: p,
+			})
+		}
+	})
+	return err
+}
+
+func configurePushProviders(d ResourceData, api *management.Management) (err error) {
+	List(d, "amazon_sns", HasChange()).Elem(func(d ResourceData) {
+		err = api.Guardian.MultiFactor.Push.UpdateAmazonSNS(&management.MultiFactorProviderAmazonSNS{
+			AccessKeyID:                String(d, "aws_access_key_id"),
+			SecretAccessKeyID:          String(d, "aws_secret_access_key"),
+			Region:                     String(d, "aws_region"),
+			APNSPlatformApplicationARN: String(d, "sns_apns_platform_application_arn"),
+			GCMPlatformApplicationARN:  String(d, "sns_gcm_platform_application_arn"),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	List(d, "direct_apns", HasChange()).Elem(func(d ResourceData) {
+		err = api.Request(http.MethodPatch, api.URI("guardian", "factors", "push-notification", "providers", "apns"), &multiFactorProviderAPNS{
+			Sandbox:  Bool(d, "sandbox"),
+			BundleID: String(d, "bundle_id"),
+			P12:      String(d, "p12"),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	List(d, "direct_fcm", HasChange()).Elem(func(d ResourceData) {
+		err = api.Request(http.MethodPatch, api.URI("guardian", "factors", "push-notification", "providers", "fcm"), &multiFactorProviderFCM{
+			ServerKey: String(d, "server_key"),
+		})
+	})
+	return err
+}
+
+func updateDUOFactor(d *schema.ResourceData, api *management.Management) (err error) {
+	ok, err := factorShouldBeUpdated(d, "duo")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return api.Guardian.MultiFactor.DUO.Enable(false)
+	}
+	List(d, "duo").Elem(func(d ResourceData) {
+		err = api.Request(http.MethodPut, api.URI("guardian", "factors", "duo", "settings"), &multiFactorDUOSettings{
+			Hostname:       String(d, "hostname"),
+			IntegrationKey: String(d, "integration_key"),
+			SecretKey:      String(d, "secret_key"),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return api.Guardian.MultiFactor.DUO.Enable(true)
+}
+
+func updateRecoveryCodeFactor(d *schema.ResourceData, api *management.Management) error {
+	if changed := d.HasChange("recovery_code"); changed {
+		enabled := d.Get("recovery_code").(bool)
+		return enableFactor(api, "recovery-code", enabled)
+	}
+	return nil
+}
+
+// enableFactor enables or disables the factor by name. It is used for factors
+// which the management package can't enable, or enables incorrectly, as is the
+// case for webauthn-platform.
+func enableFactor(api *management.Management, name string, enabled bool) error {
+	return api.Request(http.MethodPut, api.URI("guardian", "factors", name), &management.MultiFactor{
+		Enabled: &enabled,
+	})
+}
+
 func configurePhone(d *schema.ResourceData, api *management.Management) (err error) {
 	md := make(MapData)
 	List(d, "phone").Elem(func(d ResourceData) {
@@ -224,15 +584,27 @@ func updateAuth0Options(opts Iterator, api *management.Management) (err error) {
 
 func updateTwilioOptions(opts Iterator, api *management.Management) error {
 	md := make(map[string]*string)
+	var keepToken bool
 	opts.Elem(func(d ResourceData) {
 		md["sid"] = String(d, "sid")
-		md["auth_token"] = String(d, "auth_token")
+		md["auth_token"] = Secret(d, "auth_token")
+		keepToken = md["auth_token"] == nil && isSecretHash(d.Get("auth_token").(string))
 		md["from"] = String(d, "from")
 		md["messaging_service_sid"] = String(d, "messaging_service_sid")
 		md["enrollment_message"] = String(d, "enrollment_message")
 		md["verification_message"] = String(d, "verification_message")
 	})
 
+	if keepToken {
+		// Only the hash of the token is known, so the current token is sent
+		// back as it is.
+		tw, err := api.Guardian.MultiFactor.SMS.Twilio()
+		if err != nil {
+			return err
+		}
+		md["auth_token"] = tw.AuthToken
+	}
+
 	err := api.Guardian.MultiFactor.SMS.UpdateTwilio(&management.MultiFactorProviderTwilio{
 		From:                md["from"],
 		MessagingServiceSid: md["messaging_service_sid"],
@@ -253,7 +625,7 @@ func updateTwilioOptions(opts Iterator, api *management.Management) error {
 }
 
 func readGuardian(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	mt, err := api.Guardian.MultiFactor.Phone.MessageTypes()
 	if err != nil {
 		return err
@@ -278,7 +650,7 @@ func readGuardian(d *schema.ResourceData, m interface{}) error {
 	var md map[string]interface{}
 	switch *prv.Provider {
 	case "twilio":
-		md, err = flattenTwilioOptions(api)
+		md, err = flattenTwilioOptions(api, m.(*config).secrets)
 	case "auth0":
 		md, err = flattenAuth0Options(api)
 	}
@@ -304,6 +676,7 @@ func readGuardian(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
+	enabled := make(map[string]bool)
 	for _, v := range factors {
 		if v.Name != nil {
 			if *v.Name == "email" {
@@ -312,11 +685,131 @@ func readGuardian(d *schema.ResourceData, m interface{}) error {
 			if *v.Name == "otp" {
 				d.Set("otp", v.Enabled)
 			}
+			if *v.Name == "recovery-code" {
+				d.Set("recovery_code", v.Enabled)
+			}
+			enabled[*v.Name] = v.GetEnabled()
 		}
 	}
+
+	var webAuthnRoaming []interface{}
+	if enabled["webauthn-roaming"] {
+		if webAuthnRoaming, err = flattenWebAuthnSettings(api, "webauthn-roaming"); err != nil {
+			return err
+		}
+	}
+	d.Set("webauthn_roaming", webAuthnRoaming)
+
+	var webAuthnPlatform []interface{}
+	if enabled["webauthn-platform"] {
+		if webAuthnPlatform, err = flattenWebAuthnSettings(api, "webauthn-platform"); err != nil {
+			return err
+		}
+		delete(webAuthnPlatform[0].(map[string]interface{}), "user_verification")
+	}
+	d.Set("webauthn_platform", webAuthnPlatform)
+
+	var push []interface{}
+	if enabled["push-notification"] {
+		if push, err = flattenPushOptions(d, api); err != nil {
+			return err
+		}
+	}
+	d.Set("push", push)
+
+	var duo []interface{}
+	if enabled["duo"] {
+		if duo, err = flattenDUOOptions(api); err != nil {
+			return err
+		}
+	}
+	d.Set("duo", duo)
+
 	return nil
 }
 
+func flattenWebAuthnSettings(api *management.Management, factor string) ([]interface{}, error) {
+	s := &multiFactorWebAuthnSettings{}
+	err := api.Request(http.MethodGet, api.URI("guardian", "factors", factor, "settings"), s)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"user_verification":        s.UserVerification,
+			"override_relying_party":   s.OverrideRelyingParty,
+			"relying_party_identifier": s.RelyingPartyIdentifier,
+		},
+	}, nil
+}
+
+func flattenPushOptions(d *schema.ResourceData, api *management.Management) ([]interface{}, error) {
+	md := make(map[string]interface{})
+
+	p := &multiFactorPushProvider{}
+	err := api.Request(http.MethodGet, api.URI("guardian", "factors", "push-notification", "selected-provider"), p)
+	if err != nil {
+		return nil, err
+	}
+	md["provider"] = p.Provider
+
+	if _, ok := d.GetOk("push.0.amazon_sns"); ok {
+		sns, err := api.Guardian.MultiFactor.Push.AmazonSNS()
+		if err != nil {
+			return nil, err
+		}
+		md["amazon_sns"] = []interface{}{
+			map[string]interface{}{
+				"aws_access_key_id":                 sns.AccessKeyID,
+				"aws_secret_access_key":             d.Get("push.0.amazon_sns.0.aws_secret_access_key"), // does not get read back
+				"aws_region":                        sns.Region,
+				"sns_apns_platform_application_arn": sns.APNSPlatformApplicationARN,
+				"sns_gcm_platform_application_arn":  sns.GCMPlatformApplicationARN,
+			},
+		}
+	}
+
+	if _, ok := d.GetOk("push.0.direct_apns"); ok {
+		apns := &multiFactorProviderAPNS{}
+		err := api.Request(http.MethodGet, api.URI("guardian", "factors", "push-notification", "providers", "apns"), apns)
+		if err != nil {
+			return nil, err
+		}
+		md["direct_apns"] = []interface{}{
+			map[string]interface{}{
+				"sandbox":   apns.Sandbox,
+				"bundle_id": apns.BundleID,
+				"p12":       d.Get("push.0.direct_apns.0.p12"), // does not get read back
+			},
+		}
+	}
+
+	if _, ok := d.GetOk("push.0.direct_fcm"); ok {
+		md["direct_fcm"] = []interface{}{
+			map[string]interface{}{
+				"server_key": d.Get("push.0.direct_fcm.0.server_key"), // does not get read back
+			},
+		}
+	}
+
+	return []interface{}{md}, nil
+}
+
+func flattenDUOOptions(api *management.Management) ([]interface{}, error) {
+	s := &multiFactorDUOSettings{}
+	err := api.Request(http.MethodGet, api.URI("guardian", "factors", "duo", "settings"), s)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"hostname":        s.Hostname,
+			"integration_key": s.IntegrationKey,
+			"secret_key":      s.SecretKey,
+		},
+	}, nil
+}
+
 func hasBlockPresentInNewState(d *schema.ResourceData, factor string) bool {
 	if ok := d.HasChange(factor); ok {
 		_, n := d.GetChange(factor)
@@ -337,7 +830,7 @@ func flattenAuth0Options(api *management.Management) (map[string]interface{}, er
 	return md, nil
 }
 
-func flattenTwilioOptions(api *management.Management) (map[string]interface{}, error) {
+func flattenTwilioOptions(api *management.Management, secrets *secretHasher) (map[string]interface{}, error) {
 	md := make(map[string]interface{})
 	t, err := api.Guardian.MultiFactor.SMS.Template()
 	if err != nil {
@@ -349,7 +842,7 @@ func flattenTwilioOptions(api *management.Management) (map[string]interface{}, e
 	if err != nil {
 		return nil, err
 	}
-	md["auth_token"] = tw.AuthToken
+	md["auth_token"] = secrets.hash(tw.GetAuthToken())
 	md["from"] = tw.From
 	md["messaging_service_sid"] = tw.MessagingServiceSid
 	md["sid"] = tw.SID
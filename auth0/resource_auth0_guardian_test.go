@@ -118,6 +118,45 @@ func TestAccGuardian(t *testing.T) {
 					resource.TestCheckResourceAttr("auth0_guardian.foo", "otp", "false"),
 				),
 			},
+
+			{
+				Config: testAccConfigureWebAuthn,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "webauthn_roaming.#", "1"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "webauthn_roaming.0.user_verification", "required"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "webauthn_platform.#", "1"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "recovery_code", "true"),
+				),
+			},
+
+			{
+				Config: testAccConfigureWebAuthnUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "webauthn_roaming.#", "0"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "webauthn_platform.#", "0"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "recovery_code", "false"),
+				),
+			},
+
+			{
+				Config: testAccConfigurePush,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "push.#", "1"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "push.0.provider", "sns"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "push.0.amazon_sns.0.aws_access_key_id", "test1"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "push.0.amazon_sns.0.aws_region", "us-west-1"),
+				),
+			},
+
+			{
+				Config: testAccConfigureDUO,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "push.#", "0"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "duo.#", "1"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "duo.0.integration_key", "someKey"),
+					resource.TestCheckResourceAttr("auth0_guardian.foo", "duo.0.hostname", "api-hostname"),
+				),
+			},
 		},
 	})
 }
@@ -225,3 +264,52 @@ resource "auth0_guardian" "foo" {
 	otp  = false
 }
 `
+
+const testAccConfigureWebAuthn = `
+
+resource "auth0_guardian" "foo" {
+	policy = "all-applications"
+	webauthn_roaming {
+		user_verification = "required"
+	}
+	webauthn_platform {}
+	recovery_code = true
+}
+`
+
+const testAccConfigureWebAuthnUpdate = `
+
+resource "auth0_guardian" "foo" {
+	policy = "all-applications"
+	recovery_code = false
+}
+`
+
+const testAccConfigurePush = `
+
+resource "auth0_guardian" "foo" {
+	policy = "all-applications"
+	push {
+		provider = "sns"
+		amazon_sns {
+			aws_access_key_id = "test1"
+			aws_secret_access_key = "secretKey"
+			aws_region = "us-west-1"
+			sns_apns_platform_application_arn = "test_arn"
+			sns_gcm_platform_application_arn = "test_arn"
+		}
+	}
+}
+`
+
+const testAccConfigureDUO = `
+
+resource "auth0_guardian" "foo" {
+	policy = "all-applications"
+	duo {
+		integration_key = "someKey"
+		secret_key = "someSecret"
+		hostname = "api-hostname"
+	}
+}
+`
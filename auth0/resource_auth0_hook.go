@@ -3,6 +3,7 @@ package auth0
 import (
 	"net/http"
 	"regexp"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
@@ -11,7 +12,7 @@ import (
 	"gopkg.in/auth0.v5/management"
 )
 
-func newHook() *schema.Resource {
+func newHook(secrets *secretHasher) *schema.Resource {
 	return &schema.Resource{
 
 		Create: createHook,
@@ -57,10 +58,12 @@ func newHook() *schema.Resource {
 					", or send-phone-message",
 			},
 			"secrets": {
-				Type:        schema.TypeMap,
-				Optional:    true,
-				Description: "The secrets associated with the hook",
-				Elem:        schema.TypeString,
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: secrets.suppressDiff,
+				Description:      "The secrets associated with the hook",
+				Elem:             schema.TypeString,
 			},
 			"enabled": {
 				Type:        schema.TypeBool,
@@ -74,7 +77,7 @@ func newHook() *schema.Resource {
 
 func createHook(d *schema.ResourceData, m interface{}) error {
 	c := buildHook(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Hook.Create(c); err != nil {
 		return err
 	}
@@ -86,7 +89,7 @@ func createHook(d *schema.ResourceData, m interface{}) error {
 }
 
 func readHook(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	c, err := api.Hook.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -103,12 +106,23 @@ func readHook(d *schema.ResourceData, m interface{}) error {
 	d.Set("script", c.Script)
 	d.Set("trigger_id", c.TriggerID)
 	d.Set("enabled", c.Enabled)
+
+	// Secret values are never returned, so they can only be read back when
+	// they are stored as hashes, in which case removed secrets are detected
+	// by name.
+	if hasher := m.(*config).secrets; hasher.enabled() {
+		secrets, err := api.Hook.Secrets(d.Id())
+		if err != nil {
+			return err
+		}
+		d.Set("secrets", flattenHookSecretHashes(hasher, secrets, d.Get("secrets").(map[string]interface{})))
+	}
 	return nil
 }
 
 func updateHook(d *schema.ResourceData, m interface{}) error {
 	c := buildHook(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Hook.Update(d.Id(), c)
 	if err != nil {
 		return err
@@ -121,14 +135,79 @@ func updateHook(d *schema.ResourceData, m interface{}) error {
 
 func upsertHookSecrets(d *schema.ResourceData, m interface{}) error {
 	if d.IsNewResource() || d.HasChange("secrets") {
+		api := m.(*config).api
+		if m.(*config).secrets.enabled() && !d.IsNewResource() {
+			o, n := d.GetChange("secrets")
+			return updateHookSecrets(api, d.Id(), o.(map[string]interface{}), n.(map[string]interface{}))
+		}
 		secrets := Map(d, "secrets")
-		api := m.(*management.Management)
 		hookSecrets := toHookSecrets(secrets)
 		return api.Hook.ReplaceSecrets(d.Id(), hookSecrets)
 	}
 	return nil
 }
 
+// updateHookSecrets only sends the secrets which changed, as the values of
+// the others are not known when their hash is stored in state.
+func updateHookSecrets(api *management.Management, id string, old, new map[string]interface{}) error {
+	created, updated, removed := diffHookSecrets(old, new)
+	if len(removed) > 0 {
+		if err := api.Hook.RemoveSecrets(id, removed); err != nil {
+			return err
+		}
+	}
+	if len(created) > 0 {
+		if err := api.Hook.CreateSecrets(id, created); err != nil {
+			return err
+		}
+	}
+	if len(updated) > 0 {
+		if err := api.Hook.UpdateSecrets(id, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffHookSecrets returns the secrets to create, update and remove to go from
+// the old secrets to the new ones. Secrets whose new value is a hash are
+// unchanged. Secrets with an empty old value exist but were not created by
+// Terraform, so they are updated rather than created.
+func diffHookSecrets(old, new map[string]interface{}) (created, updated management.HookSecrets, removed []string) {
+	created, updated = management.HookSecrets{}, management.HookSecrets{}
+	for name, v := range new {
+		value, _ := v.(string)
+		if isSecretHash(value) {
+			continue
+		}
+		if _, ok := old[name]; ok {
+			updated[name] = value
+		} else {
+			created[name] = value
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return
+}
+
+// flattenHookSecretHashes flattens the secrets of a hook, whose values are
+// not returned, using the hash, made with hasher, of the value found in prior
+// for the secret of the same name. Secrets which are not in prior get an
+// empty value.
+func flattenHookSecretHashes(hasher *secretHasher, secrets management.HookSecrets, prior map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(secrets))
+	for name := range secrets {
+		value, _ := prior[name].(string)
+		m[name] = hasher.hash(value)
+	}
+	return m
+}
+
 func toHookSecrets(val map[string]interface{}) management.HookSecrets {
 	hookSecrets := management.HookSecrets{}
 	for key, value := range val {
@@ -140,7 +219,7 @@ func toHookSecrets(val map[string]interface{}) management.HookSecrets {
 }
 
 func deleteHook(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Hook.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -184,7 +184,7 @@ func newLogStream() *schema.Resource {
 func createLogStream(d *schema.ResourceData, m interface{}) error {
 	ls := expandLogStream(d)
 
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.LogStream.Create(ls); err != nil {
 		return err
 	}
@@ -205,7 +205,7 @@ func createLogStream(d *schema.ResourceData, m interface{}) error {
 }
 
 func readLogStream(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	ls, err := api.LogStream.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -228,7 +228,7 @@ func readLogStream(d *schema.ResourceData, m interface{}) error {
 func updateLogStream(d *schema.ResourceData, m interface{}) error {
 	ls := expandLogStream(d)
 
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.LogStream.Update(d.Id(), ls)
 	if err != nil {
 		return err
@@ -237,7 +237,7 @@ func updateLogStream(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteLogStream(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.LogStream.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
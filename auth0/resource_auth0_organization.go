@@ -84,7 +84,7 @@ func newOrganization() *schema.Resource {
 
 func createOrganization(d *schema.ResourceData, m interface{}) error {
 	o := expandOrganization(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Organization.Create(o); err != nil {
 		return err
 	}
@@ -102,7 +102,7 @@ func createOrganization(d *schema.ResourceData, m interface{}) error {
 
 func assignOrganizationConnections(d *schema.ResourceData, m interface{}) (err error) {
 
-	api := m.(*management.Management)
+	api := m.(*config).api
 
 	add, rm := Diff(d, "connections")
 
@@ -146,7 +146,7 @@ func assignOrganizationConnections(d *schema.ResourceData, m interface{}) (err e
 }
 
 func readOrganization(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	o, err := api.Organization.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -184,7 +184,7 @@ func readOrganization(d *schema.ResourceData, m interface{}) error {
 
 func updateOrganization(d *schema.ResourceData, m interface{}) error {
 	o := expandOrganization(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Organization.Update(d.Id(), o)
 	if err != nil {
 		return err
@@ -201,7 +201,7 @@ func updateOrganization(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteOrganization(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Organization.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -0,0 +1,389 @@
+package auth0
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
+)
+
+// hostedPages are the pages hosted by Auth0 which can be customized, by the
+// name of their block. The login page is the custom login page of the global
+// client, the others are set in the tenant settings.
+var hostedPages = []string{"login", "change_password", "guardian_mfa", "error"}
+
+func newPages() *schema.Resource {
+	return &schema.Resource{
+
+		Create: createPages,
+		Read:   readPages,
+		Update: updatePages,
+		Delete: deletePages,
+		Importer: &schema.ResourceImporter{
+			State: importPages,
+		},
+		CustomizeDiff: customdiff.All(validatePagesOwnership, diffPagesHashes),
+
+		Schema: map[string]*schema.Schema{
+			"login": newHostedPageSchema("login", "Custom login page of the classic login experience, "+
+				"set on the global client", true, nil),
+			"change_password": newHostedPageSchema("change_password", "Custom password reset page", true, nil),
+			"guardian_mfa":    newHostedPageSchema("guardian_mfa", "Custom multi-factor authentication page", true, nil),
+			"error": newHostedPageSchema("error", "Custom error page", false, map[string]*schema.Schema{
+				"show_log_link": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Indicates whether or not to show the link to the logs on the error page",
+				},
+				"url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "URL to redirect to when an error occurs, instead of showing the error page",
+				},
+			}),
+			"html_hashes": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "SHA-256 hashes of the HTML of the managed pages, by name",
+			},
+		},
+	}
+}
+
+// newHostedPageSchema returns the schema of the block of a hosted page, whose
+// HTML is set inline or read from a file.
+func newHostedPageSchema(page, description string, enabled bool, extra map[string]*schema.Schema) *schema.Schema {
+	s := map[string]*schema.Schema{
+		"html": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{page + ".0.html_file"},
+			Description:   "HTML of the page",
+		},
+		"html_file": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{page + ".0.html"},
+			Description: "Path of a file holding the HTML of the page. Changes " +
+				"to its content are detected with `html_hashes`",
+		},
+	}
+	if enabled {
+		s["enabled"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "Indicates whether or not the custom page is used instead of the default one",
+		}
+	}
+	for k, v := range extra {
+		s[k] = v
+	}
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: description,
+		Elem:        &schema.Resource{Schema: s},
+	}
+}
+
+func createPages(d *schema.ResourceData, m interface{}) error {
+	d.SetId(resource.UniqueId())
+	return updatePages(d, m)
+}
+
+func readPages(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	id, err := globalClientID(api)
+	if err != nil {
+		return err
+	}
+	c, err := api.Client.Read(id, management.IncludeFields("custom_login_page_on", "custom_login_page"))
+	if err != nil {
+		return err
+	}
+	t, err := api.Tenant.Read()
+	if err != nil {
+		return err
+	}
+
+	pages := map[string]map[string]interface{}{
+		"login": {
+			"enabled": c.GetCustomLoginPageOn(),
+			"html":    c.GetCustomLoginPage(),
+		},
+		"change_password": {
+			"enabled": t.GetChangePassword().GetEnabled(),
+			"html":    t.GetChangePassword().GetHTML(),
+		},
+		"guardian_mfa": {
+			"enabled": t.GetGuardianMFAPage().GetEnabled(),
+			"html":    t.GetGuardianMFAPage().GetHTML(),
+		},
+		"error": {
+			"html":          t.GetErrorPage().GetHTML(),
+			"show_log_link": t.GetErrorPage().GetShowLogLink(),
+			"url":           t.GetErrorPage().GetURL(),
+		},
+	}
+
+	// Only the pages configured are managed, and so read. The HTML of pages
+	// read from a file is tracked by html_hashes instead, as it is not
+	// configured.
+	hashes := make(map[string]interface{})
+	for _, page := range hostedPages {
+		if _, ok := d.GetOk(page); !ok {
+			continue
+		}
+		p := pages[page]
+		hashes[page] = hostedPageHash(p["html"].(string))
+		if file, _ := d.Get(page + ".0.html_file").(string); file != "" {
+			p["html"] = ""
+			p["html_file"] = file
+		}
+		d.Set(page, []interface{}{p})
+	}
+	d.Set("html_hashes", hashes)
+
+	return nil
+}
+
+func updatePages(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+
+	if hostedPageChanged(d, "login") {
+		c := &management.Client{}
+		var err error
+		List(d, "login").Elem(func(d ResourceData) {
+			c.CustomLoginPageOn = Bool(d, "enabled")
+			c.CustomLoginPage, err = expandHostedPageHTML(d)
+		})
+		if err != nil {
+			return err
+		}
+		id, err := globalClientID(api)
+		if err != nil {
+			return err
+		}
+		if err := api.Client.Update(id, c); err != nil {
+			return err
+		}
+	}
+
+	t, err := expandPagesTenant(d)
+	if err != nil {
+		return err
+	}
+	if t.ChangePassword != nil || t.GuardianMFAPage != nil || t.ErrorPage != nil {
+		if err := api.Tenant.Update(t); err != nil {
+			return err
+		}
+	}
+
+	return readPages(d, m)
+}
+
+// deletePages removes the pages from the state only. They are left as they
+// are, like the settings of auth0_tenant.
+func deletePages(d *schema.ResourceData, m interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+// importPages imports all the pages, which are otherwise only read when they
+// are configured.
+func importPages(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	for _, page := range hostedPages {
+		if err := d.Set(page, []interface{}{map[string]interface{}{}}); err != nil {
+			return nil, err
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandPagesTenant returns the tenant settings of the pages which changed.
+func expandPagesTenant(d *schema.ResourceData) (*management.Tenant, error) {
+	t := &management.Tenant{}
+	var errs *multierror.Error
+
+	if hostedPageChanged(d, "change_password") {
+		List(d, "change_password").Elem(func(d ResourceData) {
+			html, err := expandHostedPageHTML(d)
+			errs = multierror.Append(errs, err)
+			t.ChangePassword = &management.TenantChangePassword{
+				Enabled: Bool(d, "enabled"),
+				HTML:    html,
+			}
+		})
+	}
+	if hostedPageChanged(d, "guardian_mfa") {
+		List(d, "guardian_mfa").Elem(func(d ResourceData) {
+			html, err := expandHostedPageHTML(d)
+			errs = multierror.Append(errs, err)
+			t.GuardianMFAPage = &management.TenantGuardianMFAPage{
+				Enabled: Bool(d, "enabled"),
+				HTML:    html,
+			}
+		})
+	}
+	if hostedPageChanged(d, "error") {
+		List(d, "error").Elem(func(d ResourceData) {
+			html, err := expandHostedPageHTML(d)
+			errs = multierror.Append(errs, err)
+			t.ErrorPage = &management.TenantErrorPage{
+				HTML:        html,
+				ShowLogLink: auth0.Bool(d.Get("show_log_link").(bool)),
+				URL:         auth0.String(d.Get("url").(string)),
+			}
+		})
+	}
+
+	return t, errs.ErrorOrNil()
+}
+
+// hostedPageChanged reports whether a configured page is to be updated, which
+// is the case when its block or the content of its file changed.
+func hostedPageChanged(d *schema.ResourceData, page string) bool {
+	if _, ok := d.GetOk(page); !ok {
+		return false
+	}
+	return d.IsNewResource() || d.HasChange(page) || d.HasChange("html_hashes."+page)
+}
+
+// expandHostedPageHTML returns the HTML of a page, reading it from html_file
+// when it is set. The HTML is always sent, so that it can be emptied.
+func expandHostedPageHTML(d ResourceData) (*string, error) {
+	if file, _ := d.Get("html_file").(string); file != "" {
+		html, err := readHostedPageFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return auth0.String(html), nil
+	}
+	return auth0.String(d.Get("html").(string)), nil
+}
+
+func readHostedPageFile(file string) (string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read html_file: %w", err)
+	}
+	return string(b), nil
+}
+
+func hostedPageHash(html string) string {
+	sum := sha256.Sum256([]byte(html))
+	return hex.EncodeToString(sum[:])
+}
+
+// diffPagesHashes plans the hashes of the HTML of the configured pages, so
+// that changes to the content of their html_file and to the pages outside of
+// Terraform are detected.
+func diffPagesHashes(d *schema.ResourceDiff, m interface{}) error {
+	hashes := make(map[string]interface{})
+	for _, page := range hostedPages {
+		if _, ok := d.GetOk(page); !ok {
+			continue
+		}
+		for _, key := range []string{"html", "html_file"} {
+			if !d.NewValueKnown(page + ".0." + key) {
+				return d.SetNewComputed("html_hashes")
+			}
+		}
+		html, _ := d.Get(page + ".0.html").(string)
+		if file, _ := d.Get(page + ".0.html_file").(string); file != "" {
+			var err error
+			if html, err = readHostedPageFile(file); err != nil {
+				return fmt.Errorf("%s.0.html_file: %w", page, err)
+			}
+		}
+		hashes[page] = hostedPageHash(html)
+	}
+	return d.SetNew("html_hashes", hashes)
+}
+
+// claimHostedPage records that owner, either auth0_pages or the legacy
+// setting of another resource, plans to set a hosted page. Besides
+// auth0_pages, the legacy settings of auth0_tenant and auth0_global_client set
+// the pages as well, so that the resources would overwrite each other. It
+// fails when auth0_pages and a legacy setting both claim the page.
+func claimHostedPage(m interface{}, page, owner string) error {
+	for _, other := range m.(*config).claims.claim("hosted_page", page, owner) {
+		if isPagesOwner(other) == isPagesOwner(owner) {
+			continue
+		}
+		legacy := owner
+		if isPagesOwner(owner) {
+			legacy = other
+		}
+		return fmt.Errorf("%s conflicts with the %s page of auth0_pages, "+
+			"which manages it. Remove %s from the configuration", legacy, page, legacy)
+	}
+	return nil
+}
+
+func isPagesOwner(owner string) bool {
+	return strings.HasPrefix(owner, "auth0_pages.")
+}
+
+// validatePagesOwnership claims the configured pages, so that conflicts with
+// the legacy settings of other resources are detected.
+func validatePagesOwnership(d *schema.ResourceDiff, m interface{}) error {
+	var errs *multierror.Error
+	for _, page := range hostedPages {
+		if _, ok := d.GetOk(page); ok {
+			errs = multierror.Append(errs, claimHostedPage(m, page, "auth0_pages."+page))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// validateLegacyPages claims the pages set by the legacy settings of a
+// resource, given by page. The settings which are not computed are only kept
+// in state when they are configured, see readLegacyPages, so they are claimed
+// whenever they are set. The computed ones are claimed when they change.
+func validateLegacyPages(resource string, s map[string]*schema.Schema, pages map[string]string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		var errs *multierror.Error
+		for key, page := range pages {
+			_, set := d.GetOk(key)
+			if d.HasChange(key) || (set && !s[key].Computed) {
+				errs = multierror.Append(errs, claimHostedPage(m, page, resource+"."+key))
+			}
+		}
+		return errs.ErrorOrNil()
+	}
+}
+
+// readLegacyPages wraps a function reading a resource into state, such as its
+// create, read or update function, so that the legacy page settings given by
+// keys are only kept when they are configured. Otherwise the pages read from
+// Auth0 would be kept in state, and couldn't be told apart from configured
+// ones when planning.
+func readLegacyPages(f func(*schema.ResourceData, interface{}) error, keys ...string) func(*schema.ResourceData, interface{}) error {
+	return func(d *schema.ResourceData, m interface{}) error {
+		configured := make(map[string]bool)
+		for _, key := range keys {
+			_, configured[key] = d.GetOk(key)
+		}
+		if err := f(d, m); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !configured[key] {
+				d.Set(key, nil)
+			}
+		}
+		return nil
+	}
+}
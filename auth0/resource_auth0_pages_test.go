@@ -0,0 +1,333 @@
+package auth0
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPages(t *testing.T) {
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPagesConfigCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "login.0.enabled", "true"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "login.0.html", "<html><body>Login</body></html>"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "change_password.0.enabled", "true"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "change_password.0.html", "<html><body>Change Password</body></html>"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "guardian_mfa.0.enabled", "false"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "guardian_mfa.0.html", "<html><body>MFA</body></html>"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "error.0.html", ""),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "error.0.html_file", "testdata/pages/error.html"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "error.0.show_log_link", "false"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "error.0.url", "https://mycompany.org/error"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "html_hashes.%", "4"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "html_hashes.error", "d47013d1c0827ee7af725a20a7546dc4b19f4d3feb57910be2a3a5fb170a0feb"),
+				),
+			},
+			{
+				Config: testAccPagesConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "login.0.enabled", "false"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "guardian_mfa.#", "0"),
+					resource.TestCheckResourceAttr("auth0_pages.my_pages", "html_hashes.%", "3"),
+				),
+			},
+			{
+				Config:      testAccPagesConfigUpdate + testAccPagesConfigLegacyTenant,
+				ExpectError: regexp.MustCompile(`auth0_tenant.change_password conflicts with the change_password page of auth0_pages`),
+			},
+		},
+	})
+}
+
+const testAccPagesConfigCreate = `
+resource "auth0_pages" "my_pages" {
+	login {
+		enabled = true
+		html = "<html><body>Login</body></html>"
+	}
+	change_password {
+		enabled = true
+		html = "<html><body>Change Password</body></html>"
+	}
+	guardian_mfa {
+		enabled = false
+		html = "<html><body>MFA</body></html>"
+	}
+	error {
+		html_file = "testdata/pages/error.html"
+		show_log_link = false
+		url = "https://mycompany.org/error"
+	}
+}
+`
+
+const testAccPagesConfigUpdate = `
+resource "auth0_pages" "my_pages" {
+	login {
+		enabled = false
+		html = "<html><body>Login</body></html>"
+	}
+	change_password {
+		enabled = true
+		html = "<html><body>Change Password</body></html>"
+	}
+	error {
+		html_file = "testdata/pages/error.html"
+		show_log_link = false
+		url = "https://mycompany.org/error"
+	}
+}
+`
+
+const testAccPagesConfigLegacyTenant = `
+resource "auth0_tenant" "my_tenant" {
+	change_password {
+		enabled = true
+		html = "<html><body>Legacy Change Password</body></html>"
+	}
+}
+`
+
+func TestPagesHashes(t *testing.T) {
+	diff, err := newPages().Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"login": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"html":    "<html><body>Login</body></html>",
+		}},
+		"error": []interface{}{map[string]interface{}{
+			"html_file": "testdata/pages/error.html",
+		}},
+	}), &config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]string{
+		"html_hashes.%":     "2",
+		"html_hashes.login": hostedPageHash("<html><body>Login</body></html>"),
+		"html_hashes.error": hostedPageHash("<!DOCTYPE html>\n<html><body><h1>Something went wrong</h1></body></html>\n"),
+	} {
+		if actual := diff.Attributes[key]; actual == nil || actual.New != expected {
+			t.Errorf("expected %s to be %q, got %v", key, expected, actual)
+		}
+	}
+
+	_, err = newPages().Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"error": []interface{}{map[string]interface{}{
+			"html_file": "testdata/pages/missing.html",
+		}},
+	}), &config{})
+	if err == nil || !strings.Contains(err.Error(), "error.0.html_file: failed to read html_file") {
+		t.Errorf("expected an error reading the missing file, got %v", err)
+	}
+}
+
+func TestValidatePagesOwnership(t *testing.T) {
+	pages := map[string]interface{}{
+		"change_password": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"html":    "<html><body>Change Password</body></html>",
+		}},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		resource func() *schema.Resource
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "TenantWithoutPages",
+			resource: newTenant,
+			config: map[string]interface{}{
+				"friendly_name": "My Test Tenant",
+			},
+		},
+		{
+			name:     "TenantWithOtherPage",
+			resource: newTenant,
+			config: map[string]interface{}{
+				"guardian_mfa_page": []interface{}{map[string]interface{}{
+					"enabled": true,
+					"html":    "<html><body>MFA</body></html>",
+				}},
+			},
+		},
+		{
+			name:     "TenantWithSamePage",
+			resource: newTenant,
+			config: map[string]interface{}{
+				"change_password": []interface{}{map[string]interface{}{
+					"enabled": true,
+					"html":    "<html><body>Legacy Change Password</body></html>",
+				}},
+			},
+			expected: "auth0_tenant.change_password conflicts with the change_password page of auth0_pages, which manages it",
+		},
+		{
+			name:     "GlobalClientWithoutLoginPage",
+			resource: newGlobalClient,
+			config: map[string]interface{}{
+				"custom_login_page_on": true,
+				"custom_login_page":    "<html><body>Legacy Login</body></html>",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := &config{}
+			if _, err := newPages().Diff(nil, terraform.NewResourceConfigRaw(pages), meta); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := tt.resource().Diff(nil, terraform.NewResourceConfigRaw(tt.config), meta)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidatePagesOwnershipUnchangedLegacySettings(t *testing.T) {
+	pages := map[string]interface{}{
+		"login": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"html":    "<html><body>Login</body></html>",
+		}},
+		"change_password": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"html":    "<html><body>Change Password</body></html>",
+		}},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		resource func() *schema.Resource
+		state    map[string]string
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			// A configured legacy setting conflicts even when it is unchanged.
+			name:     "TenantConfigured",
+			resource: newTenant,
+			state: map[string]string{
+				"change_password.#":         "1",
+				"change_password.0.enabled": "true",
+				"change_password.0.html":    "<html><body>Legacy</body></html>",
+			},
+			config: map[string]interface{}{
+				"change_password": []interface{}{map[string]interface{}{
+					"enabled": true,
+					"html":    "<html><body>Legacy</body></html>",
+				}},
+			},
+			expected: "auth0_tenant.change_password conflicts with the change_password page of auth0_pages",
+		},
+		{
+			name:     "GlobalClientConfigured",
+			resource: newGlobalClient,
+			state: map[string]string{
+				"custom_login_page": "<html><body>Legacy</body></html>",
+			},
+			config: map[string]interface{}{
+				"custom_login_page": "<html><body>Legacy</body></html>",
+			},
+			expected: "auth0_global_client.custom_login_page conflicts with the login page of auth0_pages",
+		},
+		{
+			// The computed settings are read from Auth0 when they aren't
+			// configured, as auth0_pages sets them, so they are only claimed
+			// when they change.
+			name:     "GlobalClientComputed",
+			resource: newGlobalClient,
+			state: map[string]string{
+				"custom_login_page_on": "true",
+			},
+			config: map[string]interface{}{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := &config{}
+			if _, err := newPages().Diff(nil, terraform.NewResourceConfigRaw(pages), meta); err != nil {
+				t.Fatal(err)
+			}
+
+			state := &terraform.InstanceState{ID: "id", Attributes: tt.state}
+			_, err := tt.resource().Diff(state, terraform.NewResourceConfigRaw(tt.config), meta)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestReadLegacyPages(t *testing.T) {
+	read := func(d *schema.ResourceData, m interface{}) error {
+		for _, key := range []string{"change_password", "error_page"} {
+			d.Set(key, []interface{}{map[string]interface{}{"html": "<html><body>Read</body></html>"}})
+		}
+		return nil
+	}
+
+	d := schema.TestResourceDataRaw(t, newTenant().Schema, map[string]interface{}{
+		"change_password": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"html":    "<html><body>Change Password</body></html>",
+		}},
+	})
+	if err := readLegacyPages(read, "change_password", "error_page")(d, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if html := d.Get("change_password.0.html"); html != "<html><body>Read</body></html>" {
+		t.Errorf("expected the configured change_password page to be read, got %q", html)
+	}
+	if pages := d.Get("error_page").([]interface{}); len(pages) != 0 {
+		t.Errorf("expected the error_page which isn't configured not to be kept, got %v", pages)
+	}
+}
+
+func TestValidatePagesOwnershipLegacyFirst(t *testing.T) {
+	meta := &config{}
+	_, err := newGlobalClient().Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"custom_login_page_on": true,
+		"custom_login_page":    "<html><body>Legacy Login</body></html>",
+	}), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = newPages().Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"login": []interface{}{map[string]interface{}{
+			"enabled": true,
+			"html":    "<html><body>Login</body></html>",
+		}},
+	}), meta)
+	expected := "conflicts with the login page of auth0_pages, which manages it"
+	if err == nil || !strings.Contains(err.Error(), "auth0_global_client.custom_login_page") || !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
@@ -45,7 +45,7 @@ func createPrompt(d *schema.ResourceData, m interface{}) error {
 }
 
 func readPrompt(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	p, err := api.Prompt.Read()
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -63,7 +63,7 @@ func readPrompt(d *schema.ResourceData, m interface{}) error {
 
 func updatePrompt(d *schema.ResourceData, m interface{}) error {
 	p := buildPrompt(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Prompt.Update(p)
 	if err != nil {
 		return err
@@ -79,7 +79,7 @@ func createPromptCustomText(d *schema.ResourceData, m interface{}) error {
 }
 
 func readPromptCustomText(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	customText, err := api.Prompt.CustomText(d.Get("prompt").(string), d.Get("language").(string))
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -101,7 +101,7 @@ func readPromptCustomText(d *schema.ResourceData, m interface{}) error {
 }
 
 func updatePromptCustomText(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	prompt, language, err := getPromptAndLanguage(d)
 	if err != nil {
 		return err
@@ -0,0 +1,312 @@
+package auth0
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	"gopkg.in/auth0.v5/management"
+)
+
+// promptCustomTextsAllPrompts is the ID of an auth0_prompt_custom_texts
+// resource which manages the custom texts of every prompt.
+const promptCustomTextsAllPrompts = "all"
+
+var (
+	errEmptyPromptCustomTextsID         = fmt.Errorf("ID cannot be empty")
+	errInvalidPromptCustomTextsIDFormat = fmt.Errorf("ID must be formated as prompt:language,language,... where prompt can be %q", promptCustomTextsAllPrompts)
+)
+
+func newPromptCustomTexts() *schema.Resource {
+	return &schema.Resource{
+		Create: createPromptCustomTexts,
+		Read:   readPromptCustomTexts,
+		Update: updatePromptCustomTexts,
+		Delete: deletePromptCustomTexts,
+		Importer: &schema.ResourceImporter{
+			State: importPromptCustomTexts,
+		},
+		CustomizeDiff: validatePromptCustomTextsPrompts,
+		Schema: map[string]*schema.Schema{
+			"prompt": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(availablePrompts, false),
+				Description: "The prompt to manage custom texts for. If omitted, the custom texts of every " +
+					"prompt are managed and each body is keyed by prompt name",
+			},
+			"texts": {
+				Type:             schema.TypeMap,
+				Required:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateFunc:     validatePromptCustomTexts,
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				Description:      "Map of language to the JSON encoded custom texts for that language",
+			},
+		},
+	}
+}
+
+func validatePromptCustomTexts(i interface{}, k string) (warnings []string, errors []error) {
+	texts, ok := i.(map[string]interface{})
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be map", k))
+		return
+	}
+	for language, body := range texts {
+		_, errs := validation.StringInSlice(availableLanguages, false)(language, k+"."+language)
+		errors = append(errors, errs...)
+
+		// Values interpolated from other resources are not known yet.
+		if s, ok := body.(string); ok {
+			_, errs = validation.StringIsJSON(s, k+"."+language)
+			errors = append(errors, errs...)
+		}
+	}
+	return
+}
+
+// validatePromptCustomTextsPrompts checks that, when managing the custom texts
+// of every prompt, each body is keyed by the name of a prompt.
+func validatePromptCustomTextsPrompts(d *schema.ResourceDiff, m interface{}) error {
+	if _, ok := d.GetOk("prompt"); ok || !d.NewValueKnown("texts") {
+		return nil
+	}
+	for language, body := range d.Get("texts").(map[string]interface{}) {
+		if err := checkCustomTextBodyPrompts(language, body.(string)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCustomTextBodyPrompts(language, body string) error {
+	prompts, err := promptsInCustomTextBody(body)
+	if err != nil {
+		return err
+	}
+	for _, prompt := range prompts {
+		found := false
+		for _, p := range availablePrompts {
+			if p == prompt {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("texts.%s: %q is not a prompt, when prompt is omitted the custom texts must be keyed by prompt name", language, prompt)
+		}
+	}
+	return nil
+}
+
+func importPromptCustomTexts(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	rawID := d.Id()
+	if rawID == "" {
+		return []*schema.ResourceData{}, errEmptyPromptCustomTextsID
+	}
+
+	idPair := strings.Split(rawID, ":")
+	if len(idPair) != 2 || idPair[0] == "" || idPair[1] == "" {
+		return []*schema.ResourceData{}, errInvalidPromptCustomTextsIDFormat
+	}
+
+	// The languages are set with an empty body, which is then populated by
+	// the read that follows the import.
+	texts := make(map[string]interface{})
+	for _, language := range strings.Split(idPair[1], ",") {
+		texts[language] = "{}"
+	}
+
+	d.SetId(idPair[0])
+	if idPair[0] != promptCustomTextsAllPrompts {
+		d.Set("prompt", idPair[0])
+	}
+	d.Set("texts", texts)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func createPromptCustomTexts(d *schema.ResourceData, m interface{}) error {
+	id := promptCustomTextsAllPrompts
+	if prompt, ok := d.GetOk("prompt"); ok {
+		id = prompt.(string)
+	}
+	d.SetId(id)
+	return updatePromptCustomTexts(d, m)
+}
+
+func readPromptCustomTexts(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	prompt := d.Get("prompt").(string)
+
+	// Only the languages which are managed by this resource are read back,
+	// any other language is left untouched.
+	texts := make(map[string]interface{})
+	for language, v := range d.Get("texts").(map[string]interface{}) {
+		var body map[string]interface{}
+		if prompt != "" {
+			customText, err := api.Prompt.CustomText(prompt, language)
+			if err != nil {
+				if mErr, ok := err.(management.Error); ok {
+					if mErr.Status() == http.StatusNotFound {
+						d.SetId("")
+						return nil
+					}
+				}
+				return err
+			}
+			body = customText
+		} else {
+			var err error
+			if body, err = readAllPromptsCustomText(api, language, v.(string)); err != nil {
+				return err
+			}
+		}
+
+		s, err := marshalCustomTextBody(body)
+		if err != nil {
+			return err
+		}
+		texts[language] = s
+	}
+
+	d.Set("texts", texts)
+	return nil
+}
+
+// readAllPromptsCustomText reads the custom texts of a language for the
+// prompts present in the current body, keyed by prompt name. If the body has
+// no prompts, as is the case after an import, every prompt is read.
+func readAllPromptsCustomText(api *management.Management, language, current string) (map[string]interface{}, error) {
+	prompts, err := promptsInCustomTextBody(current)
+	if err != nil {
+		return nil, err
+	}
+	if len(prompts) == 0 {
+		prompts = availablePrompts
+	}
+
+	body := make(map[string]interface{})
+	for _, prompt := range prompts {
+		customText, err := api.Prompt.CustomText(prompt, language)
+		if err != nil {
+			return nil, err
+		}
+		if len(customText) > 0 {
+			body[prompt] = customText
+		}
+	}
+	return body, nil
+}
+
+func updatePromptCustomTexts(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	prompt := d.Get("prompt").(string)
+
+	o, n := d.GetChange("texts")
+	oldTexts, newTexts := o.(map[string]interface{}), n.(map[string]interface{})
+
+	for language, v := range newTexts {
+		old, _ := oldTexts[language].(string)
+		if old != "" && jsonEqual(old, v.(string)) {
+			continue
+		}
+		if err := setPromptCustomTexts(api, prompt, language, old, v.(string)); err != nil {
+			return err
+		}
+	}
+
+	for language, v := range oldTexts {
+		if _, ok := newTexts[language]; ok {
+			continue
+		}
+		if err := setPromptCustomTexts(api, prompt, language, v.(string), "{}"); err != nil {
+			return err
+		}
+	}
+
+	return readPromptCustomTexts(d, m)
+}
+
+func deletePromptCustomTexts(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	prompt := d.Get("prompt").(string)
+
+	for language, v := range d.Get("texts").(map[string]interface{}) {
+		if err := setPromptCustomTexts(api, prompt, language, v.(string), "{}"); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// setPromptCustomTexts sets the custom texts of a language. When prompt is
+// empty, the bodies are keyed by prompt name, and prompts which are no longer
+// present in the new body are cleared.
+func setPromptCustomTexts(api *management.Management, prompt, language, oldBody, newBody string) error {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(newBody), &body); err != nil {
+		return err
+	}
+
+	if prompt != "" {
+		return api.Prompt.SetCustomText(prompt, language, body)
+	}
+
+	for p, text := range body {
+		t, ok := text.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected the custom texts of prompt %q in language %q to be an object", p, language)
+		}
+		if err := api.Prompt.SetCustomText(p, language, t); err != nil {
+			return err
+		}
+	}
+
+	oldPrompts, err := promptsInCustomTextBody(oldBody)
+	if err != nil {
+		return err
+	}
+	for _, p := range oldPrompts {
+		if _, ok := body[p]; ok {
+			continue
+		}
+		if err := api.Prompt.SetCustomText(p, language, map[string]interface{}{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// promptsInCustomTextBody returns the sorted prompt names used as keys of a
+// custom text body which spans every prompt.
+func promptsInCustomTextBody(body string) ([]string, error) {
+	if body == "" {
+		return nil, nil
+	}
+	var b map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &b); err != nil {
+		return nil, err
+	}
+	prompts := make([]string, 0, len(b))
+	for p := range b {
+		prompts = append(prompts, p)
+	}
+	sort.Strings(prompts)
+	return prompts, nil
+}
+
+func jsonEqual(a, b string) bool {
+	return structure.SuppressJsonDiff("", a, b, nil)
+}
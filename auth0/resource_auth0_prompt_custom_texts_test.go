@@ -0,0 +1,165 @@
+package auth0
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPromptCustomTexts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPromptCustomTextsCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_prompt_custom_texts.login", "prompt", "login"),
+					resource.TestCheckResourceAttr("auth0_prompt_custom_texts.login", "texts.%", "2"),
+					resource.TestCheckResourceAttr(
+						"auth0_prompt_custom_texts.login",
+						"texts.en",
+						"{\n    \"login\": {\n        \"buttonText\": \"Continue\"\n    }\n}",
+					),
+					resource.TestCheckResourceAttr(
+						"auth0_prompt_custom_texts.login",
+						"texts.es",
+						"{\n    \"login\": {\n        \"buttonText\": \"Continuar\"\n    }\n}",
+					),
+					resource.TestCheckResourceAttr("auth0_prompt_custom_texts.all", "texts.%", "1"),
+					resource.TestCheckResourceAttr(
+						"auth0_prompt_custom_texts.all",
+						"texts.fr",
+						"{\n    \"login\": {\n        \"login\": {\n            \"buttonText\": \"Continuer\"\n        }\n    },\n    \"signup\": {\n        \"signup\": {\n            \"buttonText\": \"S'inscrire\"\n        }\n    }\n}",
+					),
+				),
+			},
+			{
+				Config: testAccPromptCustomTextsUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_prompt_custom_texts.login", "texts.%", "1"),
+					resource.TestCheckResourceAttr(
+						"auth0_prompt_custom_texts.login",
+						"texts.en",
+						"{\n    \"login\": {\n        \"buttonText\": \"Proceed\"\n    }\n}",
+					),
+					resource.TestCheckResourceAttr(
+						"auth0_prompt_custom_texts.all",
+						"texts.fr",
+						"{\n    \"login\": {\n        \"login\": {\n            \"buttonText\": \"Continuer\"\n        }\n    }\n}",
+					),
+				),
+			},
+		},
+	})
+}
+
+const testAccPromptCustomTextsCreate = `
+resource "auth0_prompt_custom_texts" "login" {
+	prompt = "login"
+	texts = {
+		en = jsonencode({ "login" : { "buttonText" : "Continue" } })
+		es = jsonencode({ "login" : { "buttonText" : "Continuar" } })
+	}
+}
+
+resource "auth0_prompt_custom_texts" "all" {
+	texts = {
+		fr = jsonencode({
+			"login" : { "login" : { "buttonText" : "Continuer" } }
+			"signup" : { "signup" : { "buttonText" : "S'inscrire" } }
+		})
+	}
+}
+`
+
+const testAccPromptCustomTextsUpdate = `
+resource "auth0_prompt_custom_texts" "login" {
+	prompt = "login"
+	texts = {
+		en = jsonencode({ "login" : { "buttonText" : "Proceed" } })
+	}
+}
+
+resource "auth0_prompt_custom_texts" "all" {
+	texts = {
+		fr = jsonencode({
+			"login" : { "login" : { "buttonText" : "Continuer" } }
+		})
+	}
+}
+`
+
+func TestValidatePromptCustomTexts(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		texts map[string]interface{}
+		valid bool
+	}{
+		{"Valid", map[string]interface{}{"en": `{"login":{}}`, "pt-BR": `{}`}, true},
+		{"UnknownLanguage", map[string]interface{}{"xx": `{}`}, false},
+		{"InvalidJSON", map[string]interface{}{"en": `{"login":`}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validatePromptCustomTexts(tt.texts, "texts")
+			if tt.valid && len(errs) > 0 {
+				t.Errorf("unexpected errors: %v", errs)
+			}
+			if !tt.valid && len(errs) == 0 {
+				t.Errorf("expected %v to be invalid", tt.texts)
+			}
+		})
+	}
+}
+
+func TestCheckCustomTextBodyPrompts(t *testing.T) {
+	if err := checkCustomTextBodyPrompts("en", `{"login":{"login":{}},"signup-id":{}}`); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := checkCustomTextBodyPrompts("en", `{"login":{},"buttonText":"Continue"}`); err == nil {
+		t.Errorf("expected an error for a body not keyed by prompt")
+	}
+}
+
+func TestPromptCustomTextsImport(t *testing.T) {
+	for _, tt := range []struct {
+		id        string
+		prompt    string
+		languages []string
+		err       error
+	}{
+		{id: "login:en,es", prompt: "login", languages: []string{"en", "es"}},
+		{id: "all:fr", languages: []string{"fr"}},
+		{id: "", err: errEmptyPromptCustomTextsID},
+		{id: "login", err: errInvalidPromptCustomTextsIDFormat},
+		{id: "login:", err: errInvalidPromptCustomTextsIDFormat},
+	} {
+		t.Run(tt.id, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, newPromptCustomTexts().Schema, nil)
+			d.SetId(tt.id)
+
+			_, err := importPromptCustomTexts(d, nil)
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if v := d.Get("prompt").(string); v != tt.prompt {
+				t.Errorf("expected prompt %q, got %q", tt.prompt, v)
+			}
+			texts := d.Get("texts").(map[string]interface{})
+			if len(texts) != len(tt.languages) {
+				t.Fatalf("expected %d languages, got %v", len(tt.languages), texts)
+			}
+			for _, language := range tt.languages {
+				if _, ok := texts[language]; !ok {
+					t.Errorf("expected language %q to be imported", language)
+				}
+			}
+		})
+	}
+}
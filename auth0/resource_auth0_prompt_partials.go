@@ -0,0 +1,217 @@
+package auth0
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+
+	"gopkg.in/auth0.v5/management"
+)
+
+var (
+	// promptPartialInsertionPoints lists the insertion points supported by
+	// each prompt which can be customized with partials.
+	promptPartialInsertionPoints = map[string][]string{
+		"login":           promptPartialFormInsertionPoints,
+		"login-id":        promptPartialFormInsertionPoints,
+		"login-password":  promptPartialFormInsertionPoints,
+		"signup":          promptPartialFormInsertionPoints,
+		"signup-id":       promptPartialFormInsertionPoints,
+		"signup-password": promptPartialFormInsertionPoints,
+	}
+	promptPartialFormInsertionPoints = []string{
+		"form-content-start", "form-content-end", "form-footer-start", "form-footer-end",
+		"secondary-actions-start", "secondary-actions-end",
+	}
+	// promptPartialAttributes maps each attribute of the resource to the name
+	// of the insertion point it configures.
+	promptPartialAttributes = map[string]string{
+		"form_content_start":      "form-content-start",
+		"form_content_end":        "form-content-end",
+		"form_footer_start":       "form-footer-start",
+		"form_footer_end":         "form-footer-end",
+		"secondary_actions_start": "secondary-actions-start",
+		"secondary_actions_end":   "secondary-actions-end",
+	}
+	errEmptyPromptPartialsID = fmt.Errorf("ID cannot be empty")
+)
+
+func newPromptPartials() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"prompt": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.All(validation.StringInSlice(availablePrompts, false), validatePromptPartialsPrompt),
+			Description:  "The prompt to customize. Options include `login`, `login-id`, `login-password`, `signup`, `signup-id` and `signup-password`",
+		},
+	}
+	for attr, point := range promptPartialAttributes {
+		s[attr] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: fmt.Sprintf("HTML content inserted at the `%s` insertion point", point),
+		}
+	}
+
+	return &schema.Resource{
+		Create: createPromptPartials,
+		Read:   readPromptPartials,
+		Update: updatePromptPartials,
+		Delete: deletePromptPartials,
+		Importer: &schema.ResourceImporter{
+			State: importPromptPartials,
+		},
+		CustomizeDiff: validatePromptPartials,
+		Schema:        s,
+	}
+}
+
+func validatePromptPartialsPrompt(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+	if _, ok := promptPartialInsertionPoints[v]; !ok {
+		errors = append(errors, fmt.Errorf("prompt %q does not support partials, expected one of %s", v, promptPartialPrompts()))
+	}
+	return
+}
+
+func validatePromptPartials(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("prompt") {
+		return nil
+	}
+	var points []string
+	for attr, point := range promptPartialAttributes {
+		if _, ok := d.GetOk(attr); ok {
+			points = append(points, point)
+		}
+	}
+	return checkPromptPartialInsertionPoints(d.Get("prompt").(string), points)
+}
+
+// checkPromptPartialInsertionPoints returns an error listing the insertion
+// points which are not supported by the prompt.
+func checkPromptPartialInsertionPoints(prompt string, points []string) error {
+	supported, ok := promptPartialInsertionPoints[prompt]
+	if !ok {
+		return fmt.Errorf("prompt %q does not support partials, expected one of %s", prompt, promptPartialPrompts())
+	}
+
+	var unsupported []string
+	for _, point := range points {
+		found := false
+		for _, s := range supported {
+			if s == point {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unsupported = append(unsupported, point)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("the %q prompt does not support the insertion points %s", prompt, strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+func promptPartialPrompts() string {
+	prompts := make([]string, 0, len(promptPartialInsertionPoints))
+	for prompt := range promptPartialInsertionPoints {
+		prompts = append(prompts, prompt)
+	}
+	sort.Strings(prompts)
+	return strings.Join(prompts, ", ")
+}
+
+func importPromptPartials(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	prompt := d.Id()
+	if prompt == "" {
+		return []*schema.ResourceData{}, errEmptyPromptPartialsID
+	}
+	if _, ok := promptPartialInsertionPoints[prompt]; !ok {
+		return []*schema.ResourceData{}, fmt.Errorf("prompt %q does not support partials, expected one of %s", prompt, promptPartialPrompts())
+	}
+
+	d.Set("prompt", prompt)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func createPromptPartials(d *schema.ResourceData, m interface{}) error {
+	d.SetId(d.Get("prompt").(string))
+	return updatePromptPartials(d, m)
+}
+
+func readPromptPartials(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	prompt := d.Get("prompt").(string)
+
+	partials := make(map[string]map[string]string)
+	err := api.Request(http.MethodGet, api.URI("prompts", prompt, "partials"), &partials)
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	for attr, point := range promptPartialAttributes {
+		d.Set(attr, partials[prompt][point])
+	}
+	return nil
+}
+
+func updatePromptPartials(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	prompt := d.Get("prompt").(string)
+
+	points := make(map[string]string)
+	for attr, point := range promptPartialAttributes {
+		if v, ok := d.GetOk(attr); ok {
+			points[point] = v.(string)
+		}
+	}
+
+	err := api.Request(http.MethodPut, api.URI("prompts", prompt, "partials"), &map[string]map[string]string{
+		prompt: points,
+	})
+	if err != nil {
+		return err
+	}
+
+	return readPromptPartials(d, m)
+}
+
+func deletePromptPartials(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	prompt := d.Get("prompt").(string)
+
+	err := api.Request(http.MethodPut, api.URI("prompts", prompt, "partials"), &map[string]map[string]string{
+		prompt: {},
+	})
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
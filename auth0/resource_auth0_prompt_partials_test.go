@@ -0,0 +1,174 @@
+package auth0
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPromptPartials(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPromptPartialsCreate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_prompt_partials.login", "prompt", "login"),
+					resource.TestCheckResourceAttr("auth0_prompt_partials.login", "form_content_start", "<div>Form Content Start</div>"),
+					resource.TestCheckResourceAttr("auth0_prompt_partials.login", "form_footer_end", "<div>Form Footer End</div>"),
+				),
+			},
+			{
+				Config: testAccPromptPartialsUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_prompt_partials.login", "form_content_start", "<div>Updated Form Content Start</div>"),
+					resource.TestCheckResourceAttr("auth0_prompt_partials.login", "form_footer_end", ""),
+					resource.TestCheckResourceAttr("auth0_prompt_partials.login", "secondary_actions_end", "<div>Secondary Actions End</div>"),
+				),
+			},
+		},
+	})
+}
+
+const testAccPromptPartialsCreate = `
+resource "auth0_prompt_partials" "login" {
+	prompt = "login"
+	form_content_start = "<div>Form Content Start</div>"
+	form_footer_end = "<div>Form Footer End</div>"
+}
+`
+
+const testAccPromptPartialsUpdate = `
+resource "auth0_prompt_partials" "login" {
+	prompt = "login"
+	form_content_start = "<div>Updated Form Content Start</div>"
+	secondary_actions_end = "<div>Secondary Actions End</div>"
+}
+`
+
+func TestPromptPartialInsertionPoints(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		prompt string
+		points []string
+		valid  bool
+	}{
+		{name: "Login", prompt: "login", points: []string{"form-content-start", "secondary-actions-end"}, valid: true},
+		{name: "SignupPassword", prompt: "signup-password", points: []string{"form-footer-start"}, valid: true},
+		{name: "UnknownInsertionPoint", prompt: "login", points: []string{"form-content"}, valid: false},
+		{name: "PromptWithoutPartials", prompt: "consent", points: []string{"form-content-start"}, valid: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPromptPartialInsertionPoints(tt.prompt, tt.points)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("expected %v to be invalid for prompt %q", tt.points, tt.prompt)
+			}
+		})
+	}
+}
+
+func TestPromptPartialInsertionPointsPrompts(t *testing.T) {
+	// The prompts supporting partials are a subset of those of the custom
+	// texts, so that both resources accept the same names.
+	for prompt, points := range promptPartialInsertionPoints {
+		found := false
+		for _, p := range availablePrompts {
+			found = found || p == prompt
+		}
+		if !found {
+			t.Errorf("prompt %q supports partials but is not one of availablePrompts", prompt)
+		}
+		for _, point := range points {
+			found := false
+			for _, p := range promptPartialAttributes {
+				found = found || p == point
+			}
+			if !found {
+				t.Errorf("insertion point %q of prompt %q has no attribute", point, prompt)
+			}
+		}
+	}
+}
+
+func TestValidatePromptPartials(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			name: "SupportedInsertionPoints",
+			config: map[string]interface{}{
+				"prompt":             "login",
+				"form_content_start": "<div>Start</div>",
+			},
+		},
+		{
+			name: "UnknownPrompt",
+			config: map[string]interface{}{
+				"prompt":             "log-in",
+				"form_content_start": "<div>Start</div>",
+			},
+			expected: `expected prompt to be one of`,
+		},
+		{
+			name: "PromptWithoutPartials",
+			config: map[string]interface{}{
+				"prompt":             "consent",
+				"form_content_start": "<div>Start</div>",
+			},
+			expected: `prompt "consent" does not support partials`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newPromptPartials()
+			c := terraform.NewResourceConfigRaw(tt.config)
+			_, err := r.Diff(nil, c, nil)
+			if _, errs := r.Validate(c); len(errs) > 0 {
+				err = multierror.Append(err, errs...)
+			}
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestPromptPartialsImport(t *testing.T) {
+	for id, valid := range map[string]bool{
+		"login":    true,
+		"signup":   true,
+		"consent":  false,
+		"":         false,
+		"login:en": false,
+	} {
+		d := schema.TestResourceDataRaw(t, newPromptPartials().Schema, nil)
+		d.SetId(id)
+
+		_, err := importPromptPartials(d, nil)
+		if valid && err != nil {
+			t.Errorf("importPromptPartials(%q) produced an unexpected error: %v", id, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("importPromptPartials(%q) did not produce an error", id)
+		}
+		if valid && d.Get("prompt").(string) != id {
+			t.Errorf("expected prompt to be %q, got %q", id, d.Get("prompt"))
+		}
+	}
+}
@@ -116,7 +116,7 @@ func newResourceServer() *schema.Resource {
 
 func createResourceServer(d *schema.ResourceData, m interface{}) error {
 	s := expandResourceServer(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.ResourceServer.Create(s); err != nil {
 		return err
 	}
@@ -125,7 +125,7 @@ func createResourceServer(d *schema.ResourceData, m interface{}) error {
 }
 
 func readResourceServer(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	s, err := api.ResourceServer.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -165,7 +165,7 @@ func readResourceServer(d *schema.ResourceData, m interface{}) error {
 func updateResourceServer(d *schema.ResourceData, m interface{}) error {
 	s := expandResourceServer(d)
 	s.Identifier = nil
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.ResourceServer.Update(d.Id(), s)
 	if err != nil {
 		return err
@@ -174,7 +174,7 @@ func updateResourceServer(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteResourceServer(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.ResourceServer.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -205,7 +205,7 @@ func expandResourceServer(d *schema.ResourceData) *management.ResourceServer {
 		SkipConsentForVerifiableFirstPartyClients: Bool(d, "skip_consent_for_verifiable_first_party_clients"),
 	}
 
-	Set(d, "scopes").Elem(func(d ResourceData) {
+	Set(d, "scopes", IsNewResource(), HasChange()).Elem(func(d ResourceData) {
 		s.Scopes = append(s.Scopes, &management.ResourceServerScope{
 			Value:       String(d, "value"),
 			Description: String(d, "description"),
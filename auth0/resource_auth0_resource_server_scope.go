@@ -0,0 +1,198 @@
+package auth0
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
+)
+
+var (
+	errEmptyResourceServerScopeID         = fmt.Errorf("ID cannot be empty")
+	errInvalidResourceServerScopeIDFormat = fmt.Errorf("ID must be formated as resource_server_id:scope")
+)
+
+func newResourceServerScope() *schema.Resource {
+	return &schema.Resource{
+
+		Create: createResourceServerScope,
+		Read:   readResourceServerScope,
+		Update: updateResourceServerScope,
+		Delete: deleteResourceServerScope,
+		Importer: &schema.ResourceImporter{
+			State: importResourceServerScope,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"resource_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the resource server on which to define the scope",
+			},
+			"scope": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the scope. For example `read:foo`",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the scope",
+			},
+		},
+	}
+}
+
+func importResourceServerScope(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	rawID := d.Id()
+	if rawID == "" {
+		return []*schema.ResourceData{}, errEmptyResourceServerScopeID
+	}
+
+	// Scopes commonly contain ':' themselves (e.g. read:foo), so only split
+	// on the first occurrence.
+	idPair := strings.SplitN(rawID, ":", 2)
+	if len(idPair) != 2 || idPair[0] == "" || idPair[1] == "" {
+		return []*schema.ResourceData{}, errInvalidResourceServerScopeIDFormat
+	}
+
+	d.Set("resource_server_id", idPair[0])
+	d.Set("scope", idPair[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func createResourceServerScope(d *schema.ResourceData, m interface{}) error {
+	resourceServerID := d.Get("resource_server_id").(string)
+	scope := d.Get("scope").(string)
+
+	err := updateResourceServerScopes(m, resourceServerID, func(scopes []*management.ResourceServerScope) ([]*management.ResourceServerScope, error) {
+		for _, s := range scopes {
+			if s.GetValue() == scope {
+				return nil, fmt.Errorf("scope %q already exists on resource server %s", scope, resourceServerID)
+			}
+		}
+		return append(scopes, &management.ResourceServerScope{
+			Value:       auth0.String(scope),
+			Description: String(d, "description"),
+		}), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resourceServerID + ":" + scope)
+	return readResourceServerScope(d, m)
+}
+
+func readResourceServerScope(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	s, err := api.ResourceServer.Read(d.Get("resource_server_id").(string))
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	for _, scope := range s.Scopes {
+		if scope.GetValue() == d.Get("scope").(string) {
+			d.Set("description", scope.Description)
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] Scope %s no longer exists on resource server %s, removing from state", d.Get("scope"), s.GetID())
+	d.SetId("")
+	return nil
+}
+
+func updateResourceServerScope(d *schema.ResourceData, m interface{}) error {
+	resourceServerID := d.Get("resource_server_id").(string)
+	scope := d.Get("scope").(string)
+
+	err := updateResourceServerScopes(m, resourceServerID, func(scopes []*management.ResourceServerScope) ([]*management.ResourceServerScope, error) {
+		for _, s := range scopes {
+			if s.GetValue() == scope {
+				s.Description = String(d, "description")
+				return scopes, nil
+			}
+		}
+		return nil, fmt.Errorf("scope %q does not exist on resource server %s", scope, resourceServerID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return readResourceServerScope(d, m)
+}
+
+func deleteResourceServerScope(d *schema.ResourceData, m interface{}) error {
+	resourceServerID := d.Get("resource_server_id").(string)
+	scope := d.Get("scope").(string)
+
+	err := updateResourceServerScopes(m, resourceServerID, func(scopes []*management.ResourceServerScope) ([]*management.ResourceServerScope, error) {
+		r := make([]*management.ResourceServerScope, 0, len(scopes))
+		for _, s := range scopes {
+			if s.GetValue() != scope {
+				r = append(r, s)
+			}
+		}
+		return r, nil
+	})
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok {
+			if mErr.Status() == http.StatusNotFound {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceServerScopes is the payload used to patch the scopes of a resource
+// server. Unlike management.ResourceServer it allows sending an empty list,
+// which is needed to remove the last scope of a resource server.
+type resourceServerScopes struct {
+	Scopes []*management.ResourceServerScope `json:"scopes"`
+}
+
+// updateResourceServerScopes reads the scopes of a resource server, passes
+// them through fn and writes the result back.
+//
+// The Management API has no way of adding or removing a single scope, so
+// calls for the same resource server are serialized to prevent scopes added
+// within the same apply from overwriting each other.
+func updateResourceServerScopes(m interface{}, resourceServerID string, fn func([]*management.ResourceServerScope) ([]*management.ResourceServerScope, error)) error {
+
+	mutexKV.Lock(resourceServerID)
+	defer mutexKV.Unlock(resourceServerID)
+
+	api := m.(*config).api
+
+	s, err := api.ResourceServer.Read(resourceServerID, management.IncludeFields("id", "scopes"))
+	if err != nil {
+		return err
+	}
+
+	scopes, err := fn(s.Scopes)
+	if err != nil {
+		return err
+	}
+
+	return api.Request(http.MethodPatch, api.URI("resource-servers", resourceServerID), &resourceServerScopes{scopes})
+}
@@ -0,0 +1,133 @@
+package auth0
+
+import (
+	"testing"
+
+	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/random"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccResourceServerScope(t *testing.T) {
+
+	rand := random.String(6)
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: random.Template(testAccResourceServerScopeConfigCreate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_resource_server_scope.read", "scope", "read:foo"),
+					resource.TestCheckResourceAttr("auth0_resource_server_scope.read", "description", "Read foos"),
+					resource.TestCheckResourceAttr("auth0_resource_server_scope.create", "scope", "create:foo"),
+					resource.TestCheckResourceAttr("auth0_resource_server_scope.delete", "scope", "delete:foo"),
+				),
+			},
+			{
+				Config: random.Template(testAccResourceServerScopeConfigUpdate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_resource_server_scope.read", "description", "Read all the foos"),
+					resource.TestCheckResourceAttr("auth0_resource_server.my_resource_server", "scopes.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceServerScopeAuxConfig = `
+
+resource "auth0_resource_server" "my_resource_server" {
+	name = "Acceptance Test - Resource Server Scope - {{.random}}"
+	identifier = "https://uat.api.alexkappa.com/scope/{{.random}}"
+	lifecycle {
+		ignore_changes = [ scopes ]
+	}
+}
+`
+
+const testAccResourceServerScopeConfigCreate = testAccResourceServerScopeAuxConfig + `
+
+resource "auth0_resource_server_scope" "read" {
+	resource_server_id = "${auth0_resource_server.my_resource_server.id}"
+	scope = "read:foo"
+	description = "Read foos"
+}
+
+resource "auth0_resource_server_scope" "create" {
+	resource_server_id = "${auth0_resource_server.my_resource_server.id}"
+	scope = "create:foo"
+	description = "Create foos"
+}
+
+resource "auth0_resource_server_scope" "delete" {
+	resource_server_id = "${auth0_resource_server.my_resource_server.id}"
+	scope = "delete:foo"
+	description = "Delete foos"
+}
+`
+
+const testAccResourceServerScopeConfigUpdate = testAccResourceServerScopeAuxConfig + `
+
+resource "auth0_resource_server_scope" "read" {
+	resource_server_id = "${auth0_resource_server.my_resource_server.id}"
+	scope = "read:foo"
+	description = "Read all the foos"
+}
+
+resource "auth0_resource_server_scope" "create" {
+	resource_server_id = "${auth0_resource_server.my_resource_server.id}"
+	scope = "create:foo"
+	description = "Create foos"
+}
+`
+
+func TestResourceServerScopeImport(t *testing.T) {
+	for _, tt := range []struct {
+		id               string
+		resourceServerID string
+		scope            string
+		err              error
+	}{
+		{
+			id:               "5f1c2b7e8a1b2c3d4e5f6a7b:read:foo",
+			resourceServerID: "5f1c2b7e8a1b2c3d4e5f6a7b",
+			scope:            "read:foo",
+		},
+		{
+			id:               "5f1c2b7e8a1b2c3d4e5f6a7b:openid",
+			resourceServerID: "5f1c2b7e8a1b2c3d4e5f6a7b",
+			scope:            "openid",
+		},
+		{
+			id:  "5f1c2b7e8a1b2c3d4e5f6a7b",
+			err: errInvalidResourceServerScopeIDFormat,
+		},
+		{
+			id:  ":read:foo",
+			err: errInvalidResourceServerScopeIDFormat,
+		},
+	} {
+		t.Run(tt.id, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, newResourceServerScope().Schema, nil)
+			d.SetId(tt.id)
+
+			_, err := importResourceServerScope(d, nil)
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if v := d.Get("resource_server_id").(string); v != tt.resourceServerID {
+				t.Errorf("expected resource_server_id %q, got %q", tt.resourceServerID, v)
+			}
+			if v := d.Get("scope").(string); v != tt.scope {
+				t.Errorf("expected scope %q, got %q", tt.scope, v)
+			}
+		})
+	}
+}
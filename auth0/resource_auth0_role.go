@@ -59,7 +59,7 @@ func newRole() *schema.Resource {
 func createRole(d *schema.ResourceData, m interface{}) error {
 
 	c := expandRole(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Role.Create(c); err != nil {
 		return err
 	}
@@ -82,7 +82,7 @@ func createRole(d *schema.ResourceData, m interface{}) error {
 }
 
 func readRole(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	c, err := api.Role.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -122,7 +122,7 @@ func readRole(d *schema.ResourceData, m interface{}) error {
 
 func updateRole(d *schema.ResourceData, m interface{}) error {
 	c := expandRole(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Role.Update(d.Id(), c)
 	if err != nil {
 		return err
@@ -136,7 +136,7 @@ func updateRole(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteRole(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Role.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -178,7 +178,7 @@ func assignRolePermissions(d *schema.ResourceData, m interface{}) error {
 		})
 	}
 
-	api := m.(*management.Management)
+	api := m.(*config).api
 
 	if len(rmPermissions) > 0 {
 		err := api.Role.RemovePermissions(d.Id(), rmPermissions)
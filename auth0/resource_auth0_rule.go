@@ -52,7 +52,7 @@ func newRule() *schema.Resource {
 
 func createRule(d *schema.ResourceData, m interface{}) error {
 	c := buildRule(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	if err := api.Rule.Create(c); err != nil {
 		return err
 	}
@@ -61,7 +61,7 @@ func createRule(d *schema.ResourceData, m interface{}) error {
 }
 
 func readRule(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	c, err := api.Rule.Read(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -82,7 +82,7 @@ func readRule(d *schema.ResourceData, m interface{}) error {
 
 func updateRule(d *schema.ResourceData, m interface{}) error {
 	c := buildRule(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Rule.Update(d.Id(), c)
 	if err != nil {
 		return err
@@ -91,7 +91,7 @@ func updateRule(d *schema.ResourceData, m interface{}) error {
 }
 
 func deleteRule(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Rule.Delete(d.Id())
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -109,7 +109,7 @@ func buildRule(d *schema.ResourceData) *management.Rule {
 	return &management.Rule{
 		Name:    String(d, "name"),
 		Script:  String(d, "script"),
-		Order:   Int(d, "order"),
+		Order:   Int(d, "order", IsNewResource(), HasChange()),
 		Enabled: Bool(d, "enabled"),
 	}
 }
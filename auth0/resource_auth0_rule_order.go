@@ -0,0 +1,195 @@
+package auth0
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
+)
+
+// ruleOrderMutexKey is locked while rules are being reordered.
+const ruleOrderMutexKey = "auth0_rule_order"
+
+func newRuleOrder() *schema.Resource {
+	return &schema.Resource{
+
+		Create: createRuleOrder,
+		Read:   readRuleOrder,
+		Update: updateRuleOrder,
+		Delete: deleteRuleOrder,
+		Importer: &schema.ResourceImporter{
+			State: importRuleOrder,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"rule_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the rules, in the order in which they should execute",
+			},
+		},
+	}
+}
+
+// ruleOrderUpdate is a single change of the order of a rule.
+type ruleOrderUpdate struct {
+	id    string
+	order int
+}
+
+func importRuleOrder(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	api := m.(*config).api
+	rules, err := listRules(api)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].GetOrder() < rules[j].GetOrder()
+	})
+	ids := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.GetID())
+	}
+	d.Set("rule_ids", ids)
+	return []*schema.ResourceData{d}, nil
+}
+
+func createRuleOrder(d *schema.ResourceData, m interface{}) error {
+	d.SetId(resource.UniqueId())
+	return updateRuleOrder(d, m)
+}
+
+func readRuleOrder(d *schema.ResourceData, m interface{}) error {
+	api := m.(*config).api
+	rules, err := listRules(api)
+	if err != nil {
+		return err
+	}
+
+	orders := make(map[string]int, len(rules))
+	for _, rule := range rules {
+		orders[rule.GetID()] = rule.GetOrder()
+	}
+
+	// Rules which no longer exist are dropped, and the remaining ones are
+	// sorted by their actual order so that reordering is detected as drift.
+	var ids []string
+	for _, id := range *castToListOfStrings(d.Get("rule_ids").([]interface{})) {
+		if _, ok := orders[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		return orders[ids[i]] < orders[ids[j]]
+	})
+
+	d.Set("rule_ids", ids)
+	return nil
+}
+
+func updateRuleOrder(d *schema.ResourceData, m interface{}) error {
+	mutexKV.Lock(ruleOrderMutexKey)
+	defer mutexKV.Unlock(ruleOrderMutexKey)
+
+	api := m.(*config).api
+	rules, err := listRules(api)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]int, len(rules))
+	for _, rule := range rules {
+		current[rule.GetID()] = rule.GetOrder()
+	}
+
+	updates, err := planRuleOrderUpdates(current, *castToListOfStrings(d.Get("rule_ids").([]interface{})))
+	if err != nil {
+		return err
+	}
+
+	for _, u := range updates {
+		log.Printf("[DEBUG] Setting the order of rule %s to %d", u.id, u.order)
+		if err := api.Rule.Update(u.id, &management.Rule{Order: auth0.Int(u.order)}); err != nil {
+			return err
+		}
+	}
+
+	return readRuleOrder(d, m)
+}
+
+func deleteRuleOrder(d *schema.ResourceData, m interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+// planRuleOrderUpdates returns the sequence of updates which sets the order of
+// the rules in ids to 1, 2, 3, ... without ever assigning an order which is
+// already in use, as Auth0 requires the order of every rule to be unique.
+//
+// Rules which need to move are first given temporary orders above every order
+// in use, and then moved to their final order. Rules that are not part of ids
+// are left untouched, and an error is returned if one of them holds an order
+// which is needed.
+func planRuleOrderUpdates(current map[string]int, ids []string) ([]ruleOrderUpdate, error) {
+	target := make(map[string]int, len(ids))
+	for i, id := range ids {
+		if _, ok := current[id]; !ok {
+			return nil, fmt.Errorf("rule %s does not exist", id)
+		}
+		if _, ok := target[id]; ok {
+			return nil, fmt.Errorf("rule %s is listed more than once", id)
+		}
+		target[id] = i + 1
+	}
+
+	offset := len(ids)
+	for id, order := range current {
+		if _, ok := target[id]; !ok && order <= len(ids) {
+			return nil, fmt.Errorf("rule %s is not part of the rule order but has order %d, "+
+				"which is needed by the rule order", id, order)
+		}
+		if order > offset {
+			offset = order
+		}
+	}
+
+	var moves []string
+	for _, id := range ids {
+		if current[id] != target[id] {
+			moves = append(moves, id)
+		}
+	}
+
+	updates := make([]ruleOrderUpdate, 0, 2*len(moves))
+	for i, id := range moves {
+		updates = append(updates, ruleOrderUpdate{id, offset + i + 1})
+	}
+	for _, id := range moves {
+		updates = append(updates, ruleOrderUpdate{id, target[id]})
+	}
+	return updates, nil
+}
+
+func listRules(api *management.Management) ([]*management.Rule, error) {
+	var rules []*management.Rule
+	var page int
+	for {
+		l, err := api.Rule.List(management.Page(page), management.IncludeFields("id", "order"))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, l.Rules...)
+		if !l.HasNext() {
+			break
+		}
+		page++
+	}
+	return rules, nil
+}
@@ -0,0 +1,162 @@
+package auth0
+
+import (
+	"testing"
+
+	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/random"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccRuleOrder(t *testing.T) {
+
+	rand := random.String(6)
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: random.Template(testAccRuleOrderCreate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("auth0_rule_order.my_rule_order", "rule_ids.0", "auth0_rule.first", "id"),
+					resource.TestCheckResourceAttrPair("auth0_rule_order.my_rule_order", "rule_ids.1", "auth0_rule.second", "id"),
+					resource.TestCheckResourceAttrPair("auth0_rule_order.my_rule_order", "rule_ids.2", "auth0_rule.third", "id"),
+				),
+			},
+			{
+				Config: random.Template(testAccRuleOrderUpdate, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("auth0_rule_order.my_rule_order", "rule_ids.0", "auth0_rule.third", "id"),
+					resource.TestCheckResourceAttrPair("auth0_rule_order.my_rule_order", "rule_ids.1", "auth0_rule.first", "id"),
+					resource.TestCheckResourceAttrPair("auth0_rule_order.my_rule_order", "rule_ids.2", "auth0_rule.second", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccRuleOrderAux = `
+
+resource "auth0_rule" "first" {
+  name = "acceptance-test-order-first-{{.random}}"
+  script = "function (user, context, callback) { callback(null, user, context); }"
+}
+
+resource "auth0_rule" "second" {
+  name = "acceptance-test-order-second-{{.random}}"
+  script = "function (user, context, callback) { callback(null, user, context); }"
+}
+
+resource "auth0_rule" "third" {
+  name = "acceptance-test-order-third-{{.random}}"
+  script = "function (user, context, callback) { callback(null, user, context); }"
+}
+`
+
+const testAccRuleOrderCreate = testAccRuleOrderAux + `
+
+resource "auth0_rule_order" "my_rule_order" {
+  rule_ids = [
+    auth0_rule.first.id,
+    auth0_rule.second.id,
+    auth0_rule.third.id,
+  ]
+}
+`
+
+const testAccRuleOrderUpdate = testAccRuleOrderAux + `
+
+resource "auth0_rule_order" "my_rule_order" {
+  rule_ids = [
+    auth0_rule.third.id,
+    auth0_rule.first.id,
+    auth0_rule.second.id,
+  ]
+}
+`
+
+func TestPlanRuleOrderUpdates(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		current map[string]int
+		ids     []string
+		err     bool
+	}{
+		{
+			name:    "Unchanged",
+			current: map[string]int{"a": 1, "b": 2, "c": 3},
+			ids:     []string{"a", "b", "c"},
+		},
+		{
+			name:    "Reversed",
+			current: map[string]int{"a": 1, "b": 2, "c": 3},
+			ids:     []string{"c", "b", "a"},
+		},
+		{
+			name:    "Rotated",
+			current: map[string]int{"a": 1, "b": 2, "c": 3},
+			ids:     []string{"b", "c", "a"},
+		},
+		{
+			name:    "Sparse",
+			current: map[string]int{"a": 5, "b": 1, "c": 10, "other": 20},
+			ids:     []string{"a", "b", "c"},
+		},
+		{
+			name:    "Conflict",
+			current: map[string]int{"a": 1, "b": 2, "other": 3},
+			ids:     []string{"a", "b", "other2"},
+			err:     true,
+		},
+		{
+			name:    "Unmanaged",
+			current: map[string]int{"a": 1, "b": 3, "other": 2},
+			ids:     []string{"a", "b"},
+			err:     true,
+		},
+		{
+			name:    "Duplicate",
+			current: map[string]int{"a": 1, "b": 2},
+			ids:     []string{"a", "a"},
+			err:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, err := planRuleOrderUpdates(tt.current, tt.ids)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			state := make(map[string]int)
+			used := make(map[int]string)
+			for id, order := range tt.current {
+				state[id] = order
+				used[order] = id
+			}
+			for _, u := range updates {
+				if owner, ok := used[u.order]; ok && owner != u.id {
+					t.Fatalf("setting order %d on rule %s conflicts with rule %s", u.order, u.id, owner)
+				}
+				delete(used, state[u.id])
+				state[u.id] = u.order
+				used[u.order] = u.id
+			}
+			for i, id := range tt.ids {
+				if state[id] != i+1 {
+					t.Errorf("expected rule %s to have order %d, got %d", id, i+1, state[id])
+				}
+			}
+			if tt.name == "Unchanged" && len(updates) != 0 {
+				t.Errorf("expected no updates, got %v", updates)
+			}
+		})
+	}
+}
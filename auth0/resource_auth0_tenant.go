@@ -12,12 +12,25 @@ import (
 	v "github.com/alexkappa/terraform-provider-auth0/auth0/internal/validation"
 )
 
+// tenantLegacyPages maps the legacy settings of the tenant to the hosted page
+// they set, which is managed by auth0_pages.
+var tenantLegacyPages = map[string]string{
+	"change_password":   "change_password",
+	"guardian_mfa_page": "guardian_mfa",
+	"error_page":        "error",
+}
+
 func newTenant() *schema.Resource {
-	return &schema.Resource{
+	legacy := make([]string, 0, len(tenantLegacyPages))
+	for key := range tenantLegacyPages {
+		legacy = append(legacy, key)
+	}
 
-		Create: createTenant,
-		Read:   readTenant,
-		Update: updateTenant,
+	tenant := &schema.Resource{
+
+		Create: readLegacyPages(createTenant, legacy...),
+		Read:   readLegacyPages(readTenant, legacy...),
+		Update: readLegacyPages(updateTenant, legacy...),
 		Delete: deleteTenant,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -25,10 +38,10 @@ func newTenant() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"change_password": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Computed: true,
+				Type:       schema.TypeList,
+				Deprecated: "Use the change_password page of the auth0_pages resource instead",
+				Optional:   true,
+				MaxItems:   1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"enabled": {
@@ -43,10 +56,10 @@ func newTenant() *schema.Resource {
 				},
 			},
 			"guardian_mfa_page": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Computed: true,
+				Type:       schema.TypeList,
+				Deprecated: "Use the guardian_mfa page of the auth0_pages resource instead",
+				Optional:   true,
+				MaxItems:   1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"enabled": {
@@ -71,10 +84,10 @@ func newTenant() *schema.Resource {
 				Computed: true,
 			},
 			"error_page": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Computed: true,
-				MaxItems: 1,
+				Type:       schema.TypeList,
+				Deprecated: "Use the error page of the auth0_pages resource instead",
+				Optional:   true,
+				MaxItems:   1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"html": {
@@ -246,6 +259,9 @@ func newTenant() *schema.Resource {
 			},
 		},
 	}
+	tenant.CustomizeDiff = validateLegacyPages("auth0_tenant", tenant.Schema, tenantLegacyPages)
+
+	return tenant
 }
 
 func createTenant(d *schema.ResourceData, m interface{}) error {
@@ -254,7 +270,7 @@ func createTenant(d *schema.ResourceData, m interface{}) error {
 }
 
 func readTenant(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
+	api := m.(*config).api
 	t, err := api.Tenant.Read()
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
@@ -291,7 +307,7 @@ func readTenant(d *schema.ResourceData, m interface{}) error {
 
 func updateTenant(d *schema.ResourceData, m interface{}) error {
 	t := buildTenant(d)
-	api := m.(*management.Management)
+	api := m.(*config).api
 	err := api.Tenant.Update(t)
 	if err != nil {
 		return err
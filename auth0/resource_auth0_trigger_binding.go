@@ -1,8 +1,11 @@
 package auth0
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 
@@ -21,6 +24,7 @@ func newTriggerBinding() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: validateTriggerBinding,
 
 		Schema: map[string]*schema.Schema{
 			"trigger": {
@@ -48,8 +52,13 @@ func newTriggerBinding() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						"id": {
 							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Trigger ID",
+							Optional:    true,
+							Description: "ID of the action to bind. Exactly one of `id` or `name` must be set",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the action to bind. Exactly one of `id` or `name` must be set",
 						},
 						"display_name": {
 							Type:        schema.TypeString,
@@ -60,25 +69,91 @@ func newTriggerBinding() *schema.Resource {
 				},
 				Description: "The actions bound to this trigger",
 			},
+			"authoritative": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				ForceNew: true,
+				Description: "Whether this resource manages every action bound to " +
+					"the trigger. When false, only the actions listed in `actions` " +
+					"are managed, and the actions bound by others are left in place",
+			},
+			"position": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description: "Position, starting at 1, at which the actions are " +
+					"inserted among the actions bound to the trigger. Can only be " +
+					"set when `authoritative` is false. Defaults to the end",
+			},
 		},
 	}
 }
 
+func validateTriggerBinding(d *schema.ResourceDiff, m interface{}) error {
+	if _, ok := d.GetOk("position"); ok && d.Get("authoritative").(bool) {
+		return fmt.Errorf("position can only be set when authoritative is false")
+	}
+
+	actions := d.Get("actions").([]interface{})
+	for i := range actions {
+		idKnown := d.NewValueKnown(fmt.Sprintf("actions.%d.id", i))
+		nameKnown := d.NewValueKnown(fmt.Sprintf("actions.%d.name", i))
+		id := d.Get(fmt.Sprintf("actions.%d.id", i)).(string)
+		name := d.Get(fmt.Sprintf("actions.%d.name", i)).(string)
+
+		if id != "" && name != "" {
+			return fmt.Errorf("actions.%d: only one of id or name can be set", i)
+		}
+		if id == "" && name == "" && idKnown && nameKnown {
+			return fmt.Errorf("actions.%d: one of id or name must be set", i)
+		}
+	}
+
+	// Binding an action which was never deployed fails with an error which
+	// doesn't say which action is at fault, so it is checked here instead.
+	// Actions whose ID or name is not known yet, such as those created in the
+	// same apply, are checked when binding them.
+	if !d.HasChange("actions") {
+		return nil
+	}
+	api := m.(*config).api
+	for i := range actions {
+		if !d.NewValueKnown(fmt.Sprintf("actions.%d.id", i)) || !d.NewValueKnown(fmt.Sprintf("actions.%d.name", i)) {
+			continue
+		}
+		a, err := undeployedTriggerBindingAction(api, d, i)
+		if err != nil {
+			return err
+		}
+		if a != nil {
+			return errUndeployedTriggerBindingAction(i, a)
+		}
+	}
+	return nil
+}
+
 func createTriggerBinding(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	id := d.Get("trigger").(string)
+	trigger := d.Get("trigger").(string)
+	if !d.Get("authoritative").(bool) {
+		d.SetId(trigger + ":" + resource.UniqueId())
+		return updateTriggerBinding(d, m)
+	}
+
+	api := m.(*config).api
 	b := expandTriggerBindings(d)
-	err := api.Action.UpdateBindings(id, b)
+	err := bindTriggerActions(api, d, trigger, b)
 	if err != nil {
 		return err
 	}
-	d.SetId(id)
+	d.SetId(trigger)
 	return readTriggerBinding(d, m)
 }
 
 func readTriggerBinding(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	b, err := api.Action.Bindings(d.Id())
+	api := m.(*config).api
+	trigger, authoritative := triggerBindingTrigger(d.Id())
+	b, err := api.Action.Bindings(trigger)
 	if err != nil {
 		if mErr, ok := err.(management.Error); ok {
 			if mErr.Status() == http.StatusNotFound {
@@ -89,24 +164,94 @@ func readTriggerBinding(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
-	d.Set("actions", flattenTriggerBindingActions(b.Bindings))
+	d.Set("trigger", trigger)
+	d.Set("authoritative", authoritative)
 
+	prior := d.Get("actions").([]interface{})
+	if authoritative {
+		d.Set("actions", flattenTriggerBindingActions(b.Bindings, prior))
+		return nil
+	}
+
+	managed := managedTriggerBindingActions(prior)
+	var bindings []*management.ActionBinding
+	position := 0
+	for i, binding := range b.Bindings {
+		if managed.has(binding) {
+			if position == 0 {
+				position = i + 1
+			}
+			bindings = append(bindings, binding)
+		}
+	}
+	d.Set("actions", flattenTriggerBindingActions(bindings, prior))
+
+	if _, ok := d.GetOk("position"); ok && position != 0 {
+		d.Set("position", position)
+	}
 	return nil
 }
 
 func updateTriggerBinding(d *schema.ResourceData, m interface{}) error {
-	b := expandTriggerBindings(d)
-	api := m.(*management.Management)
-	err := api.Action.UpdateBindings(d.Id(), b)
+	api := m.(*config).api
+	trigger, authoritative := triggerBindingTrigger(d.Id())
+
+	if authoritative {
+		b := expandTriggerBindings(d)
+		err := bindTriggerActions(api, d, trigger, b)
+		if err != nil {
+			return err
+		}
+		return readTriggerBinding(d, m)
+	}
+
+	mutexKey := "auth0_trigger_binding:" + trigger
+	mutexKV.Lock(mutexKey)
+	defer mutexKV.Unlock(mutexKey)
+
+	current, err := api.Action.Bindings(trigger)
 	if err != nil {
 		return err
 	}
+
+	// Both the actions managed until now and the ones to be bound are
+	// removed, before the latter are inserted at the requested position.
+	o, n := d.GetChange("actions")
+	managed := managedTriggerBindingActions(o.([]interface{}))
+	managed.add(managedTriggerBindingActions(n.([]interface{})))
+
+	b := mergeTriggerBindings(current.Bindings, expandTriggerBindings(d), managed, d.Get("position").(int))
+	if err := bindTriggerActions(api, d, trigger, b); err != nil {
+		return err
+	}
 	return readTriggerBinding(d, m)
 }
 
 func deleteTriggerBinding(d *schema.ResourceData, m interface{}) error {
-	api := m.(*management.Management)
-	if err := api.Action.UpdateBindings(d.Id(), []*management.ActionBinding{}); err != nil {
+	api := m.(*config).api
+	trigger, authoritative := triggerBindingTrigger(d.Id())
+
+	b := []*management.ActionBinding{}
+	if !authoritative {
+		mutexKey := "auth0_trigger_binding:" + trigger
+		mutexKV.Lock(mutexKey)
+		defer mutexKV.Unlock(mutexKey)
+
+		current, err := api.Action.Bindings(trigger)
+		if err != nil {
+			if mErr, ok := err.(management.Error); ok {
+				if mErr.Status() == http.StatusNotFound {
+					d.SetId("")
+					return nil
+				}
+			}
+			return err
+		}
+		managed := managedTriggerBindingActions(d.Get("actions").([]interface{}))
+		b = mergeTriggerBindings(current.Bindings, nil, managed, 0)
+	}
+
+	if err := api.Action.UpdateBindings(trigger, b); err != nil {
 		if mErr, ok := err.(management.Error); ok {
 			if mErr.Status() == http.StatusNotFound {
 				d.SetId("")
@@ -118,25 +263,177 @@ func deleteTriggerBinding(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func expandTriggerBindings(d *schema.ResourceData) (b []*management.ActionBinding) {
-	List(d, "actions").Elem(func(d ResourceData) {
+// bindTriggerActions updates the actions bound to a trigger. Undeployed
+// actions are checked when planning, except those which were not known then,
+// so when binding fails the actions are checked again to report them.
+func bindTriggerActions(api *management.Management, d *schema.ResourceData, trigger string, b []*management.ActionBinding) error {
+	err := api.Action.UpdateBindings(trigger, b)
+	if err == nil {
+		return nil
+	}
+	for i := range d.Get("actions").([]interface{}) {
+		if a, _ := undeployedTriggerBindingAction(api, d, i); a != nil {
+			return fmt.Errorf("%s: %w", errUndeployedTriggerBindingAction(i, a), err)
+		}
+	}
+	return err
+}
+
+// undeployedTriggerBindingAction returns the i-th action of a binding if it
+// has no deployed version. Actions which can't be found are left to the API
+// to report.
+func undeployedTriggerBindingAction(api *management.Management, d interface{ Get(string) interface{} }, i int) (*management.Action, error) {
+	var a *management.Action
+	var err error
+	if id := d.Get(fmt.Sprintf("actions.%d.id", i)).(string); id != "" {
+		a, err = api.Action.Read(id)
+	} else if name := d.Get(fmt.Sprintf("actions.%d.name", i)).(string); name != "" {
+		a, err = readActionByName(api, name)
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		if mErr, ok := err.(management.Error); ok && mErr.Status() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if a == nil || a.DeployedVersion != nil {
+		return nil, nil
+	}
+	return a, nil
+}
+
+func errUndeployedTriggerBindingAction(i int, a *management.Action) error {
+	return fmt.Errorf("actions.%d: action %q has no deployed version, "+
+		"set deploy to true on the action or deploy it before binding it", i, a.GetName())
+}
+
+// triggerBindingTrigger returns the trigger of a binding given its ID, and
+// whether the binding is authoritative. Non-authoritative bindings have an ID
+// of the form trigger:unique-id, as several of them can target one trigger.
+func triggerBindingTrigger(id string) (trigger string, authoritative bool) {
+	if i := strings.Index(id, ":"); i >= 0 {
+		return id[:i], false
+	}
+	return id, true
+}
+
+// triggerBindingActions is a set of the IDs and names of bound actions.
+type triggerBindingActions map[string]bool
+
+func managedTriggerBindingActions(actions []interface{}) triggerBindingActions {
+	managed := make(triggerBindingActions)
+	for _, a := range actions {
+		action, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, _ := action["id"].(string); id != "" {
+			managed["id:"+id] = true
+		}
+		if name, _ := action["name"].(string); name != "" {
+			managed["name:"+name] = true
+		}
+	}
+	return managed
+}
+
+func (t triggerBindingActions) add(other triggerBindingActions) {
+	for k := range other {
+		t[k] = true
+	}
+}
+
+func (t triggerBindingActions) has(b *management.ActionBinding) bool {
+	return t["id:"+b.Action.GetID()] || t["name:"+b.Action.GetName()]
+}
+
+// mergeTriggerBindings returns the bindings of a trigger after the managed
+// actions are removed from the current bindings, and the desired bindings are
+// inserted at position, starting at 1. A position of 0 appends them.
+func mergeTriggerBindings(current, desired []*management.ActionBinding, managed triggerBindingActions, position int) []*management.ActionBinding {
+	b := make([]*management.ActionBinding, 0, len(current)+len(desired))
+	for _, binding := range current {
+		if managed.has(binding) {
+			continue
+		}
 		b = append(b, &management.ActionBinding{
 			Ref: &management.ActionBindingReference{
-				Type:  auth0.String("action_id"),
-				Value: String(d, "id"),
+				Type:  auth0.String(management.ActionBindingReferenceById),
+				Value: binding.Action.ID,
 			},
+			DisplayName: binding.DisplayName,
+		})
+	}
+
+	i := len(b)
+	if position > 0 && position-1 < i {
+		i = position - 1
+	}
+	return append(b[:i], append(desired, b[i:]...)...)
+}
+
+func expandTriggerBindings(d *schema.ResourceData) (b []*management.ActionBinding) {
+	List(d, "actions").Elem(func(d ResourceData) {
+		ref := &management.ActionBindingReference{
+			Type:  auth0.String(management.ActionBindingReferenceById),
+			Value: String(d, "id"),
+		}
+		if ref.Value == nil {
+			ref = &management.ActionBindingReference{
+				Type:  auth0.String(management.ActionBindingReferenceByName),
+				Value: String(d, "name"),
+			}
+		}
+		b = append(b, &management.ActionBinding{
+			Ref:         ref,
 			DisplayName: String(d, "display_name"),
 		})
 	})
 	return
 }
 
-func flattenTriggerBindingActions(bindings []*management.ActionBinding) (r []interface{}) {
-	for _, b := range bindings {
-		r = append(r, map[string]interface{}{
+// flattenTriggerBindingActions flattens the bound actions, referencing each
+// of them the same way as the action at the same position in prior, so that
+// actions bound by name are read back by name. Actions are referenced by ID
+// otherwise, as is the case after an import.
+func flattenTriggerBindingActions(bindings []*management.ActionBinding, prior []interface{}) (r []interface{}) {
+	for i, b := range bindings {
+		byName := false
+		if i < len(prior) {
+			if p, ok := prior[i].(map[string]interface{}); ok {
+				id, _ := p["id"].(string)
+				name, _ := p["name"].(string)
+				byName = id == "" && name != ""
+			}
+		}
+
+		action := map[string]interface{}{
 			"id":           b.Action.GetID(),
+			"name":         "",
 			"display_name": b.GetDisplayName(),
-		})
+		}
+		if byName {
+			action["id"] = ""
+			action["name"] = b.Action.GetName()
+		}
+		r = append(r, action)
 	}
 	return
 }
+
+// readActionByName returns the action with the given name, or nil if there is
+// none.
+func readActionByName(api *management.Management, name string) (*management.Action, error) {
+	l, err := api.Action.List(management.Parameter("actionName", name))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range l.Actions {
+		if a.GetName() == name {
+			return api.Action.Read(a.GetID())
+		}
+	}
+	return nil, nil
+}
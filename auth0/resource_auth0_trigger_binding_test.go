@@ -1,11 +1,19 @@
 package auth0
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/random"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
 )
 
 func TestAccTriggerBinding(t *testing.T) {
@@ -37,6 +45,15 @@ func TestAccTriggerBinding(t *testing.T) {
 					random.TestCheckResourceAttr("auth0_trigger_binding.login_flow", "actions.1.display_name", "Test Trigger Binding Foo {{.random}}", rand),
 				),
 			},
+			{
+				Config: random.Template(testAccTriggerBindingConfigByName, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_trigger_binding.login_flow", "actions.#", "2"),
+					random.TestCheckResourceAttr("auth0_trigger_binding.login_flow", "actions.0.name", "Test Trigger Binding Foo {{.random}}", rand),
+					resource.TestCheckResourceAttr("auth0_trigger_binding.login_flow", "actions.0.id", ""),
+					random.TestCheckResourceAttr("auth0_trigger_binding.login_flow", "actions.1.name", "Test Trigger Binding Bar {{.random}}", rand),
+				),
+			},
 		},
 	})
 }
@@ -101,3 +118,256 @@ resource auth0_trigger_binding login_flow {
 	}
 }
 `
+
+const testAccTriggerBindingConfigByName = testAccTriggerBindingAction + `
+
+resource auth0_trigger_binding login_flow {
+	trigger = "post-login"
+	actions {
+		name = auth0_action.action_foo.name
+		display_name = auth0_action.action_foo.name
+	}
+	actions {
+		name = auth0_action.action_bar.name
+		display_name = auth0_action.action_bar.name
+	}
+}
+`
+
+func TestAccTriggerBindingNonAuthoritative(t *testing.T) {
+
+	rand := random.String(6)
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: random.Template(testAccTriggerBindingConfigNonAuthoritative, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_trigger_binding.foo", "authoritative", "false"),
+					resource.TestCheckResourceAttr("auth0_trigger_binding.foo", "actions.#", "1"),
+					random.TestCheckResourceAttr("auth0_trigger_binding.foo", "actions.0.display_name", "Test Trigger Binding Foo {{.random}}", rand),
+					resource.TestCheckResourceAttr("auth0_trigger_binding.bar", "actions.#", "1"),
+					random.TestCheckResourceAttr("auth0_trigger_binding.bar", "actions.0.display_name", "Test Trigger Binding Bar {{.random}}", rand),
+					resource.TestCheckResourceAttr("auth0_trigger_binding.bar", "position", "1"),
+				),
+			},
+			{
+				Config:      random.Template(testAccTriggerBindingConfigInvalidPosition, rand),
+				ExpectError: regexp.MustCompile("position can only be set when authoritative is false"),
+			},
+		},
+	})
+}
+
+const testAccTriggerBindingConfigNonAuthoritative = testAccTriggerBindingAction + `
+
+resource auth0_trigger_binding foo {
+	trigger = "post-login"
+	authoritative = false
+	actions {
+		id = auth0_action.action_foo.id
+		display_name = auth0_action.action_foo.name
+	}
+}
+
+resource auth0_trigger_binding bar {
+	depends_on = [ auth0_trigger_binding.foo ]
+	trigger = "post-login"
+	authoritative = false
+	position = 1
+	actions {
+		id = auth0_action.action_bar.id
+		display_name = auth0_action.action_bar.name
+	}
+}
+`
+
+const testAccTriggerBindingConfigInvalidPosition = testAccTriggerBindingAction + `
+
+resource auth0_trigger_binding foo {
+	trigger = "post-login"
+	position = 1
+	actions {
+		id = auth0_action.action_foo.id
+		display_name = auth0_action.action_foo.name
+	}
+}
+`
+
+func TestAccTriggerBindingUndeployedAction(t *testing.T) {
+
+	rand := random.String(6)
+
+	resource.Test(t, resource.TestCase{
+		Providers: map[string]terraform.ResourceProvider{
+			"auth0": Provider(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: random.Template(testAccTriggerBindingUndeployedAction, rand),
+			},
+			{
+				Config:      random.Template(testAccTriggerBindingConfigUndeployedAction, rand),
+				ExpectError: regexp.MustCompile("has no deployed version"),
+			},
+			{
+				// The action is created and deployed in the same apply as it
+				// is bound, so it can't be checked when planning.
+				Config: random.Template(testAccTriggerBindingConfigDeployedAction, rand),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("auth0_trigger_binding.login_flow", "actions.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+const testAccTriggerBindingUndeployedAction = `
+
+resource auth0_action action_undeployed {
+	name = "Test Trigger Binding Undeployed {{.random}}"
+	supported_triggers {
+		id = "post-login"
+		version = "v2"
+	}
+	code = "exports.onExecutePostLogin = async (event, api) => {};"
+}
+`
+
+const testAccTriggerBindingConfigUndeployedAction = testAccTriggerBindingUndeployedAction + `
+
+resource auth0_trigger_binding login_flow {
+	trigger = "post-login"
+	actions {
+		id = auth0_action.action_undeployed.id
+		display_name = auth0_action.action_undeployed.name
+	}
+}
+`
+
+const testAccTriggerBindingConfigDeployedAction = `
+
+resource auth0_action action_deployed {
+	name = "Test Trigger Binding Deployed {{.random}}"
+	supported_triggers {
+		id = "post-login"
+		version = "v2"
+	}
+	code = "exports.onExecutePostLogin = async (event, api) => {};"
+	deploy = true
+}
+
+resource auth0_trigger_binding login_flow {
+	trigger = "post-login"
+	actions {
+		id = auth0_action.action_deployed.id
+		display_name = auth0_action.action_deployed.name
+	}
+}
+`
+
+func TestValidateTriggerBindingUndeployedAction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/actions/actions/act_deployed":
+			w.Write([]byte(`{"id": "act_deployed", "name": "deployed", "deployed_version": {"id": "v1"}}`))
+		case "/api/v2/actions/actions/act_undeployed":
+			w.Write([]byte(`{"id": "act_undeployed", "name": "undeployed"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	api, err := management.New(strings.TrimPrefix(server.URL, "http://"), management.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"Deployed", "act_deployed", ""},
+		{"Undeployed", "act_undeployed", `actions.0: action "undeployed" has no deployed version`},
+		// The value the SDK uses for values which are not known yet.
+		{"Unknown", "74D93920-ED26-11E3-AC10-0800200C9A66", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTriggerBinding().Diff(nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"trigger": "post-login",
+				"actions": []interface{}{map[string]interface{}{
+					"id":           tt.id,
+					"display_name": "action",
+				}},
+			}), &config{api: api})
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMergeTriggerBindings(t *testing.T) {
+	binding := func(id, name string) *management.ActionBinding {
+		return &management.ActionBinding{
+			Action:      &management.Action{ID: auth0.String(id), Name: auth0.String(name)},
+			DisplayName: auth0.String(name),
+		}
+	}
+	ref := func(value string) *management.ActionBinding {
+		return &management.ActionBinding{
+			Ref: &management.ActionBindingReference{
+				Type:  auth0.String(management.ActionBindingReferenceById),
+				Value: auth0.String(value),
+			},
+		}
+	}
+	current := []*management.ActionBinding{binding("a", "A"), binding("b", "B"), binding("c", "C")}
+
+	for _, tt := range []struct {
+		name     string
+		managed  triggerBindingActions
+		desired  []*management.ActionBinding
+		position int
+		expected []string
+	}{
+		{"Append", triggerBindingActions{}, []*management.ActionBinding{ref("x")}, 0, []string{"a", "b", "c", "x"}},
+		{"First", triggerBindingActions{}, []*management.ActionBinding{ref("x")}, 1, []string{"x", "a", "b", "c"}},
+		{"Middle", triggerBindingActions{}, []*management.ActionBinding{ref("x"), ref("y")}, 2, []string{"a", "x", "y", "b", "c"}},
+		{"PastEnd", triggerBindingActions{}, []*management.ActionBinding{ref("x")}, 10, []string{"a", "b", "c", "x"}},
+		{"Move", triggerBindingActions{"id:c": true}, []*management.ActionBinding{ref("c")}, 1, []string{"c", "a", "b"}},
+		{"ByName", triggerBindingActions{"name:B": true}, []*management.ActionBinding{ref("b")}, 0, []string{"a", "c", "b"}},
+		{"Remove", triggerBindingActions{"id:a": true}, nil, 0, []string{"b", "c"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, b := range mergeTriggerBindings(current, tt.desired, tt.managed, tt.position) {
+				ids = append(ids, b.Ref.GetValue())
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestTriggerBindingTrigger(t *testing.T) {
+	if trigger, authoritative := triggerBindingTrigger("post-login"); trigger != "post-login" || !authoritative {
+		t.Errorf("expected an authoritative post-login binding, got %q %t", trigger, authoritative)
+	}
+	if trigger, authoritative := triggerBindingTrigger("post-login:20210101000000000000000001"); trigger != "post-login" || authoritative {
+		t.Errorf("expected a non-authoritative post-login binding, got %q %t", trigger, authoritative)
+	}
+}
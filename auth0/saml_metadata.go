@@ -0,0 +1,175 @@
+package auth0
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	samlBindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlBindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+)
+
+// samlMetadata holds the settings of a SAML identity provider, as read from
+// its metadata.
+type samlMetadata struct {
+	EntityID        string
+	SignInEndpoint  string
+	SignOutEndpoint string
+	ProtocolBinding string
+	SigningCert     string
+}
+
+// samlEntityDescriptor is an EntityDescriptor or EntitiesDescriptor element
+// of SAML metadata. Namespaces are ignored, as identity providers don't agree
+// on their prefixes.
+type samlEntityDescriptor struct {
+	XMLName           xml.Name
+	EntityID          string                 `xml:"entityID,attr"`
+	IDPSSODescriptors []samlIDPSSODescriptor `xml:"IDPSSODescriptor"`
+	EntityDescriptors []samlEntityDescriptor `xml:"EntityDescriptor"`
+}
+
+type samlIDPSSODescriptor struct {
+	KeyDescriptors       []samlKeyDescriptor `xml:"KeyDescriptor"`
+	SingleSignOnServices []samlEndpoint      `xml:"SingleSignOnService"`
+	SingleLogoutServices []samlEndpoint      `xml:"SingleLogoutService"`
+}
+
+type samlKeyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+type samlEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// parseSAMLMetadata reads the settings of the identity provider described by
+// SAML metadata. When the metadata describes several entities, the first
+// identity provider among them is read.
+func parseSAMLMetadata(metadata string) (*samlMetadata, error) {
+	var e samlEntityDescriptor
+	if err := xml.Unmarshal([]byte(metadata), &e); err != nil {
+		return nil, fmt.Errorf("invalid SAML metadata: %w", err)
+	}
+
+	entities := []samlEntityDescriptor{e}
+	if e.XMLName.Local == "EntitiesDescriptor" {
+		entities = e.EntityDescriptors
+	}
+	for _, e := range entities {
+		if len(e.IDPSSODescriptors) == 0 {
+			continue
+		}
+		return parseSAMLIDPSSODescriptor(e.EntityID, e.IDPSSODescriptors[0])
+	}
+	return nil, fmt.Errorf("invalid SAML metadata: no IDPSSODescriptor found")
+}
+
+func parseSAMLIDPSSODescriptor(entityID string, d samlIDPSSODescriptor) (*samlMetadata, error) {
+	m := &samlMetadata{EntityID: entityID}
+
+	// The redirect binding is preferred, as it is the default of Auth0.
+	for _, binding := range []string{samlBindingHTTPRedirect, samlBindingHTTPPost} {
+		if s, ok := samlEndpointWithBinding(d.SingleSignOnServices, binding); ok && m.SignInEndpoint == "" {
+			m.SignInEndpoint = s.Location
+			m.ProtocolBinding = s.Binding
+		}
+		if s, ok := samlEndpointWithBinding(d.SingleLogoutServices, binding); ok && m.SignOutEndpoint == "" {
+			m.SignOutEndpoint = s.Location
+		}
+	}
+	if m.SignInEndpoint == "" {
+		return nil, fmt.Errorf("invalid SAML metadata: no SingleSignOnService found with a supported binding")
+	}
+
+	// Keys without a use are used for both signing and encryption.
+	for _, k := range d.KeyDescriptors {
+		if (k.Use == "signing" || k.Use == "") && len(k.Certificates) > 0 {
+			b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(k.Certificates[0]), ""))
+			if err != nil {
+				return nil, fmt.Errorf("invalid SAML metadata: invalid signing certificate: %w", err)
+			}
+			if _, err := x509.ParseCertificate(b); err != nil {
+				return nil, fmt.Errorf("invalid SAML metadata: invalid signing certificate: %w", err)
+			}
+			m.SigningCert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b}))
+			break
+		}
+	}
+	if m.SigningCert == "" {
+		return nil, fmt.Errorf("invalid SAML metadata: no signing certificate found")
+	}
+
+	return m, nil
+}
+
+func samlEndpointWithBinding(endpoints []samlEndpoint, binding string) (samlEndpoint, bool) {
+	for _, e := range endpoints {
+		if e.Binding == binding && e.Location != "" {
+			return e, true
+		}
+	}
+	return samlEndpoint{}, false
+}
+
+// validateSAMLMetadata is a schema.SchemaValidateFunc checking that a value is
+// SAML metadata describing an identity provider.
+func validateSAMLMetadata(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseSAMLMetadata(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
+
+// parseSigningCert reads an X.509 certificate in any of the encodings accepted
+// by Auth0: PEM, or PEM and DER (CER) encoded in base64.
+func parseSigningCert(cert string) (*x509.Certificate, error) {
+	b := []byte(strings.TrimSpace(cert))
+	if p, _ := pem.Decode(b); p != nil {
+		return x509.ParseCertificate(p.Bytes)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(cert), ""))
+	if err != nil {
+		return nil, fmt.Errorf("certificate is neither PEM nor base64 encoded: %w", err)
+	}
+	if p, _ := pem.Decode(b); p != nil {
+		return x509.ParseCertificate(p.Bytes)
+	}
+	return x509.ParseCertificate(b)
+}
+
+// flattenSigningCert returns the expiry date, the fingerprint and the subject
+// of a signing certificate. They are empty when the certificate can't be read.
+func flattenSigningCert(cert string) map[string]interface{} {
+	m := map[string]interface{}{
+		"signing_cert_expires_at":  "",
+		"signing_cert_fingerprint": "",
+		"signing_cert_subject":     "",
+	}
+	if cert == "" {
+		return m
+	}
+	c, err := parseSigningCert(cert)
+	if err != nil {
+		return m
+	}
+	fingerprint := sha1.Sum(c.Raw)
+	m["signing_cert_expires_at"] = c.NotAfter.UTC().Format(time.RFC3339)
+	m["signing_cert_fingerprint"] = hex.EncodeToString(fingerprint[:])
+	m["signing_cert_subject"] = c.Subject.String()
+	return m
+}
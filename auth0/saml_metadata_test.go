@@ -0,0 +1,153 @@
+package auth0
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSAMLMetadata(t *testing.T, name string) string {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "saml", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestParseSAMLMetadata(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		file     string
+		expected samlMetadata
+		cert     map[string]interface{}
+	}{
+		{
+			name: "Okta",
+			file: "okta.xml",
+			expected: samlMetadata{
+				EntityID:        "http://www.okta.com/exk1a2b3c4d5e6f7g8h9",
+				SignInEndpoint:  "https://example.okta.com/app/example_app/exk1a2b3c4d5e6f7g8h9/sso/saml/redirect",
+				SignOutEndpoint: "https://example.okta.com/app/example_app/exk1a2b3c4d5e6f7g8h9/slo/saml",
+				ProtocolBinding: samlBindingHTTPRedirect,
+			},
+			cert: map[string]interface{}{
+				"signing_cert_expires_at":  "2036-10-16T15:08:29Z",
+				"signing_cert_fingerprint": "9f6d4215a1f5790b6884fd9de72ed9abd2dae520",
+				"signing_cert_subject":     "CN=idp.example.com,O=Example,C=US",
+			},
+		},
+		{
+			name: "ADFS",
+			file: "adfs.xml",
+			expected: samlMetadata{
+				EntityID:        "https://adfs.example.com/adfs/services/trust",
+				SignInEndpoint:  "https://adfs.example.com/adfs/ls/",
+				SignOutEndpoint: "https://adfs.example.com/adfs/ls/",
+				ProtocolBinding: samlBindingHTTPPost,
+			},
+			cert: map[string]interface{}{
+				"signing_cert_expires_at":  "2026-10-20T15:08:29Z",
+				"signing_cert_fingerprint": "a6478d1d44e87df3f5316fc14c317b46b888d078",
+				"signing_cert_subject":     "CN=ADFS Signing - adfs.example.com",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			md, err := parseSAMLMetadata(testSAMLMetadata(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(md.SigningCert, "-----BEGIN CERTIFICATE-----\n") {
+				t.Errorf("expected a PEM encoded signing certificate, got %q", md.SigningCert)
+			}
+			if cert := flattenSigningCert(md.SigningCert); !reflect.DeepEqual(cert, tt.cert) {
+				t.Errorf("expected signing certificate %v, got %v", tt.cert, cert)
+			}
+
+			md.SigningCert = ""
+			if !reflect.DeepEqual(*md, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, *md)
+			}
+		})
+	}
+}
+
+func TestParseSAMLMetadataInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		metadata string
+		expected string
+	}{
+		{
+			name:     "NotXML",
+			metadata: "{}",
+			expected: "invalid SAML metadata: EOF",
+		},
+		{
+			name:     "ServiceProvider",
+			metadata: testSAMLMetadata(t, "sp.xml"),
+			expected: "invalid SAML metadata: no IDPSSODescriptor found",
+		},
+		{
+			name: "NoSigningCert",
+			metadata: `<EntityDescriptor entityID="idp"><IDPSSODescriptor>
+				<KeyDescriptor use="encryption"><KeyInfo><X509Data><X509Certificate>MIID</X509Certificate></X509Data></KeyInfo></KeyDescriptor>
+				<SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/sso"/>
+			</IDPSSODescriptor></EntityDescriptor>`,
+			expected: "invalid SAML metadata: no signing certificate found",
+		},
+		{
+			name: "UnsupportedBinding",
+			metadata: `<EntityDescriptor entityID="idp"><IDPSSODescriptor>
+				<SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:SOAP" Location="https://idp.example.com/sso"/>
+			</IDPSSODescriptor></EntityDescriptor>`,
+			expected: "invalid SAML metadata: no SingleSignOnService found with a supported binding",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSAMLMetadata(tt.metadata)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseSigningCert(t *testing.T) {
+	md, err := parseSAMLMetadata(testSAMLMetadata(t, "okta.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := pem.Decode([]byte(md.SigningCert))
+
+	for _, tt := range []struct {
+		name string
+		cert string
+	}{
+		{"PEM", md.SigningCert},
+		{"Base64PEM", base64.StdEncoding.EncodeToString([]byte(md.SigningCert))},
+		{"Base64DER", base64.StdEncoding.EncodeToString(p.Bytes)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := parseSigningCert(tt.cert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := c.Subject.CommonName; s != "idp.example.com" {
+				t.Errorf("expected the certificate of idp.example.com, got %s", s)
+			}
+		})
+	}
+
+	if _, err := parseSigningCert("not a certificate"); err == nil {
+		t.Error("expected an error for an invalid certificate")
+	}
+	if cert := flattenSigningCert("not a certificate"); cert["signing_cert_fingerprint"] != "" {
+		t.Errorf("expected no fingerprint for an invalid certificate, got %v", cert)
+	}
+}
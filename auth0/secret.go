@@ -0,0 +1,65 @@
+package auth0
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// secretHasher hashes secrets before they are stored in state, with the key
+// set from the secrets_hmac_key argument when the provider is configured.
+// Secrets are stored as they are when the key is empty.
+//
+// Each provider has its own, which is part of its meta, and which is also
+// given to the resources holding secrets, as diff suppression functions have
+// no access to the meta.
+type secretHasher struct {
+	key []byte
+}
+
+// secretHashPrefix prefixes the hash of a secret stored in state.
+const secretHashPrefix = "hmac-sha256:"
+
+// enabled reports whether secrets are hashed.
+func (h *secretHasher) enabled() bool {
+	return h != nil && len(h.key) > 0
+}
+
+// hash returns the value of a secret to store in state. Secrets are hashed
+// with HMAC-SHA256 when a key is configured, and returned as they are
+// otherwise. Empty values and values which are already hashed are returned as
+// they are.
+func (h *secretHasher) hash(value string) string {
+	if !h.enabled() || value == "" || isSecretHash(value) {
+		return value
+	}
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(value))
+	return secretHashPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isSecretHash reports whether value is the hash of a secret, rather than the
+// secret itself.
+func isSecretHash(value string) bool {
+	return strings.HasPrefix(value, secretHashPrefix)
+}
+
+// suppressDiff suppresses the diff of a secret when the configured value
+// matches the hash stored in state.
+func (h *secretHasher) suppressDiff(k, old, new string, d *schema.ResourceData) bool {
+	return isSecretHash(old) && h.hash(new) == old
+}
+
+// Secret accesses the value of a secret the same way String does, except that
+// nil is returned when the value is the hash stored in state. This is the case
+// when the secret is unchanged, as its value is then not known.
+func Secret(d ResourceData, key string, conditions ...Condition) *string {
+	v := String(d, key, conditions...)
+	if v != nil && isSecretHash(*v) {
+		return nil
+	}
+	return v
+}
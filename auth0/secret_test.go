@@ -0,0 +1,131 @@
+package auth0
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
+)
+
+func TestHashSecret(t *testing.T) {
+	if v := (&secretHasher{}).hash("foo"); v != "foo" {
+		t.Errorf("expected secrets to be kept as is without a key, got %q", v)
+	}
+	var none *secretHasher
+	if v := none.hash("foo"); v != "foo" {
+		t.Errorf("expected secrets to be kept as is without a hasher, got %q", v)
+	}
+
+	hasher := &secretHasher{key: []byte("key")}
+
+	h := hasher.hash("foo")
+	if !isSecretHash(h) {
+		t.Errorf("expected a hash, got %q", h)
+	}
+	if hasher.hash("foo") != h {
+		t.Error("expected the hash to be stable")
+	}
+	if hasher.hash("bar") == h {
+		t.Error("expected different secrets to have different hashes")
+	}
+	if hasher.hash(h) != h {
+		t.Error("expected a hash not to be hashed again")
+	}
+	if hasher.hash("") != "" {
+		t.Error("expected an empty secret to be kept empty")
+	}
+
+	if !hasher.suppressDiff("secret", h, "foo", nil) {
+		t.Error("expected the diff to be suppressed when the hash matches")
+	}
+	if hasher.suppressDiff("secret", h, "bar", nil) {
+		t.Error("expected the diff not to be suppressed when the hash differs")
+	}
+	if hasher.suppressDiff("secret", "foo", "foo", nil) {
+		t.Error("expected the diff of plain values not to be suppressed")
+	}
+
+	if (&secretHasher{key: []byte("other")}).hash("foo") == h {
+		t.Error("expected the hash to depend on the key")
+	}
+}
+
+func TestSecret(t *testing.T) {
+	hasher := &secretHasher{key: []byte("key")}
+
+	d := MapData{"plain": "foo", "hashed": hasher.hash("foo")}
+	if v := Secret(d, "plain"); v == nil || *v != "foo" {
+		t.Errorf("expected the plain value, got %v", v)
+	}
+	if v := Secret(d, "hashed"); v != nil {
+		t.Errorf("expected nil for a hashed value, got %q", *v)
+	}
+	if v := Secret(d, "missing"); v != nil {
+		t.Errorf("expected nil for a missing value, got %q", *v)
+	}
+}
+
+func TestFlattenActionSecretHashes(t *testing.T) {
+	hasher := &secretHasher{key: []byte("key")}
+
+	secrets := []*management.ActionSecret{
+		{Name: auth0.String("b")},
+		{Name: auth0.String("c")},
+		{Name: auth0.String("a")},
+	}
+	prior := []interface{}{
+		map[string]interface{}{"name": "a", "value": "1"},
+		map[string]interface{}{"name": "removed", "value": "2"},
+		map[string]interface{}{"name": "b", "value": hasher.hash("3")},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"name": "a", "value": hasher.hash("1")},
+		map[string]interface{}{"name": "b", "value": hasher.hash("3")},
+		map[string]interface{}{"name": "c", "value": ""},
+	}
+	if actual := flattenActionSecretHashes(hasher, secrets, prior); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestDiffHookSecrets(t *testing.T) {
+	hasher := &secretHasher{key: []byte("key")}
+
+	old := map[string]interface{}{
+		"unchanged": hasher.hash("1"),
+		"changed":   hasher.hash("2"),
+		"removed":   hasher.hash("3"),
+		"external":  "",
+	}
+	new := map[string]interface{}{
+		"unchanged": hasher.hash("1"),
+		"changed":   "22",
+		"external":  "4",
+		"added":     "5",
+	}
+
+	created, updated, removed := diffHookSecrets(old, new)
+	if expected := (management.HookSecrets{"added": "5"}); !reflect.DeepEqual(created, expected) {
+		t.Errorf("expected created secrets %v, got %v", expected, created)
+	}
+	if expected := (management.HookSecrets{"changed": "22", "external": "4"}); !reflect.DeepEqual(updated, expected) {
+		t.Errorf("expected updated secrets %v, got %v", expected, updated)
+	}
+	if expected := []string{"removed"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected removed secrets %v, got %v", expected, removed)
+	}
+}
+
+func TestFlattenHookSecretHashes(t *testing.T) {
+	hasher := &secretHasher{key: []byte("key")}
+
+	secrets := management.HookSecrets{"a": "_VALUE_NOT_SHOWN_", "b": "_VALUE_NOT_SHOWN_"}
+	prior := map[string]interface{}{"a": "1", "removed": "2"}
+
+	expected := map[string]interface{}{"a": hasher.hash("1"), "b": ""}
+	if actual := flattenHookSecretHashes(hasher, secrets, prior); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,117 @@
+package auth0
+
+func flattenAttackProtectionBreachedPasswordDetection(bpd *attackProtectionBreachedPasswordDetection) []interface{} {
+	m := make(map[string]interface{})
+	if bpd != nil {
+		m["enabled"] = bpd.Enabled
+		m["shields"] = derefStrings(bpd.Shields)
+		m["admin_notification_frequency"] = derefStrings(bpd.AdminNotificationFrequency)
+		m["method"] = bpd.Method
+	}
+	return []interface{}{m}
+}
+
+func flattenAttackProtectionBruteForceProtection(bfp *attackProtectionBruteForceProtection) []interface{} {
+	m := make(map[string]interface{})
+	if bfp != nil {
+		m["enabled"] = bfp.Enabled
+		m["shields"] = derefStrings(bfp.Shields)
+		m["mode"] = bfp.Mode
+		m["max_attempts"] = bfp.MaxAttempts
+		m["allowlist"] = derefStrings(bfp.AllowList)
+	}
+	return []interface{}{m}
+}
+
+func flattenAttackProtectionSuspiciousIPThrottling(sit *attackProtectionSuspiciousIPThrottling) []interface{} {
+	m := make(map[string]interface{})
+	if sit != nil {
+		m["enabled"] = sit.Enabled
+		m["shields"] = derefStrings(sit.Shields)
+		m["allowlist"] = derefStrings(sit.AllowList)
+		m["pre_login"] = flattenAttackProtectionThrottlingStage(sit.Stage[attackProtectionStagePreLogin])
+		m["pre_user_registration"] = flattenAttackProtectionThrottlingStage(sit.Stage[attackProtectionStagePreUserRegistration])
+	}
+	return []interface{}{m}
+}
+
+func flattenAttackProtectionThrottlingStage(stage *attackProtectionThrottlingStage) []interface{} {
+	m := make(map[string]interface{})
+	if stage != nil {
+		m["max_attempts"] = stage.MaxAttempts
+		m["rate"] = stage.Rate
+	}
+	return []interface{}{m}
+}
+
+func expandAttackProtectionBreachedPasswordDetection(d ResourceData) (bpd *attackProtectionBreachedPasswordDetection) {
+	List(d, "breached_password_detection").Elem(func(d ResourceData) {
+		bpd = &attackProtectionBreachedPasswordDetection{
+			Enabled:                    Bool(d, "enabled"),
+			Shields:                    expandAttackProtectionStrings(d, "shields"),
+			AdminNotificationFrequency: expandAttackProtectionStrings(d, "admin_notification_frequency"),
+			Method:                     String(d, "method"),
+		}
+	})
+	return
+}
+
+func expandAttackProtectionBruteForceProtection(d ResourceData) (bfp *attackProtectionBruteForceProtection) {
+	List(d, "brute_force_protection").Elem(func(d ResourceData) {
+		bfp = &attackProtectionBruteForceProtection{
+			Enabled:     Bool(d, "enabled"),
+			Shields:     expandAttackProtectionStrings(d, "shields"),
+			AllowList:   expandAttackProtectionStrings(d, "allowlist"),
+			Mode:        String(d, "mode"),
+			MaxAttempts: Int(d, "max_attempts"),
+		}
+	})
+	return
+}
+
+func expandAttackProtectionSuspiciousIPThrottling(d ResourceData) (sit *attackProtectionSuspiciousIPThrottling) {
+	List(d, "suspicious_ip_throttling").Elem(func(d ResourceData) {
+		sit = &attackProtectionSuspiciousIPThrottling{
+			Enabled:   Bool(d, "enabled"),
+			Shields:   expandAttackProtectionStrings(d, "shields"),
+			AllowList: expandAttackProtectionStrings(d, "allowlist"),
+			Stage:     make(map[string]*attackProtectionThrottlingStage),
+		}
+		List(d, "pre_login").Elem(func(d ResourceData) {
+			sit.Stage[attackProtectionStagePreLogin] = expandAttackProtectionThrottlingStage(d)
+		})
+		List(d, "pre_user_registration").Elem(func(d ResourceData) {
+			sit.Stage[attackProtectionStagePreUserRegistration] = expandAttackProtectionThrottlingStage(d)
+		})
+	})
+	return
+}
+
+func expandAttackProtectionThrottlingStage(d ResourceData) *attackProtectionThrottlingStage {
+	return &attackProtectionThrottlingStage{
+		MaxAttempts: Int(d, "max_attempts"),
+		Rate:        Int(d, "rate"),
+	}
+}
+
+// expandAttackProtectionStrings accesses the set of strings held by key. If
+// the set was emptied, a pointer to an empty slice is returned so that it is
+// sent to the API as [], which allows clearing shields or allow lists.
+func expandAttackProtectionStrings(d ResourceData, key string) *[]string {
+	if _, ok := d.GetOk(key); !ok && !d.HasChange(key) {
+		return nil
+	}
+	l := Set(d, key).List()
+	s := make([]string, 0, len(l))
+	for _, v := range l {
+		s = append(s, v.(string))
+	}
+	return &s
+}
+
+func derefStrings(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
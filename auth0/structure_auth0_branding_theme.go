@@ -0,0 +1,184 @@
+package auth0
+
+import (
+	"gopkg.in/auth0.v5"
+)
+
+func flattenBrandingThemeBorders(b *brandingThemeBorders) []interface{} {
+	m := make(map[string]interface{})
+	if b != nil {
+		m["button_border_radius"] = b.ButtonBorderRadius
+		m["button_border_weight"] = b.ButtonBorderWeight
+		m["buttons_style"] = b.ButtonsStyle
+		m["input_border_radius"] = b.InputBorderRadius
+		m["input_border_weight"] = b.InputBorderWeight
+		m["inputs_style"] = b.InputsStyle
+		m["show_widget_shadow"] = b.ShowWidgetShadow
+		m["widget_border_weight"] = b.WidgetBorderWeight
+		m["widget_corner_radius"] = b.WidgetCornerRadius
+	}
+	return []interface{}{m}
+}
+
+func flattenBrandingThemeColors(c *brandingThemeColors) []interface{} {
+	m := make(map[string]interface{})
+	if c != nil {
+		m["base_focus_color"] = c.BaseFocusColor
+		m["base_hover_color"] = c.BaseHoverColor
+		m["body_text"] = c.BodyText
+		m["error"] = c.Error
+		m["header"] = c.Header
+		m["icons"] = c.Icons
+		m["input_background"] = c.InputBackground
+		m["input_border"] = c.InputBorder
+		m["input_filled_text"] = c.InputFilledText
+		m["input_labels_placeholders"] = c.InputLabelsPlaceholders
+		m["links_focused_components"] = c.LinksFocusedComponents
+		m["primary_button"] = c.PrimaryButton
+		m["primary_button_label"] = c.PrimaryButtonLabel
+		m["secondary_button_border"] = c.SecondaryButtonBorder
+		m["secondary_button_label"] = c.SecondaryButtonLabel
+		m["success"] = c.Success
+		m["widget_background"] = c.WidgetBackground
+		m["widget_border"] = c.WidgetBorder
+	}
+	return []interface{}{m}
+}
+
+func flattenBrandingThemeFonts(f *brandingThemeFonts) []interface{} {
+	m := make(map[string]interface{})
+	if f != nil {
+		m["font_url"] = f.FontURL
+		m["links_style"] = f.LinksStyle
+		m["reference_text_size"] = f.ReferenceTextSize
+		m["body_text"] = flattenBrandingThemeText(f.BodyText)
+		m["buttons_text"] = flattenBrandingThemeText(f.ButtonsText)
+		m["input_labels"] = flattenBrandingThemeText(f.InputLabels)
+		m["links"] = flattenBrandingThemeText(f.Links)
+		m["subtitle"] = flattenBrandingThemeText(f.Subtitle)
+		m["title"] = flattenBrandingThemeText(f.Title)
+	}
+	return []interface{}{m}
+}
+
+func flattenBrandingThemeText(t *brandingThemeText) []interface{} {
+	m := make(map[string]interface{})
+	if t != nil {
+		m["bold"] = t.Bold
+		m["size"] = t.Size
+	}
+	return []interface{}{m}
+}
+
+func flattenBrandingThemePageBackground(p *brandingThemePageBackground) []interface{} {
+	m := make(map[string]interface{})
+	if p != nil {
+		m["background_color"] = p.BackgroundColor
+		m["background_image_url"] = p.BackgroundImageURL
+		m["page_layout"] = p.PageLayout
+	}
+	return []interface{}{m}
+}
+
+func flattenBrandingThemeWidget(w *brandingThemeWidget) []interface{} {
+	m := make(map[string]interface{})
+	if w != nil {
+		m["header_text_alignment"] = w.HeaderTextAlignment
+		m["logo_height"] = w.LogoHeight
+		m["logo_position"] = w.LogoPosition
+		m["logo_url"] = w.LogoURL
+		m["social_buttons_layout"] = w.SocialButtonsLayout
+	}
+	return []interface{}{m}
+}
+
+// expandBrandingTheme builds the complete theme from the configuration. Every
+// field has a default value, and the Management API expects all of them to be
+// present, so values are read with Get rather than the String, Float64 and
+// Bool accessors which would omit zero values such as a border weight of 0.
+func expandBrandingTheme(d ResourceData) *brandingTheme {
+	t := &brandingTheme{
+		DisplayName: String(d, "display_name"),
+	}
+
+	List(d, "borders").Elem(func(d ResourceData) {
+		t.Borders = &brandingThemeBorders{
+			ButtonBorderRadius: auth0.Float64(d.Get("button_border_radius").(float64)),
+			ButtonBorderWeight: auth0.Float64(d.Get("button_border_weight").(float64)),
+			ButtonsStyle:       auth0.String(d.Get("buttons_style").(string)),
+			InputBorderRadius:  auth0.Float64(d.Get("input_border_radius").(float64)),
+			InputBorderWeight:  auth0.Float64(d.Get("input_border_weight").(float64)),
+			InputsStyle:        auth0.String(d.Get("inputs_style").(string)),
+			ShowWidgetShadow:   auth0.Bool(d.Get("show_widget_shadow").(bool)),
+			WidgetBorderWeight: auth0.Float64(d.Get("widget_border_weight").(float64)),
+			WidgetCornerRadius: auth0.Float64(d.Get("widget_corner_radius").(float64)),
+		}
+	})
+
+	List(d, "colors").Elem(func(d ResourceData) {
+		t.Colors = &brandingThemeColors{
+			BaseFocusColor:          auth0.String(d.Get("base_focus_color").(string)),
+			BaseHoverColor:          auth0.String(d.Get("base_hover_color").(string)),
+			BodyText:                auth0.String(d.Get("body_text").(string)),
+			Error:                   auth0.String(d.Get("error").(string)),
+			Header:                  auth0.String(d.Get("header").(string)),
+			Icons:                   auth0.String(d.Get("icons").(string)),
+			InputBackground:         auth0.String(d.Get("input_background").(string)),
+			InputBorder:             auth0.String(d.Get("input_border").(string)),
+			InputFilledText:         auth0.String(d.Get("input_filled_text").(string)),
+			InputLabelsPlaceholders: auth0.String(d.Get("input_labels_placeholders").(string)),
+			LinksFocusedComponents:  auth0.String(d.Get("links_focused_components").(string)),
+			PrimaryButton:           auth0.String(d.Get("primary_button").(string)),
+			PrimaryButtonLabel:      auth0.String(d.Get("primary_button_label").(string)),
+			SecondaryButtonBorder:   auth0.String(d.Get("secondary_button_border").(string)),
+			SecondaryButtonLabel:    auth0.String(d.Get("secondary_button_label").(string)),
+			Success:                 auth0.String(d.Get("success").(string)),
+			WidgetBackground:        auth0.String(d.Get("widget_background").(string)),
+			WidgetBorder:            auth0.String(d.Get("widget_border").(string)),
+		}
+	})
+
+	List(d, "fonts").Elem(func(d ResourceData) {
+		t.Fonts = &brandingThemeFonts{
+			FontURL:           auth0.String(d.Get("font_url").(string)),
+			LinksStyle:        auth0.String(d.Get("links_style").(string)),
+			ReferenceTextSize: auth0.Float64(d.Get("reference_text_size").(float64)),
+			BodyText:          expandBrandingThemeText(d, "body_text"),
+			ButtonsText:       expandBrandingThemeText(d, "buttons_text"),
+			InputLabels:       expandBrandingThemeText(d, "input_labels"),
+			Links:             expandBrandingThemeText(d, "links"),
+			Subtitle:          expandBrandingThemeText(d, "subtitle"),
+			Title:             expandBrandingThemeText(d, "title"),
+		}
+	})
+
+	List(d, "page_background").Elem(func(d ResourceData) {
+		t.PageBackground = &brandingThemePageBackground{
+			BackgroundColor:    auth0.String(d.Get("background_color").(string)),
+			BackgroundImageURL: auth0.String(d.Get("background_image_url").(string)),
+			PageLayout:         auth0.String(d.Get("page_layout").(string)),
+		}
+	})
+
+	List(d, "widget").Elem(func(d ResourceData) {
+		t.Widget = &brandingThemeWidget{
+			HeaderTextAlignment: auth0.String(d.Get("header_text_alignment").(string)),
+			LogoHeight:          auth0.Float64(d.Get("logo_height").(float64)),
+			LogoPosition:        auth0.String(d.Get("logo_position").(string)),
+			LogoURL:             auth0.String(d.Get("logo_url").(string)),
+			SocialButtonsLayout: auth0.String(d.Get("social_buttons_layout").(string)),
+		}
+	})
+
+	return t
+}
+
+func expandBrandingThemeText(d ResourceData, key string) (t *brandingThemeText) {
+	List(d, key).Elem(func(d ResourceData) {
+		t = &brandingThemeText{
+			Bold: auth0.Bool(d.Get("bold").(bool)),
+			Size: auth0.Float64(d.Get("size").(float64)),
+		}
+	})
+	return
+}
@@ -1,13 +1,22 @@
 package auth0
 
 import (
-	"log"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 
 	"gopkg.in/auth0.v5"
 	"gopkg.in/auth0.v5/management"
 )
 
-func flattenConnectionOptions(d ResourceData, options interface{}) []interface{} {
+func flattenConnectionOptions(d ResourceData, options interface{}, secrets *secretHasher) []interface{} {
 
 	var m interface{}
 
@@ -35,7 +44,7 @@ func flattenConnectionOptions(d ResourceData, options interface{}) []interface{}
 	case *management.ConnectionOptionsEmail:
 		m = flattenConnectionOptionsEmail(o)
 	case *management.ConnectionOptionsSMS:
-		m = flattenConnectionOptionsSMS(o)
+		m = flattenConnectionOptionsSMS(o, secrets)
 	case *management.ConnectionOptionsOIDC:
 		m = flattenConnectionOptionsOIDC(o)
 	case *management.ConnectionOptionsAD:
@@ -45,7 +54,15 @@ func flattenConnectionOptions(d ResourceData, options interface{}) []interface{}
 	case *management.ConnectionOptionsADFS:
 		m = flattenConnectionOptionsADFS(o)
 	case *management.ConnectionOptionsSAML:
-		m = flattenConnectionOptionsSAML(o)
+		m = flattenConnectionOptionsSAML(d, o)
+	case *connectionOptionsOkta:
+		m = flattenConnectionOptionsOkta(o)
+	case *connectionOptionsPingFederate:
+		m = flattenConnectionOptionsPingFederate(o)
+	case *connectionOptionsOAuth1:
+		m = flattenConnectionOptionsOAuth1(o)
+	case *connectionOptionsTwitter:
+		m = flattenConnectionOptionsTwitter(o)
 	}
 
 	return []interface{}{m}
@@ -73,7 +90,7 @@ func flattenConnectionOptionsWindowsLive(o *management.ConnectionOptionsWindowsL
 }
 
 func flattenConnectionOptionsAuth0(d ResourceData, o *management.ConnectionOptions) interface{} {
-	return map[string]interface{}{
+	m := map[string]interface{}{
 		"validation":                     o.Validation,
 		"password_policy":                o.GetPasswordPolicy(),
 		"password_history":               o.PasswordHistory,
@@ -86,10 +103,20 @@ func flattenConnectionOptionsAuth0(d ResourceData, o *management.ConnectionOptio
 		"disable_signup":                 o.GetDisableSignup(),
 		"requires_username":              o.GetRequiresUsername(),
 		"custom_scripts":                 o.CustomScripts,
+		"custom_scripts_directory":       "",
 		"mfa":                            o.MFA,
 		"configuration":                  Map(d, "configuration"), // does not get read back
+		"set_user_root_attributes":       o.GetSetUserAttributes(),
 		"non_persistent_attrs":           o.GetNonPersistentAttrs(),
 	}
+
+	// Scripts read from a directory are tracked by custom_scripts_hashes
+	// instead, as they are not configured.
+	if dir, _ := d.Get("auth0_options.0.custom_scripts_directory").(string); dir != "" {
+		m["custom_scripts"] = nil
+		m["custom_scripts_directory"] = dir
+	}
+	return m
 }
 
 func flattenConnectionOptionsGoogleOAuth2(o *management.ConnectionOptionsGoogleOAuth2) interface{} {
@@ -175,14 +202,14 @@ func flattenConnectionOptionsSalesforce(o *management.ConnectionOptionsSalesforc
 	}
 }
 
-func flattenConnectionOptionsSMS(o *management.ConnectionOptionsSMS) interface{} {
+func flattenConnectionOptionsSMS(o *management.ConnectionOptionsSMS, secrets *secretHasher) interface{} {
 	return map[string]interface{}{
 		"name":                   o.GetName(),
 		"from":                   o.GetFrom(),
 		"syntax":                 o.GetSyntax(),
 		"template":               o.GetTemplate(),
 		"twilio_sid":             o.GetTwilioSID(),
-		"twilio_token":           o.GetTwilioToken(),
+		"twilio_token":           secrets.hash(o.GetTwilioToken()),
 		"messaging_service_sid":  o.GetMessagingServiceSID(),
 		"disable_signup":         o.GetDisableSignup(),
 		"brute_force_protection": o.GetBruteForceProtection(),
@@ -290,8 +317,8 @@ func flattenConnectionOptionsADFS(o *management.ConnectionOptionsADFS) interface
 	}
 }
 
-func flattenConnectionOptionsSAML(o *management.ConnectionOptionsSAML) interface{} {
-	return map[string]interface{}{
+func flattenConnectionOptionsSAML(d ResourceData, o *management.ConnectionOptionsSAML) interface{} {
+	m := map[string]interface{}{
 		"signing_cert":     o.GetSigningCert(),
 		"protocol_binding": o.GetProtocolBinding(),
 		"debug":            o.GetDebug(),
@@ -315,22 +342,267 @@ func flattenConnectionOptionsSAML(o *management.ConnectionOptionsSAML) interface
 		"non_persistent_attrs":     o.GetNonPersistentAttrs(),
 		"entity_id":                o.GetEntityID(),
 	}
+	for k, v := range flattenSigningCert(o.GetSigningCert()) {
+		m[k] = v
+	}
+
+	// The options read from the metadata are only kept when they are
+	// configured, so that they don't conflict with it. The metadata itself is
+	// not read back.
+	metadata, _ := d.Get("saml_options.0.metadata_xml").(string)
+	m["metadata_xml"] = metadata
+	if metadata != "" {
+		for _, k := range []string{"sign_in_endpoint", "sign_out_endpoint", "signing_cert", "protocol_binding"} {
+			if v, _ := d.Get("saml_options.0." + k).(string); v == "" {
+				m[k] = ""
+			}
+		}
+	}
+	return m
+}
+
+func flattenConnectionOptionsOkta(o *connectionOptionsOkta) interface{} {
+	m := map[string]interface{}{
+		"client_id":      auth0.StringValue(o.ClientID),
+		"client_secret":  auth0.StringValue(o.ClientSecret),
+		"domain":         auth0.StringValue(o.Domain),
+		"domain_aliases": o.DomainAliases,
+		"icon_url":       auth0.StringValue(o.LogoURL),
+
+		"type":                     auth0.StringValue(o.Type),
+		"scopes":                   o.Scopes(),
+		"issuer":                   auth0.StringValue(o.Issuer),
+		"jwks_uri":                 auth0.StringValue(o.JWKSURI),
+		"token_endpoint":           auth0.StringValue(o.TokenEndpoint),
+		"userinfo_endpoint":        auth0.StringValue(o.UserInfoEndpoint),
+		"authorization_endpoint":   auth0.StringValue(o.AuthorizationEndpoint),
+		"upstream_params":          string(o.UpstreamParams),
+		"set_user_root_attributes": auth0.StringValue(o.SetUserAttributes),
+		"non_persistent_attrs":     stringSliceValue(o.NonPersistentAttrs),
+	}
+	if a := o.AttributeMap; a != nil {
+		m["attribute_map"] = []interface{}{
+			map[string]interface{}{
+				"mapping_mode":   auth0.StringValue(a.MappingMode),
+				"userinfo_scope": auth0.StringValue(a.UserInfoScope),
+				"attributes":     string(a.Attributes),
+			},
+		}
+	}
+	return m
+}
+
+func flattenConnectionOptionsPingFederate(o *connectionOptionsPingFederate) interface{} {
+	return map[string]interface{}{
+		"ping_federate_base_url":   auth0.StringValue(o.PingFederateBaseURL),
+		"tenant_domain":            auth0.StringValue(o.TenantDomain),
+		"domain_aliases":           o.DomainAliases,
+		"icon_url":                 auth0.StringValue(o.LogoURL),
+		"signing_cert":             auth0.StringValue(o.SigningCert),
+		"sign_saml_request":        auth0.BoolValue(o.SignSAMLRequest),
+		"signature_algorithm":      auth0.StringValue(o.SignatureAlgorithm),
+		"digest_algorithm":         auth0.StringValue(o.DigestAlgorithm),
+		"fields_map":               o.FieldsMap,
+		"set_user_root_attributes": auth0.StringValue(o.SetUserAttributes),
+		"non_persistent_attrs":     stringSliceValue(o.NonPersistentAttrs),
+	}
 }
 
-func expandConnection(d ResourceData) *management.Connection {
+func flattenConnectionOptionsOAuth1(o *connectionOptionsOAuth1) interface{} {
+	return map[string]interface{}{
+		"client_id":                auth0.StringValue(o.ClientID),
+		"client_secret":            auth0.StringValue(o.ClientSecret),
+		"request_token_endpoint":   auth0.StringValue(o.RequestTokenURL),
+		"access_token_endpoint":    auth0.StringValue(o.AccessTokenURL),
+		"authorization_endpoint":   auth0.StringValue(o.UserAuthorizationURL),
+		"signature_method":         auth0.StringValue(o.SignatureMethod),
+		"scripts":                  o.Scripts,
+		"upstream_params":          string(o.UpstreamParams),
+		"set_user_root_attributes": auth0.StringValue(o.SetUserAttributes),
+		"non_persistent_attrs":     stringSliceValue(o.NonPersistentAttrs),
+	}
+}
 
-	c := &management.Connection{
+func flattenConnectionOptionsTwitter(o *connectionOptionsTwitter) interface{} {
+	return map[string]interface{}{
+		"client_id":                auth0.StringValue(o.ClientID),
+		"client_secret":            auth0.StringValue(o.ClientSecret),
+		"upstream_params":          string(o.UpstreamParams),
+		"set_user_root_attributes": auth0.StringValue(o.SetUserAttributes),
+		"non_persistent_attrs":     stringSliceValue(o.NonPersistentAttrs),
+	}
+}
+
+// flattenConnectionOptionsJSON flattens the options of a connection read as
+// JSON. When prior is a JSON object, only its keys are kept, so that options
+// defaulted by the API don't show up as changes.
+func flattenConnectionOptionsJSON(options, prior string) (string, error) {
+	var o map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(options), &o); err != nil {
+		return "", err
+	}
+
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(prior), &p); err == nil && p != nil {
+		for k := range o {
+			if _, ok := p[k]; !ok {
+				delete(o, k)
+			}
+		}
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// connectionOptionsBlocks are the blocks holding the options of each strategy
+// with typed options. The options of other strategies can only be configured
+// with options_json.
+var connectionOptionsBlocks = map[string]string{
+	management.ConnectionStrategyAuth0:               "auth0_options",
+	management.ConnectionStrategyGoogleOAuth2:        "google_oauth2_options",
+	management.ConnectionStrategyGoogleApps:          "google_apps_options",
+	management.ConnectionStrategyOAuth2:              "oauth2_options",
+	management.ConnectionStrategyFacebook:            "facebook_options",
+	management.ConnectionStrategyApple:               "apple_options",
+	management.ConnectionStrategyLinkedin:            "linkedin_options",
+	management.ConnectionStrategyGitHub:              "github_options",
+	management.ConnectionStrategyWindowsLive:         "windowslive_options",
+	management.ConnectionStrategySalesforce:          "salesforce_options",
+	management.ConnectionStrategySalesforceCommunity: "salesforce_options",
+	management.ConnectionStrategySalesforceSandbox:   "salesforce_options",
+	management.ConnectionStrategySMS:                 "sms_options",
+	management.ConnectionStrategyOIDC:                "oidc_options",
+	management.ConnectionStrategyAD:                  "ad_options",
+	management.ConnectionStrategyAzureAD:             "waad_options",
+	management.ConnectionStrategyEmail:               "email_options",
+	management.ConnectionStrategySAML:                "saml_options",
+	management.ConnectionStrategyADFS:                "adfs_options",
+	connectionStrategyOkta:                           "okta_options",
+	connectionStrategyPingFederate:                   "pingfederate_options",
+	connectionStrategyOAuth1:                         "oauth1_options",
+	connectionStrategyTwitter:                        "twitter_options",
+}
+
+// connectionStrategiesEnterprise are the strategies of enterprise connections,
+// which are found through home realm discovery by their domain aliases, and
+// can be shown as a button on the login page.
+var connectionStrategiesEnterprise = map[string]bool{
+	management.ConnectionStrategyAD:         true,
+	management.ConnectionStrategyADFS:       true,
+	management.ConnectionStrategyAzureAD:    true,
+	management.ConnectionStrategyGoogleApps: true,
+	management.ConnectionStrategyOIDC:       true,
+	management.ConnectionStrategySAML:       true,
+	connectionStrategyOkta:                  true,
+	connectionStrategyPingFederate:          true,
+}
+
+// connectionOptionsBlockNames returns the names of the options blocks, sorted.
+func connectionOptionsBlockNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, block := range connectionOptionsBlocks {
+		if !seen[block] {
+			seen[block] = true
+			names = append(names, block)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// connectionOptionsKeys are the option keys consumed by each strategy with
+// typed options. They are recorded while expanding empty options, so that they
+// are always those read by the expandConnectionOptions* functions.
+var connectionOptionsKeys = func() map[string]map[string]bool {
+	keys := make(map[string]map[string]bool)
+	for strategy, block := range connectionOptionsBlocks {
+		r := connectionOptionsKeyRecorder{
+			MapData: MapData{
+				"strategy":          strategy,
+				block:               []interface{}{map[string]interface{}{}},
+				block + ".0.scopes": schema.NewSet(schema.HashString, nil),
+			},
+			prefix: block + ".0.",
+			keys:   make(map[string]bool),
+		}
+		expandConnection(r)
+		keys[strategy] = r.keys
+	}
+	return keys
+}()
+
+// connectionOptionsKeyRecorder is a ResourceData recording the option keys
+// which are read from it.
+type connectionOptionsKeyRecorder struct {
+	MapData
+	prefix string
+	keys   map[string]bool
+}
+
+func (r connectionOptionsKeyRecorder) record(key string) {
+	if k := strings.TrimPrefix(key, r.prefix); k != key {
+		r.keys[strings.SplitN(k, ".", 2)[0]] = true
+	}
+}
+
+func (r connectionOptionsKeyRecorder) HasChange(key string) bool {
+	r.record(key)
+	return r.MapData.HasChange(key)
+}
+
+func (r connectionOptionsKeyRecorder) GetChange(key string) (interface{}, interface{}) {
+	r.record(key)
+	return r.MapData.GetChange(key)
+}
+
+func (r connectionOptionsKeyRecorder) Get(key string) interface{} {
+	r.record(key)
+	return r.MapData.Get(key)
+}
+
+func (r connectionOptionsKeyRecorder) GetOk(key string) (interface{}, bool) {
+	r.record(key)
+	return r.MapData.GetOk(key)
+}
+
+func (r connectionOptionsKeyRecorder) GetOkExists(key string) (interface{}, bool) {
+	r.record(key)
+	return r.MapData.GetOkExists(key)
+}
+
+func expandConnection(d ResourceData) *connection {
+
+	c := &connection{Connection: &management.Connection{
 		Name:               String(d, "name", IsNewResource()),
 		DisplayName:        String(d, "display_name"),
 		Strategy:           String(d, "strategy", IsNewResource()),
 		IsDomainConnection: Bool(d, "is_domain_connection"),
-		EnabledClients:     Set(d, "enabled_clients").List(),
 		Realms:             Slice(d, "realms", IsNewResource(), HasChange()),
+	}}
+
+	// Clients which aren't authoritative are enabled one at a time once the
+	// connection is written, see updateManagedEnabledClients.
+	if authoritative, ok := d.Get("enabled_clients_authoritative").(bool); authoritative || !ok {
+		c.EnabledClients = Set(d, "enabled_clients", IsNewResource(), HasChange()).List()
+	}
+
+	if v, ok := d.GetOk("options_json"); ok {
+		c.Options = json.RawMessage(v.(string))
+		return c
 	}
 
 	s := d.Get("strategy").(string)
+	block, ok := connectionOptionsBlocks[s]
+	if !ok {
+		return c
+	}
 
-	List(d, "options").Elem(func(d ResourceData) {
+	List(d, block).Elem(func(d ResourceData) {
 		switch s {
 		case management.ConnectionStrategyAuth0:
 			c.Options = expandConnectionOptionsAuth0(d)
@@ -368,10 +640,21 @@ func expandConnection(d ResourceData) *management.Connection {
 			c.Options = expandConnectionOptionsSAML(d)
 		case management.ConnectionStrategyADFS:
 			c.Options = expandConnectionOptionsADFS(d)
-		default:
-			log.Printf("[WARN]: Unsupported connection strategy %s", s)
-			log.Printf("[WARN]: Raise an issue with the auth0 provider in order to support it:")
-			log.Printf("[WARN]: 	https://github.com/alexkappa/terraform-provider-auth0/issues/new")
+		case connectionStrategyOkta:
+			c.Options = expandConnectionOptionsOkta(d)
+		case connectionStrategyPingFederate:
+			c.Options = expandConnectionOptionsPingFederate(d)
+		case connectionStrategyOAuth1:
+			c.Options = expandConnectionOptionsOAuth1(d)
+		case connectionStrategyTwitter:
+			c.Options = expandConnectionOptionsTwitter(d)
+		}
+
+		// show_as_button is sent along with the connection rather than its
+		// options, but is configured with them as it only applies to
+		// enterprise connections.
+		if connectionStrategiesEnterprise[s] {
+			c.ShowAsButton = Bool(d, "show_as_button")
 		}
 	})
 
@@ -396,6 +679,7 @@ func expandConnectionOptionsAuth0(d ResourceData) *management.ConnectionOptions
 
 	o := &management.ConnectionOptions{
 		PasswordPolicy:     String(d, "password_policy"),
+		SetUserAttributes:  String(d, "set_user_root_attributes"),
 		NonPersistentAttrs: castToListOfStrings(Set(d, "non_persistent_attrs").List()),
 	}
 
@@ -445,9 +729,95 @@ func expandConnectionOptionsAuth0(d ResourceData) *management.ConnectionOptions
 	o.CustomScripts = Map(d, "custom_scripts")
 	o.Configuration = Map(d, "configuration")
 
+	// The directory is read when planning, so failing to read it here is
+	// unlikely. The scripts are then left as they are.
+	if dir := String(d, "custom_scripts_directory"); dir != nil {
+		if scripts, err := readConnectionCustomScripts(*dir); err == nil {
+			o.CustomScripts = scripts
+		}
+	}
+
 	return o
 }
 
+// connectionCustomScripts are the names of the custom database action scripts.
+var connectionCustomScripts = []string{
+	"login",
+	"create",
+	"verify",
+	"change_password",
+	"delete",
+	"get_user",
+	"change_email",
+}
+
+func isConnectionCustomScript(name string) bool {
+	for _, script := range connectionCustomScripts {
+		if name == script {
+			return true
+		}
+	}
+	return false
+}
+
+func unsupportedConnectionCustomScript(name string) error {
+	return fmt.Errorf("unsupported custom script %q, expected one of %s",
+		name, strings.Join(connectionCustomScripts, ", "))
+}
+
+// validateConnectionCustomScripts is a schema.SchemaValidateFunc checking the
+// names of custom database scripts, as scripts with other names never run.
+func validateConnectionCustomScripts(i interface{}, k string) (warnings []string, errors []error) {
+	scripts, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be map", k)}
+	}
+	for name := range scripts {
+		if !isConnectionCustomScript(name) {
+			errors = append(errors, fmt.Errorf("%s: %w", k, unsupportedConnectionCustomScript(name)))
+		}
+	}
+	return nil, errors
+}
+
+// readConnectionCustomScripts reads the custom database scripts of a
+// directory, from the files named after them, such as login.js. Files without
+// the .js extension are ignored.
+func readConnectionCustomScripts(dir string) (map[string]interface{}, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom_scripts_directory: %w", err)
+	}
+	scripts := make(map[string]interface{})
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".js")
+		if f.IsDir() || name == f.Name() {
+			continue
+		}
+		if !isConnectionCustomScript(name) {
+			return nil, fmt.Errorf("custom_scripts_directory: %s: %w", f.Name(), unsupportedConnectionCustomScript(name))
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom_scripts_directory: %w", err)
+		}
+		scripts[name] = string(b)
+	}
+	return scripts, nil
+}
+
+// connectionCustomScriptsHashes returns the SHA-256 hashes of custom database
+// scripts, by name.
+func connectionCustomScriptsHashes(scripts map[string]interface{}) map[string]interface{} {
+	hashes := make(map[string]interface{}, len(scripts))
+	for name, script := range scripts {
+		s, _ := script.(string)
+		sum := sha256.Sum256([]byte(s))
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
+
 func expandConnectionOptionsGoogleOAuth2(d ResourceData) *management.ConnectionOptionsGoogleOAuth2 {
 
 	o := &management.ConnectionOptionsGoogleOAuth2{
@@ -582,7 +952,7 @@ func expandConnectionOptionsSMS(d ResourceData) *management.ConnectionOptionsSMS
 		Syntax:               String(d, "syntax"),
 		Template:             String(d, "template"),
 		TwilioSID:            String(d, "twilio_sid"),
-		TwilioToken:          String(d, "twilio_token"),
+		TwilioToken:          Secret(d, "twilio_token"),
 		MessagingServiceSID:  String(d, "messaging_service_sid"),
 		Provider:             String(d, "provider"),
 		GatewayUrl:           String(d, "gateway_url"),
@@ -732,6 +1102,26 @@ func expandConnectionOptionsSAML(d ResourceData) *management.ConnectionOptionsSA
 		SetUserAttributes:  String(d, "set_user_root_attributes"),
 		NonPersistentAttrs: castToListOfStrings(Set(d, "non_persistent_attrs").List()),
 		EntityID:           String(d, "entity_id"),
+		MetadataXML:        String(d, "metadata_xml"),
+	}
+
+	// The options which are not set are read from the metadata. It is
+	// validated when planning, so it can't fail to be read here.
+	if o.MetadataXML != nil {
+		if md, err := parseSAMLMetadata(o.GetMetadataXML()); err == nil {
+			if o.SignInEndpoint == nil {
+				o.SignInEndpoint = &md.SignInEndpoint
+			}
+			if o.SignOutEndpoint == nil && md.SignOutEndpoint != "" {
+				o.SignOutEndpoint = &md.SignOutEndpoint
+			}
+			if o.SigningCert == nil {
+				o.SigningCert = &md.SigningCert
+			}
+			if o.ProtocolBinding == nil {
+				o.ProtocolBinding = &md.ProtocolBinding
+			}
+		}
 	}
 
 	List(d, "idp_initiated").Elem(func(d ResourceData) {
@@ -748,7 +1138,7 @@ func expandConnectionOptionsSAML(d ResourceData) *management.ConnectionOptionsSA
 func expandConnectionOptionsADFS(d ResourceData) *management.ConnectionOptionsADFS {
 	return &management.ConnectionOptionsADFS{
 		TenantDomain:       String(d, "tenant_domain"),
-		DomainAliases:      Slice(d, "domain_aliases"),
+		DomainAliases:      Set(d, "domain_aliases").List(),
 		LogoURL:            String(d, "icon_url"),
 		ADFSServer:         String(d, "adfs_server"),
 		EnableUsersAPI:     Bool(d, "api_enable_users"),
@@ -757,6 +1147,88 @@ func expandConnectionOptionsADFS(d ResourceData) *management.ConnectionOptionsAD
 	}
 }
 
+func expandConnectionOptionsOkta(d ResourceData) *connectionOptionsOkta {
+
+	o := &connectionOptionsOkta{
+		ClientID:              String(d, "client_id"),
+		ClientSecret:          String(d, "client_secret"),
+		Domain:                String(d, "domain"),
+		DomainAliases:         Set(d, "domain_aliases").List(),
+		LogoURL:               String(d, "icon_url"),
+		AuthorizationEndpoint: String(d, "authorization_endpoint"),
+		Issuer:                String(d, "issuer"),
+		JWKSURI:               String(d, "jwks_uri"),
+		Type:                  String(d, "type"),
+		UserInfoEndpoint:      String(d, "userinfo_endpoint"),
+		TokenEndpoint:         String(d, "token_endpoint"),
+		UpstreamParams:        expandConnectionOptionsRawJSON(d, "upstream_params"),
+		SetUserAttributes:     String(d, "set_user_root_attributes"),
+		NonPersistentAttrs:    castToListOfStrings(Set(d, "non_persistent_attrs").List()),
+	}
+
+	List(d, "attribute_map").Elem(func(d ResourceData) {
+		o.AttributeMap = &connectionOptionsAttributeMap{
+			MappingMode:   String(d, "mapping_mode"),
+			UserInfoScope: String(d, "userinfo_scope"),
+			Attributes:    expandConnectionOptionsRawJSON(d, "attributes"),
+		}
+	})
+
+	expandConnectionOptionsScopes(d, o)
+
+	return o
+}
+
+func expandConnectionOptionsPingFederate(d ResourceData) *connectionOptionsPingFederate {
+	return &connectionOptionsPingFederate{
+		PingFederateBaseURL: String(d, "ping_federate_base_url"),
+		TenantDomain:        String(d, "tenant_domain"),
+		DomainAliases:       Set(d, "domain_aliases").List(),
+		LogoURL:             String(d, "icon_url"),
+		SigningCert:         String(d, "signing_cert"),
+		SignSAMLRequest:     Bool(d, "sign_saml_request"),
+		SignatureAlgorithm:  String(d, "signature_algorithm"),
+		DigestAlgorithm:     String(d, "digest_algorithm"),
+		FieldsMap:           Map(d, "fields_map"),
+		SetUserAttributes:   String(d, "set_user_root_attributes"),
+		NonPersistentAttrs:  castToListOfStrings(Set(d, "non_persistent_attrs").List()),
+	}
+}
+
+func expandConnectionOptionsOAuth1(d ResourceData) *connectionOptionsOAuth1 {
+	return &connectionOptionsOAuth1{
+		ClientID:             String(d, "client_id"),
+		ClientSecret:         String(d, "client_secret"),
+		RequestTokenURL:      String(d, "request_token_endpoint"),
+		AccessTokenURL:       String(d, "access_token_endpoint"),
+		UserAuthorizationURL: String(d, "authorization_endpoint"),
+		SignatureMethod:      String(d, "signature_method"),
+		Scripts:              Map(d, "scripts"),
+		UpstreamParams:       expandConnectionOptionsRawJSON(d, "upstream_params"),
+		SetUserAttributes:    String(d, "set_user_root_attributes"),
+		NonPersistentAttrs:   castToListOfStrings(Set(d, "non_persistent_attrs").List()),
+	}
+}
+
+func expandConnectionOptionsTwitter(d ResourceData) *connectionOptionsTwitter {
+	return &connectionOptionsTwitter{
+		ClientID:           String(d, "client_id"),
+		ClientSecret:       String(d, "client_secret"),
+		UpstreamParams:     expandConnectionOptionsRawJSON(d, "upstream_params"),
+		SetUserAttributes:  String(d, "set_user_root_attributes"),
+		NonPersistentAttrs: castToListOfStrings(Set(d, "non_persistent_attrs").List()),
+	}
+}
+
+// expandConnectionOptionsRawJSON returns the JSON held by key as it is, so
+// that it is sent without being decoded. The JSON is validated by the schema.
+func expandConnectionOptionsRawJSON(d ResourceData, key string) json.RawMessage {
+	if v := String(d, key); v != nil {
+		return json.RawMessage(*v)
+	}
+	return nil
+}
+
 type scoper interface {
 	Scopes() []string
 	SetScopes(enable bool, scopes ...string)
@@ -0,0 +1,215 @@
+package auth0
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"gopkg.in/auth0.v5"
+	"gopkg.in/auth0.v5/management"
+)
+
+// The options of the strategies below are not supported by the client, which
+// reads them as a map. They are defined here, and connectionOptionsTypes is
+// used to read them into their type.
+const (
+	connectionStrategyOkta         = "okta"
+	connectionStrategyPingFederate = "pingfederate"
+	connectionStrategyOAuth1       = "oauth1"
+	connectionStrategyTwitter      = "twitter"
+)
+
+// connectionOptionsTypes returns a new value of the options type of the
+// strategies which are not supported by the client. The client has a type for
+// the options of adfs connections, but reads them as a map as well.
+var connectionOptionsTypes = map[string]func() interface{}{
+	management.ConnectionStrategyADFS: func() interface{} { return &management.ConnectionOptionsADFS{} },
+	connectionStrategyOkta:            func() interface{} { return &connectionOptionsOkta{} },
+	connectionStrategyPingFederate:    func() interface{} { return &connectionOptionsPingFederate{} },
+	connectionStrategyOAuth1:          func() interface{} { return &connectionOptionsOAuth1{} },
+	connectionStrategyTwitter:         func() interface{} { return &connectionOptionsTwitter{} },
+}
+
+// unmarshalConnectionOptions reads the options of a connection into their
+// type, if its strategy is one which is not supported by the client.
+func unmarshalConnectionOptions(c *management.Connection) error {
+	newOptions, ok := connectionOptionsTypes[c.GetStrategy()]
+	if !ok || c.Options == nil {
+		return nil
+	}
+	b, err := json.Marshal(c.Options)
+	if err != nil {
+		return err
+	}
+	o := newOptions()
+	if err := json.Unmarshal(b, o); err != nil {
+		return err
+	}
+	c.Options = o
+	return nil
+}
+
+// connection is a management.Connection along with the fields which are not
+// supported by the client.
+type connection struct {
+	*management.Connection
+
+	// ShowAsButton shows the connection as a button on the login page, rather
+	// than only through home realm discovery. It applies to enterprise
+	// connections only.
+	ShowAsButton *bool `json:"show_as_button,omitempty"`
+
+	// RawOptions are the options as they are returned by the API, including
+	// those which are not known to the client. They are read from the
+	// response only.
+	RawOptions json.RawMessage `json:"-"`
+}
+
+func (c *connection) GetShowAsButton() bool {
+	return auth0.BoolValue(c.ShowAsButton)
+}
+
+func (c *connection) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(c.Connection)
+	if err != nil || c.ShowAsButton == nil {
+		return b, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	m["show_as_button"], _ = json.Marshal(c.ShowAsButton)
+	return json.Marshal(m)
+}
+
+func (c *connection) UnmarshalJSON(b []byte) error {
+	if c.Connection == nil {
+		c.Connection = &management.Connection{}
+	}
+	if err := json.Unmarshal(b, c.Connection); err != nil {
+		return err
+	}
+	var v struct {
+		ShowAsButton *bool           `json:"show_as_button,omitempty"`
+		Options      json.RawMessage `json:"options,omitempty"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	c.ShowAsButton = v.ShowAsButton
+	c.RawOptions = v.Options
+	return nil
+}
+
+type connectionOptionsOkta struct {
+	ClientID     *string `json:"client_id,omitempty"`
+	ClientSecret *string `json:"client_secret,omitempty"`
+
+	Domain        *string       `json:"domain,omitempty"`
+	DomainAliases []interface{} `json:"domain_aliases,omitempty"`
+	LogoURL       *string       `json:"icon_url,omitempty"`
+
+	AuthorizationEndpoint *string `json:"authorization_endpoint,omitempty"`
+	Issuer                *string `json:"issuer,omitempty"`
+	JWKSURI               *string `json:"jwks_uri,omitempty"`
+	Type                  *string `json:"type,omitempty"`
+	UserInfoEndpoint      *string `json:"userinfo_endpoint,omitempty"`
+	TokenEndpoint         *string `json:"token_endpoint,omitempty"`
+	Scope                 *string `json:"scope,omitempty"`
+
+	UpstreamParams json.RawMessage                `json:"upstream_params,omitempty"`
+	AttributeMap   *connectionOptionsAttributeMap `json:"attribute_map,omitempty"`
+
+	SetUserAttributes  *string   `json:"set_user_root_attributes,omitempty"`
+	NonPersistentAttrs *[]string `json:"non_persistent_attrs,omitempty"`
+}
+
+func (c *connectionOptionsOkta) Scopes() []string {
+	return strings.Fields(auth0.StringValue(c.Scope))
+}
+
+func (c *connectionOptionsOkta) SetScopes(enable bool, scopes ...string) {
+	scope := joinScopes(c.Scopes(), enable, scopes...)
+	c.Scope = &scope
+}
+
+// connectionOptionsAttributeMap maps the claims of the identity provider to
+// the attributes of the Auth0 user profile.
+type connectionOptionsAttributeMap struct {
+	MappingMode   *string         `json:"mapping_mode,omitempty"`
+	UserInfoScope *string         `json:"userinfo_scope,omitempty"`
+	Attributes    json.RawMessage `json:"attributes,omitempty"`
+}
+
+type connectionOptionsPingFederate struct {
+	PingFederateBaseURL *string `json:"pingFederateBaseUrl,omitempty"`
+
+	TenantDomain  *string       `json:"tenant_domain,omitempty"`
+	DomainAliases []interface{} `json:"domain_aliases,omitempty"`
+	LogoURL       *string       `json:"icon_url,omitempty"`
+
+	SigningCert        *string `json:"signingCert,omitempty"`
+	SignSAMLRequest    *bool   `json:"signSAMLRequest,omitempty"`
+	SignatureAlgorithm *string `json:"signatureAlgorithm,omitempty"`
+	DigestAlgorithm    *string `json:"digestAlgorithm,omitempty"`
+
+	FieldsMap map[string]interface{} `json:"fieldsMap,omitempty"`
+
+	SetUserAttributes  *string   `json:"set_user_root_attributes,omitempty"`
+	NonPersistentAttrs *[]string `json:"non_persistent_attrs,omitempty"`
+}
+
+type connectionOptionsOAuth1 struct {
+	ClientID     *string `json:"client_id,omitempty"`
+	ClientSecret *string `json:"client_secret,omitempty"`
+
+	RequestTokenURL      *string `json:"requestTokenURL,omitempty"`
+	AccessTokenURL       *string `json:"accessTokenURL,omitempty"`
+	UserAuthorizationURL *string `json:"userAuthorizationURL,omitempty"`
+	SignatureMethod      *string `json:"signatureMethod,omitempty"`
+
+	Scripts        map[string]interface{} `json:"scripts,omitempty"`
+	UpstreamParams json.RawMessage        `json:"upstream_params,omitempty"`
+
+	SetUserAttributes  *string   `json:"set_user_root_attributes,omitempty"`
+	NonPersistentAttrs *[]string `json:"non_persistent_attrs,omitempty"`
+}
+
+type connectionOptionsTwitter struct {
+	ClientID     *string `json:"client_id,omitempty"`
+	ClientSecret *string `json:"client_secret,omitempty"`
+
+	UpstreamParams json.RawMessage `json:"upstream_params,omitempty"`
+
+	SetUserAttributes  *string   `json:"set_user_root_attributes,omitempty"`
+	NonPersistentAttrs *[]string `json:"non_persistent_attrs,omitempty"`
+}
+
+// joinScopes enables or disables scopes among the current ones, and returns
+// them space separated, the way the client does for the strategies it
+// supports.
+func joinScopes(current []string, enable bool, scopes ...string) string {
+	scopeMap := make(map[string]bool)
+	for _, scope := range current {
+		scopeMap[scope] = true
+	}
+	for _, scope := range scopes {
+		scopeMap[scope] = enable
+	}
+	scopeSlice := make([]string, 0, len(scopeMap))
+	for scope, enabled := range scopeMap {
+		if enabled {
+			scopeSlice = append(scopeSlice, scope)
+		}
+	}
+	sort.Strings(scopeSlice)
+	return strings.Join(scopeSlice, " ")
+}
+
+// stringSliceValue returns the value of s, or nil if it is nil.
+func stringSliceValue(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
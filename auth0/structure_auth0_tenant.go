@@ -62,7 +62,7 @@ func flattenTenantUniversalLogin(universalLogin *management.TenantUniversalLogin
 }
 
 func expandTenantChangePassword(d ResourceData) (changePassword *management.TenantChangePassword) {
-	List(d, "change_password").Elem(func(d ResourceData) {
+	List(d, "change_password", IsNewResource(), HasChange()).Elem(func(d ResourceData) {
 		changePassword = &management.TenantChangePassword{
 			Enabled: Bool(d, "enabled"),
 			HTML:    String(d, "html"),
@@ -72,7 +72,7 @@ func expandTenantChangePassword(d ResourceData) (changePassword *management.Tena
 }
 
 func expandTenantGuardianMFAPage(d ResourceData) (mfa *management.TenantGuardianMFAPage) {
-	List(d, "guardian_mfa_page").Elem(func(d ResourceData) {
+	List(d, "guardian_mfa_page", IsNewResource(), HasChange()).Elem(func(d ResourceData) {
 		mfa = &management.TenantGuardianMFAPage{
 			Enabled: Bool(d, "enabled"),
 			HTML:    String(d, "html"),
@@ -82,7 +82,7 @@ func expandTenantGuardianMFAPage(d ResourceData) (mfa *management.TenantGuardian
 }
 
 func expandTenantErrorPage(d ResourceData) (errorPage *management.TenantErrorPage) {
-	List(d, "error_page").Elem(func(d ResourceData) {
+	List(d, "error_page", IsNewResource(), HasChange()).Elem(func(d ResourceData) {
 		errorPage = &management.TenantErrorPage{
 			HTML:        String(d, "html"),
 			ShowLogLink: Bool(d, "show_log_link"),
@@ -0,0 +1,3 @@
+function login(email, password, callback) {
+  return callback(new Error("Not implemented"));
+}
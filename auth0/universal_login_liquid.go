@@ -0,0 +1,103 @@
+package auth0
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/alexkappa/terraform-provider-auth0/auth0/internal/liquid"
+)
+
+// The tags of Universal Login page templates, which Auth0 replaces with the
+// head elements and the widget of the login pages. Both are required.
+const (
+	universalLoginHeadTag   = "auth0:head"
+	universalLoginWidgetTag = "auth0:widget"
+)
+
+// universalLoginPreviewTags is the content the custom tags are rendered as in
+// previews. The actual content is generated by Auth0 for each prompt, so
+// placeholders are rendered instead.
+var universalLoginPreviewTags = map[string]string{
+	universalLoginHeadTag:   "<!-- auth0:head -->",
+	universalLoginWidgetTag: "<!-- auth0:widget -->",
+}
+
+// parseUniversalLoginTemplate parses a Universal Login page template,
+// checking that it holds the required tags. Errors are prefixed with key, the
+// attribute holding the template.
+func parseUniversalLoginTemplate(key, src string) (*liquid.Template, error) {
+	t, err := liquid.ParseTags(src, universalLoginHeadTag, universalLoginWidgetTag)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	used := make(map[string]bool)
+	for _, tag := range t.Tags() {
+		used[tag.Name] = true
+	}
+	var errs *multierror.Error
+	for _, name := range []string{universalLoginHeadTag, universalLoginWidgetTag} {
+		if !used[name] {
+			errs = multierror.Append(errs, fmt.Errorf("%s: the template must contain the {%%- %s -%%} tag", key, name))
+		}
+	}
+	return t, errs.ErrorOrNil()
+}
+
+// universalLoginSampleData holds sample values of the variables available to
+// Universal Login page templates, for the given prompt and locale, to preview
+// them.
+func universalLoginSampleData(prompt, locale string) map[string]interface{} {
+	return map[string]interface{}{
+		"application": map[string]interface{}{
+			"id":       "AaiyAPdpYdesoKnqjj8HJqRn4T5titww",
+			"name":     "My App",
+			"logo_url": "https://example.com/app.png",
+			"metadata": map[string]interface{}{},
+		},
+		"branding": map[string]interface{}{
+			"logo_url": "https://example.com/logo.png",
+			"colors": map[string]interface{}{
+				"primary":         "#0059d6",
+				"page_background": "#000000",
+			},
+		},
+		"custom_domain": map[string]interface{}{
+			"domain": "login.example.com",
+		},
+		"locale": locale,
+		"organization": map[string]interface{}{
+			"id":           "org_Lv4xCzTMm3gMwFRl",
+			"name":         "acme",
+			"display_name": "Acme",
+			"metadata":     map[string]interface{}{},
+			"branding": map[string]interface{}{
+				"logo_url": "https://example.com/acme.png",
+				"colors": map[string]interface{}{
+					"primary":         "#0059d6",
+					"page_background": "#000000",
+				},
+			},
+		},
+		"prompt": map[string]interface{}{
+			"name": prompt,
+			"screen": map[string]interface{}{
+				"name":  prompt,
+				"texts": map[string]interface{}{},
+			},
+		},
+		"tenant": map[string]interface{}{
+			"friendly_name":   "Example",
+			"support_email":   "support@example.com",
+			"support_url":     "https://example.com/support",
+			"enabled_locales": []interface{}{locale},
+		},
+		"user": map[string]interface{}{
+			"user_id":  "auth0|5f7c8ec7c33c6c004bbafe82",
+			"email":    "jane.doe@example.com",
+			"username": "jane.doe",
+			"picture":  "https://example.com/jane.png",
+		},
+	}
+}
@@ -0,0 +1,209 @@
+package auth0
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestParseUniversalLoginTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "Valid",
+			src:  `<!DOCTYPE html><html><head>{%- auth0:head -%}</head><body>{%- auth0:widget -%}</body></html>`,
+		},
+		{
+			name: "WidgetPerPrompt",
+			src: `<html><head>{%- auth0:head -%}</head><body>
+{% if prompt.name == "login" %}<div class="login">{%- auth0:widget -%}</div>{% else %}{%- auth0:widget -%}{% endif %}
+</body></html>`,
+		},
+		{
+			name: "MissingTags",
+			src:  `<html><head></head><body>{{ prompt.name }}</body></html>`,
+			expected: []string{
+				"universal_login.0.body: the template must contain the {%- auth0:head -%} tag",
+				"universal_login.0.body: the template must contain the {%- auth0:widget -%} tag",
+			},
+		},
+		{
+			name:     "SyntaxError",
+			src:      "<html><head>{%- auth0:head -%}</head>\n<body>{%- auth0:widget -%}{% if locale %}</body></html>",
+			expected: []string{"universal_login.0.body: line 2: if tag was never closed"},
+		},
+		{
+			name:     "TagArguments",
+			src:      "<html><head>{%- auth0:head -%}</head><body>{%- auth0:widget compact -%}</body></html>",
+			expected: []string{"universal_login.0.body: line 1: auth0:widget tag takes no arguments"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUniversalLoginTemplate("universal_login.0.body", tt.src)
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q, got none", tt.expected)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error %q, got %s", expected, err)
+				}
+			}
+		})
+	}
+}
+
+const testUniversalLoginPage = `<!DOCTYPE html>
+{%- assign primary = organization.branding.colors.primary | default: branding.colors.primary %}
+{%- assign extra_locales = "de,fr" | split: "," %}
+{%- assign locales = tenant.enabled_locales | concat: extra_locales | uniq | sort_natural %}
+<html lang="{{ locale }}" dir="{{ dir | default: "ltr" }}">
+<head>
+  {%- auth0:head %}
+  <style>body { --primary: {{ primary }}; }</style>
+</head>
+<body>
+{%- case prompt.name %}
+{%- when "login", "login-id" %}
+  <h1>Welcome back to {{ application.name | escape }}</h1>
+{%- when "signup" %}
+  <h1>Join {{ organization.display_name | default: tenant.friendly_name }}</h1>
+{%- else %}
+  <h1>{{ tenant.friendly_name }}</h1>
+{%- endcase %}
+  {%- auth0:widget %}
+  <ul>
+  {%- for l in locales %}
+    <li class="{% cycle "odd", "even" %}{% if l == locale %} active{% endif %}">{{ l | upcase }}</li>
+  {%- endfor %}
+  </ul>
+  <footer>{{ tenant.support_email }} ({{ tenant.enabled_locales | size | at_least: 2 }} languages)</footer>
+</body>
+</html>
+`
+
+func TestRenderUniversalLoginTemplate(t *testing.T) {
+	tmpl, err := parseUniversalLoginTemplate("body", testUniversalLoginPage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		prompt    string
+		locale    string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			name:   "Login",
+			prompt: "login",
+			locale: "en",
+			expected: `<!DOCTYPE html>
+<html lang="en" dir="ltr">
+<head><!-- auth0:head -->
+  <style>body { --primary: #0059d6; }</style>
+</head>
+<body>
+  <h1>Welcome back to My App</h1><!-- auth0:widget -->
+  <ul>
+    <li class="odd">DE</li>
+    <li class="even active">EN</li>
+    <li class="odd">FR</li>
+  </ul>
+  <footer>support@example.com (2 languages)</footer>
+</body>
+</html>
+`,
+		},
+		{
+			name:   "Signup",
+			prompt: "signup",
+			locale: "fr",
+			variables: map[string]interface{}{
+				"organization": nil,
+				"branding":     map[string]interface{}{"colors": map[string]interface{}{"primary": "#ff0000"}},
+			},
+			expected: `<!DOCTYPE html>
+<html lang="fr" dir="ltr">
+<head><!-- auth0:head -->
+  <style>body { --primary: #ff0000; }</style>
+</head>
+<body>
+  <h1>Join Example</h1><!-- auth0:widget -->
+  <ul>
+    <li class="odd">DE</li>
+    <li class="even active">FR</li>
+  </ul>
+  <footer>support@example.com (2 languages)</footer>
+</body>
+</html>
+`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data := mergeLiquidTemplateData(universalLoginSampleData(tt.prompt, tt.locale), tt.variables)
+			actual, err := tmpl.RenderTags(data, universalLoginPreviewTags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestValidateBranding(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			name: "WithoutUniversalLogin",
+			config: map[string]interface{}{
+				"logo_url": "https://mycompany.org/logo.png",
+			},
+		},
+		{
+			name: "ValidUniversalLogin",
+			config: map[string]interface{}{
+				"universal_login": []interface{}{map[string]interface{}{
+					"body": "<html><head>{%- auth0:head -%}</head><body>{%- auth0:widget -%}</body></html>",
+				}},
+			},
+		},
+		{
+			name: "InvalidUniversalLogin",
+			config: map[string]interface{}{
+				"universal_login": []interface{}{map[string]interface{}{
+					"body": "<html><head>{%- auth0:head -%}</head><body></body></html>",
+				}},
+			},
+			expected: "universal_login.0.body: the template must contain the {%- auth0:widget -%} tag",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newBranding().Diff(nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeHCL writes the auth0_action and auth0_trigger_binding resources of the
+// migration, along with a sensitive variable for the value of each secret.
+func writeHCL(w io.Writer, m *migration) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "# Generated by migrate-actions from %d rules and %d hooks.\n", m.rules, m.hooks)
+	fmt.Fprintf(b, "# Review every TODO(migration) comment before applying.\n")
+
+	for _, a := range m.actions {
+		for _, secret := range a.secrets {
+			fmt.Fprintf(b, "\nvariable %s {\n", hclString(secretVariable(a, secret)))
+			fmt.Fprintf(b, "  description = %s\n", hclString(fmt.Sprintf("Value of the %s secret of the %s action", secret, a.name)))
+			fmt.Fprintf(b, "  type        = string\n")
+			fmt.Fprintf(b, "  sensitive   = true\n")
+			fmt.Fprintf(b, "}\n")
+		}
+	}
+
+	for _, a := range m.actions {
+		b.WriteString("\n")
+		for _, todo := range a.flags {
+			fmt.Fprintf(b, "# TODO(migration): %s\n", todo)
+		}
+		fmt.Fprintf(b, "resource \"auth0_action\" %s {\n", hclString(a.resourceName))
+		fmt.Fprintf(b, "  name    = %s\n", hclString(a.name))
+		fmt.Fprintf(b, "  runtime = \"node16\"\n")
+		fmt.Fprintf(b, "  deploy  = true\n")
+		fmt.Fprintf(b, "\n  supported_triggers {\n")
+		fmt.Fprintf(b, "    id      = %s\n", hclString(a.trigger))
+		fmt.Fprintf(b, "    version = %s\n", hclString(a.version))
+		fmt.Fprintf(b, "  }\n")
+
+		for _, name := range keys(a.dependencies) {
+			fmt.Fprintf(b, "\n  dependencies {\n")
+			fmt.Fprintf(b, "    name    = %s\n", hclString(name))
+			fmt.Fprintf(b, "    version = %s\n", hclString(a.dependencies[name]))
+			fmt.Fprintf(b, "  }\n")
+		}
+
+		for _, secret := range a.secrets {
+			fmt.Fprintf(b, "\n  secrets {\n")
+			fmt.Fprintf(b, "    name  = %s\n", hclString(secret))
+			fmt.Fprintf(b, "    value = var.%s\n", secretVariable(a, secret))
+			fmt.Fprintf(b, "  }\n")
+		}
+
+		marker := heredocMarker(a.code)
+		fmt.Fprintf(b, "\n  code = <<%s\n%s\n%s\n", marker, hclTemplateEscape(strings.TrimRight(a.code, "\n")), marker)
+		fmt.Fprintf(b, "}\n")
+	}
+
+	for _, bd := range m.bindings {
+		fmt.Fprintf(b, "\nresource \"auth0_trigger_binding\" %s {\n", hclString(strings.ReplaceAll(bd.trigger, "-", "_")))
+		fmt.Fprintf(b, "  trigger = %s\n", hclString(bd.trigger))
+		for _, a := range bd.actions {
+			fmt.Fprintf(b, "\n  actions {\n")
+			fmt.Fprintf(b, "    id           = auth0_action.%s.id\n", a.resourceName)
+			fmt.Fprintf(b, "    display_name = auth0_action.%s.name\n", a.resourceName)
+			fmt.Fprintf(b, "  }\n")
+		}
+		fmt.Fprintf(b, "}\n")
+	}
+
+	return b.Flush()
+}
+
+// secretVariable returns the name of the variable holding the value of a
+// secret of an action.
+func secretVariable(a *action, secret string) string {
+	return a.resourceName + "_" + strings.Trim(resourceNamePattern.ReplaceAllString(strings.ToLower(secret), "_"), "_")
+}
+
+// hclString quotes s as an HCL string literal. HCL only supports the \n, \r,
+// \t, \", \\ and \uNNNN escapes, so other control characters are escaped
+// with \uNNNN.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return hclTemplateEscape(b.String())
+}
+
+// hclTemplateEscape escapes the template sequences of s, so that it is taken
+// literally within an HCL string or heredoc.
+func hclTemplateEscape(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
+}
+
+// heredocMarker returns a heredoc delimiter which doesn't appear in s.
+func heredocMarker(s string) string {
+	marker := "EOT"
+	for strings.Contains(s, marker) {
+		marker += "_"
+	}
+	return marker
+}
@@ -0,0 +1,107 @@
+// Command migrate-actions helps migrating from rules and hooks to actions.
+//
+// It reads the auth0_rule and auth0_hook resources from a Terraform state file,
+// or the rules and hooks of an Auth0 tenant, and writes the equivalent
+// auth0_action and auth0_trigger_binding resources as HCL.
+//
+// The code of each rule and hook is wrapped in the export signature of the
+// matching action trigger, together with a small compatibility layer. Rule
+// configuration and hook secrets become action secrets, whose values are read
+// from variables. Anything which could not be translated automatically is
+// flagged with a TODO comment in the output, and reported on stderr.
+//
+// Usage:
+//
+//	go run ./cmd/migrate-actions -state terraform.tfstate > actions.tf
+//
+//	export AUTH0_DOMAIN=... AUTH0_CLIENT_ID=... AUTH0_CLIENT_SECRET=...
+//	go run ./cmd/migrate-actions -tenant > actions.tf
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"gopkg.in/auth0.v5/management"
+)
+
+var args = struct {
+	state  string
+	tenant bool
+	out    string
+}{}
+
+func init() {
+	flag.StringVar(&args.state, "state", "", "Path to a Terraform state file to read auth0_rule and auth0_hook resources from")
+	flag.BoolVar(&args.tenant, "tenant", false, "Read rules and hooks from the tenant configured by the AUTH0_DOMAIN, AUTH0_CLIENT_ID and AUTH0_CLIENT_SECRET, or AUTH0_API_TOKEN, environment variables")
+	flag.StringVar(&args.out, "out", "", "Path of the file to write to, defaults to stdout")
+}
+
+func main() {
+	flag.Parse()
+	log.SetFlags(0)
+
+	if (args.state == "") == !args.tenant {
+		log.Fatal("exactly one of -state or -tenant must be given")
+	}
+
+	var rules []rule
+	var hooks []hook
+	var err error
+
+	if args.state != "" {
+		var f *os.File
+		if f, err = os.Open(args.state); err != nil {
+			log.Fatal(err)
+		}
+		rules, hooks, err = readState(f)
+		f.Close()
+	} else {
+		var api *management.Management
+		if api, err = newManagement(); err != nil {
+			log.Fatal(err)
+		}
+		rules, hooks, err = readTenant(api)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m := migrate(rules, hooks)
+
+	var w io.Writer = os.Stdout
+	if args.out != "" {
+		f, err := os.Create(args.out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := writeHCL(w, m); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, a := range m.actions {
+		for _, todo := range a.flags {
+			log.Printf("[TODO] %s: %s", a.resourceName, todo)
+		}
+	}
+	log.Printf("Migrated %d rules and %d hooks to %d actions", len(rules), len(hooks), len(m.actions))
+}
+
+func newManagement() (*management.Management, error) {
+	domain := os.Getenv("AUTH0_DOMAIN")
+	if domain == "" {
+		return nil, fmt.Errorf("AUTH0_DOMAIN must be set")
+	}
+	auth := management.WithStaticToken(os.Getenv("AUTH0_API_TOKEN"))
+	if os.Getenv("AUTH0_API_TOKEN") == "" {
+		auth = management.WithClientCredentials(os.Getenv("AUTH0_CLIENT_ID"), os.Getenv("AUTH0_CLIENT_SECRET"))
+	}
+	return management.New(domain, auth)
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// action is an auth0_action resource to be written.
+type action struct {
+	resourceName string
+	name         string
+	trigger      string
+	version      string
+	code         string
+	secrets      []string
+	dependencies map[string]string
+	bound        bool
+	flags        []string
+}
+
+// binding is an auth0_trigger_binding resource to be written.
+type binding struct {
+	trigger string
+	actions []*action
+}
+
+// migration is the result of migrating a set of rules and hooks.
+type migration struct {
+	rules   int
+	hooks   int
+	actions []*action
+	// bindings are sorted by trigger, and their actions by execution order.
+	bindings []*binding
+}
+
+// hookTriggers maps the trigger of a hook to the equivalent action trigger.
+var hookTriggers = map[string]struct {
+	version string
+	handler string
+}{
+	"credentials-exchange":   {"v2", hookCredentialsExchangeHandler},
+	"pre-user-registration":  {"v2", hookPreUserRegistrationHandler},
+	"post-user-registration": {"v2", hookPostUserRegistrationHandler},
+	"post-change-password":   {"v2", hookPostChangePasswordHandler},
+	"send-phone-message":     {"v2", hookSendPhoneMessageHandler},
+}
+
+// checks are patterns of rule and hook code which can't be translated
+// automatically, along with what to do about them.
+var (
+	ruleChecks = []check{
+		{regexp.MustCompile(`context\.(idToken|accessToken)\b`), "sets token claims through context.idToken or context.accessToken, use api.idToken.setCustomClaim() and api.accessToken.setCustomClaim() instead"},
+		{regexp.MustCompile(`context\.redirect\b`), "redirects through context.redirect, use api.redirect.sendUserTo() and exports.onContinuePostLogin instead"},
+		{regexp.MustCompile(`context\.multifactor\b`), "requires MFA through context.multifactor, use api.multifactor.enable() instead"},
+		{regexp.MustCompile(`context\.samlConfiguration\b`), "configures SAML through context.samlConfiguration, use api.samlResponse instead"},
+		{regexp.MustCompile(`\bauth0\.(users|accessToken|domain)\b`), "uses the auth0 object, which is not available in actions, use api.user.setAppMetadata() and api.user.setUserMetadata() or the auth0 npm package instead"},
+		{regexp.MustCompile(`\bUnauthorizedError\b`), "uses UnauthorizedError, which is not available in actions, errors passed to the callback deny access through api.access.deny()"},
+		{regexp.MustCompile(`\bglobal\.`), "uses global, which is not shared between executions of an action, use api.cache instead"},
+		{regexp.MustCompile(`user\.(app_metadata|user_metadata)[\w.\[\]'"]*\s*=[^=]`), "modifies the user profile, which is not persisted in actions, use api.user.setAppMetadata() and api.user.setUserMetadata() instead"},
+	}
+	hookChecks = []check{
+		{regexp.MustCompile(`context\.webtask\.storage\b`), "uses context.webtask.storage, which is not available in actions"},
+	}
+)
+
+type check struct {
+	pattern *regexp.Regexp
+	flag    string
+}
+
+var (
+	configurationPattern = regexp.MustCompile(`configuration(?:\.([A-Za-z_$][\w$]*)|\[\s*['"]([^'"]+)['"]\s*\])`)
+	requirePattern       = regexp.MustCompile(`require\(\s*['"]([^'"]+)['"]\s*\)`)
+	resourceNamePattern  = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// builtinModules are the node modules which are available in actions without
+// declaring a dependency.
+var builtinModules = map[string]bool{
+	"assert": true, "buffer": true, "child_process": true, "crypto": true, "dns": true,
+	"events": true, "fs": true, "http": true, "https": true, "net": true, "os": true,
+	"path": true, "querystring": true, "stream": true, "string_decoder": true,
+	"timers": true, "tls": true, "url": true, "util": true, "zlib": true,
+}
+
+// migrate translates rules and hooks to actions. Rules are bound to the
+// post-login trigger in the order given by rule.order, and hooks to the
+// trigger matching their trigger_id. Disabled rules and hooks are translated
+// but not bound.
+func migrate(rules []rule, hooks []hook) *migration {
+	m := &migration{rules: len(rules), hooks: len(hooks)}
+	names := make(map[string]bool)
+	bindings := make(map[string]*binding)
+
+	bind := func(a *action) {
+		if !a.bound {
+			return
+		}
+		b, ok := bindings[a.trigger]
+		if !ok {
+			b = &binding{trigger: a.trigger}
+			bindings[a.trigger] = b
+		}
+		b.actions = append(b.actions, a)
+	}
+
+	rules = append([]rule(nil), rules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].order < rules[j].order
+	})
+	for _, r := range rules {
+		a := migrateRule(r)
+		a.resourceName = uniqueResourceName(names, "rule_"+r.name)
+		m.actions = append(m.actions, a)
+		bind(a)
+	}
+
+	hooks = append([]hook(nil), hooks...)
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].name < hooks[j].name
+	})
+	for _, h := range hooks {
+		a := migrateHook(h)
+		a.resourceName = uniqueResourceName(names, "hook_"+h.name)
+		m.actions = append(m.actions, a)
+		bind(a)
+	}
+
+	for _, b := range bindings {
+		m.bindings = append(m.bindings, b)
+	}
+	sort.Slice(m.bindings, func(i, j int) bool {
+		return m.bindings[i].trigger < m.bindings[j].trigger
+	})
+	return m
+}
+
+func migrateRule(r rule) *action {
+	a := &action{
+		name:         r.name,
+		trigger:      "post-login",
+		version:      "v3",
+		code:         fmt.Sprintf(ruleHandler, r.name, indent(strings.TrimSpace(r.script), "    ")),
+		dependencies: make(map[string]string),
+		bound:        r.enabled,
+	}
+
+	seen := make(map[string]bool)
+	for _, match := range configurationPattern.FindAllStringSubmatch(r.script, -1) {
+		name := match[1] + match[2]
+		if !seen[name] {
+			seen[name] = true
+			a.secrets = append(a.secrets, name)
+		}
+	}
+	sort.Strings(a.secrets)
+
+	a.check(r.script, ruleChecks)
+	a.requires(r.script)
+	if !r.enabled {
+		a.flags = append(a.flags, "the rule is disabled, so the action is not bound to the post-login trigger")
+	}
+	return a
+}
+
+func migrateHook(h hook) *action {
+	a := &action{
+		name:         h.name,
+		trigger:      h.triggerID,
+		secrets:      append([]string(nil), h.secrets...),
+		dependencies: make(map[string]string),
+		bound:        h.enabled,
+	}
+	for name, version := range h.dependencies {
+		a.dependencies[name] = version
+	}
+
+	trigger, ok := hookTriggers[h.triggerID]
+	if !ok {
+		a.trigger = "post-login"
+		a.version = "v3"
+		a.bound = false
+		a.code = fmt.Sprintf(hookModule, h.name, indent(strings.TrimSpace(h.script), "  "))
+		a.flags = append(a.flags, fmt.Sprintf("hooks with trigger %q have no equivalent action trigger, the code must be migrated manually", h.triggerID))
+		return a
+	}
+
+	a.version = trigger.version
+	a.code = fmt.Sprintf(hookModule, h.name, indent(strings.TrimSpace(h.script), "  ")) + "\n" + trigger.handler
+
+	a.check(h.script, hookChecks)
+	a.requires(h.script)
+	if h.triggerID == "credentials-exchange" && strings.Contains(h.script, "scope") {
+		a.flags = append(a.flags, "changes to the scope of the access token can't be made from an action, only custom claims are kept")
+	}
+	if !h.enabled {
+		a.flags = append(a.flags, fmt.Sprintf("the hook is disabled, so the action is not bound to the %s trigger", a.trigger))
+	}
+	return a
+}
+
+func (a *action) check(script string, checks []check) {
+	for _, c := range checks {
+		if c.pattern.MatchString(script) {
+			a.flags = append(a.flags, c.flag)
+		}
+	}
+}
+
+// requires adds the modules required by script as dependencies. Modules may
+// be pinned to a version the way webtasks allowed, as in require('lodash@4.17.21').
+func (a *action) requires(script string) {
+	for _, match := range requirePattern.FindAllStringSubmatch(script, -1) {
+		module := match[1]
+		if builtinModules[module] || strings.HasPrefix(module, ".") {
+			continue
+		}
+
+		name, version := module, ""
+		if i := strings.LastIndex(module, "@"); i > 0 {
+			name, version = module[:i], module[i+1:]
+		}
+		if _, ok := a.dependencies[name]; ok {
+			continue
+		}
+		if version == "" {
+			version = "latest"
+			a.flags = append(a.flags, fmt.Sprintf("requires %q without a version, pin the version of the dependency", name))
+		}
+		if name != module {
+			a.flags = append(a.flags, fmt.Sprintf("requires %q, the version must be removed from the require call", module))
+		}
+		a.dependencies[name] = version
+	}
+}
+
+// uniqueResourceName turns name into a valid Terraform resource name which is
+// not yet part of names.
+func uniqueResourceName(names map[string]bool, name string) string {
+	base := strings.Trim(resourceNamePattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	n := base
+	for i := 2; names[n]; i++ {
+		n = fmt.Sprintf("%s_%d", base, i)
+	}
+	names[n] = true
+	return n
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ruleHandler runs a rule from a post-login action. The rule configuration is
+// read from the action secrets, and errors passed to the callback deny access.
+const ruleHandler = `/**
+ * Migrated from the rule %q.
+ */
+exports.onExecutePostLogin = async (event, api) => {
+  const configuration = event.secrets;
+  const context = {
+    clientID: event.client.client_id,
+    clientName: event.client.name,
+    clientMetadata: event.client.metadata || {},
+    connection: event.connection.name,
+    connectionStrategy: event.connection.strategy,
+    protocol: event.transaction && event.transaction.protocol,
+    request: event.request,
+    stats: event.stats,
+    authorization: event.authorization,
+    tenant: event.tenant.id,
+  };
+
+  const rule =
+%s;
+
+  try {
+    await new Promise((resolve, reject) => {
+      rule(event.user, context, (err) => (err ? reject(err) : resolve()));
+    });
+  } catch (err) {
+    api.access.deny(err.message);
+  }
+};
+`
+
+// hookModule evaluates the code of a hook, which assigns module.exports, and
+// keeps the exported function as hook.
+const hookModule = `/**
+ * Migrated from the hook %q.
+ */
+const hook = (() => {
+  const module = { exports: {} };
+%s
+  return module.exports;
+})();
+`
+
+const hookCredentialsExchangeHandler = `exports.onExecuteCredentialsExchange = async (event, api) => {
+  const client = {
+    id: event.client.client_id,
+    name: event.client.name,
+    metadata: event.client.metadata,
+  };
+  const context = { webtask: { secrets: event.secrets } };
+
+  let accessToken;
+  try {
+    accessToken = await new Promise((resolve, reject) => {
+      hook(client, event.accessToken.scope, event.resource_server.identifier, context,
+        (err, token) => (err ? reject(err) : resolve(token)));
+    });
+  } catch (err) {
+    api.access.deny("invalid_request", err.message);
+    return;
+  }
+
+  for (const [claim, value] of Object.entries(accessToken || {})) {
+    if (claim !== "scope") {
+      api.accessToken.setCustomClaim(claim, value);
+    }
+  }
+};
+`
+
+const hookPreUserRegistrationHandler = `exports.onExecutePreUserRegistration = async (event, api) => {
+  const context = {
+    connection: event.connection,
+    clientMetadata: event.client ? event.client.metadata : {},
+    request: event.request,
+    webtask: { secrets: event.secrets },
+  };
+
+  let response;
+  try {
+    response = await new Promise((resolve, reject) => {
+      hook(event.user, context, (err, response) => (err ? reject(err) : resolve(response)));
+    });
+  } catch (err) {
+    api.access.deny(err.message, err.message);
+    return;
+  }
+
+  const user = (response && response.user) || {};
+  for (const [key, value] of Object.entries(user.user_metadata || {})) {
+    api.user.setUserMetadata(key, value);
+  }
+  for (const [key, value] of Object.entries(user.app_metadata || {})) {
+    api.user.setAppMetadata(key, value);
+  }
+};
+`
+
+const hookPostUserRegistrationHandler = `exports.onExecutePostUserRegistration = async (event, api) => {
+  const context = {
+    connection: event.connection,
+    request: event.request,
+    webtask: { secrets: event.secrets },
+  };
+
+  await new Promise((resolve, reject) => {
+    hook(event.user, context, (err) => (err ? reject(err) : resolve()));
+  });
+};
+`
+
+const hookPostChangePasswordHandler = `exports.onExecutePostChangePassword = async (event, api) => {
+  const context = {
+    connection: event.connection,
+    request: event.request,
+    webtask: { secrets: event.secrets },
+  };
+
+  await new Promise((resolve, reject) => {
+    hook(event.user, context, (err) => (err ? reject(err) : resolve()));
+  });
+};
+`
+
+const hookSendPhoneMessageHandler = `exports.onExecuteSendPhoneMessage = async (event, api) => {
+  const context = {
+    ...event.message_options,
+    client: event.client,
+    user: event.user,
+    webtask: { secrets: event.secrets },
+  };
+
+  await new Promise((resolve, reject) => {
+    hook(event.message_options.recipient, event.message_options.text, context,
+      (err) => (err ? reject(err) : resolve()));
+  });
+};
+`
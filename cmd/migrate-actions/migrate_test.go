@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadState(t *testing.T) {
+	s := `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "auth0_rule",
+      "name": "first",
+      "instances": [{"attributes": {"id": "rul_1", "name": "first", "script": "function (user, context, callback) {}", "order": 2, "enabled": true}}]
+    },
+    {
+      "mode": "managed",
+      "type": "auth0_hook",
+      "name": "pre",
+      "instances": [{"attributes": {"id": "01", "name": "pre", "script": "module.exports = function () {}", "trigger_id": "pre-user-registration", "enabled": true, "secrets": {"b": "2", "a": "1"}, "dependencies": {"auth0": "2.35.0"}}}]
+    },
+    {
+      "mode": "data",
+      "type": "auth0_rule",
+      "name": "ignored",
+      "instances": [{"attributes": {"name": "ignored"}}]
+    }
+  ]
+}`
+	rules, hooks, err := readState(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRules := []rule{{"first", "function (user, context, callback) {}", 2, true}}
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Errorf("expected rules %v, got %v", expectedRules, rules)
+	}
+
+	expectedHooks := []hook{{"pre", "module.exports = function () {}", "pre-user-registration", true, []string{"a", "b"}, map[string]string{"auth0": "2.35.0"}}}
+	if !reflect.DeepEqual(hooks, expectedHooks) {
+		t.Errorf("expected hooks %v, got %v", expectedHooks, hooks)
+	}
+
+	if _, _, err := readState(strings.NewReader(`{"version": 3}`)); err == nil {
+		t.Error("expected an error for an unsupported state version")
+	}
+}
+
+func TestMigrateRules(t *testing.T) {
+	m := migrate([]rule{
+		{"Second Rule", "function (user, context, callback) {\n  context.idToken['https://example.com/x'] = 1;\n  callback(null, user, context);\n}", 20, true},
+		{"First Rule", "function (user, context, callback) {\n  const request = require('request@2.88.0');\n  request.get(configuration.API_URL + configuration['API-KEY'] + configuration.API_URL);\n  callback(null, user, context);\n}", 10, true},
+		{"Disabled", "function (user, context, callback) { callback(null, user, context); }", 5, false},
+	}, nil)
+
+	if len(m.actions) != 3 {
+		t.Fatalf("expected 3 actions, got %d", len(m.actions))
+	}
+	var names []string
+	for _, a := range m.actions {
+		names = append(names, a.resourceName)
+	}
+	if expected := []string{"rule_disabled", "rule_first_rule", "rule_second_rule"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected actions %v, got %v", expected, names)
+	}
+
+	if len(m.bindings) != 1 || m.bindings[0].trigger != "post-login" {
+		t.Fatalf("expected a single post-login binding, got %v", m.bindings)
+	}
+	var bound []string
+	for _, a := range m.bindings[0].actions {
+		bound = append(bound, a.resourceName)
+	}
+	if expected := []string{"rule_first_rule", "rule_second_rule"}; !reflect.DeepEqual(bound, expected) {
+		t.Errorf("expected bound actions %v, got %v", expected, bound)
+	}
+
+	disabled, first, second := m.actions[0], m.actions[1], m.actions[2]
+
+	if len(disabled.flags) != 1 || !strings.Contains(disabled.flags[0], "disabled") {
+		t.Errorf("expected the disabled rule to be flagged, got %v", disabled.flags)
+	}
+
+	if expected := []string{"API-KEY", "API_URL"}; !reflect.DeepEqual(first.secrets, expected) {
+		t.Errorf("expected secrets %v, got %v", expected, first.secrets)
+	}
+	if expected := map[string]string{"request": "2.88.0"}; !reflect.DeepEqual(first.dependencies, expected) {
+		t.Errorf("expected dependencies %v, got %v", expected, first.dependencies)
+	}
+	if !strings.Contains(first.code, "exports.onExecutePostLogin") || first.trigger != "post-login" || first.version != "v3" {
+		t.Errorf("expected a post-login v3 action, got %s %s:\n%s", first.trigger, first.version, first.code)
+	}
+
+	if len(second.flags) != 1 || !strings.Contains(second.flags[0], "api.idToken.setCustomClaim") {
+		t.Errorf("expected the use of context.idToken to be flagged, got %v", second.flags)
+	}
+}
+
+func TestMigrateHooks(t *testing.T) {
+	m := migrate(nil, []hook{
+		{"exchange", "module.exports = function (client, scope, audience, context, cb) { cb(null, {}); };", "credentials-exchange", true, []string{"KEY"}, map[string]string{"axios": "0.21.1"}},
+		{"register", "module.exports = function (user, context, cb) { cb(null, { user }); };", "pre-user-registration", false, nil, nil},
+		{"unknown", "module.exports = function () {};", "unknown-trigger", true, nil, nil},
+	})
+
+	if len(m.actions) != 3 {
+		t.Fatalf("expected 3 actions, got %d", len(m.actions))
+	}
+	exchange, register, unknown := m.actions[0], m.actions[1], m.actions[2]
+
+	if exchange.trigger != "credentials-exchange" || exchange.version != "v2" || !exchange.bound {
+		t.Errorf("expected a bound credentials-exchange v2 action, got %s %s", exchange.trigger, exchange.version)
+	}
+	if !strings.Contains(exchange.code, "exports.onExecuteCredentialsExchange") {
+		t.Errorf("expected the credentials-exchange handler, got:\n%s", exchange.code)
+	}
+	if !reflect.DeepEqual(exchange.secrets, []string{"KEY"}) || exchange.dependencies["axios"] != "0.21.1" {
+		t.Errorf("expected the hook secrets and dependencies to be kept, got %v %v", exchange.secrets, exchange.dependencies)
+	}
+
+	if register.bound || len(register.flags) != 1 {
+		t.Errorf("expected the disabled hook to be unbound and flagged, got %v", register.flags)
+	}
+
+	if unknown.bound || len(unknown.flags) != 1 || !strings.Contains(unknown.flags[0], "unknown-trigger") {
+		t.Errorf("expected the hook with an unknown trigger to be unbound and flagged, got %v", unknown.flags)
+	}
+
+	if len(m.bindings) != 1 || m.bindings[0].trigger != "credentials-exchange" {
+		t.Errorf("expected a single credentials-exchange binding, got %v", m.bindings)
+	}
+}
+
+func TestWriteHCL(t *testing.T) {
+	m := migrate([]rule{
+		{"templated", "function (user, context, callback) {\n  const s = `${configuration.TOKEN} %{x}`;\n  callback(null, user, context);\n}\nEOT", 1, true},
+	}, nil)
+
+	var b bytes.Buffer
+	if err := writeHCL(&b, m); err != nil {
+		t.Fatal(err)
+	}
+	hcl := b.String()
+
+	for _, expected := range []string{
+		`variable "rule_templated_token" {`,
+		`resource "auth0_action" "rule_templated" {`,
+		`value = var.rule_templated_token`,
+		"const s = `$${configuration.TOKEN} %%{x}`;",
+		"code = <<EOT_\n",
+		"\nEOT_\n",
+		`resource "auth0_trigger_binding" "post_login" {`,
+		`id           = auth0_action.rule_templated.id`,
+	} {
+		if !strings.Contains(hcl, expected) {
+			t.Errorf("expected the output to contain %q, got:\n%s", expected, hcl)
+		}
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "Plain", value: "Welcome email", expected: `"Welcome email"`},
+		{name: "Escapes", value: "a \"quoted\"\\path\n\r\t", expected: `"a \"quoted\"\\path\n\r\t"`},
+		{name: "ControlCharacters", value: "\x01\a\v\f\x7f", expected: `"\u0001\u0007\u000b\u000c\u007f"`},
+		{name: "Unicode", value: "héllo ✓", expected: `"héllo ✓"`},
+		{name: "Templates", value: "${var} %{if}", expected: `"$${var} %%{if}"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := hclString(tt.value); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"gopkg.in/auth0.v5/management"
+)
+
+// rule is a rule to migrate, read from a state file or a tenant.
+type rule struct {
+	name    string
+	script  string
+	order   int
+	enabled bool
+}
+
+// hook is a hook to migrate, read from a state file or a tenant.
+type hook struct {
+	name         string
+	script       string
+	triggerID    string
+	enabled      bool
+	secrets      []string
+	dependencies map[string]string
+}
+
+// state models the parts of a Terraform state file which are needed to read
+// the rules and hooks.
+type state struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Instances []struct {
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+type stateRule struct {
+	Name    string `json:"name"`
+	Script  string `json:"script"`
+	Order   int    `json:"order"`
+	Enabled bool   `json:"enabled"`
+}
+
+type stateHook struct {
+	Name         string            `json:"name"`
+	Script       string            `json:"script"`
+	TriggerID    string            `json:"trigger_id"`
+	Enabled      bool              `json:"enabled"`
+	Secrets      map[string]string `json:"secrets"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// readState reads the auth0_rule and auth0_hook resources of a Terraform state
+// file. Only the version 4 format, used since Terraform 0.12, is supported.
+func readState(r io.Reader) ([]rule, []hook, error) {
+	var s state
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, nil, fmt.Errorf("failed to read the state: %w", err)
+	}
+	if s.Version != 4 {
+		return nil, nil, fmt.Errorf("unsupported state version %d, expected 4", s.Version)
+	}
+
+	var rules []rule
+	var hooks []hook
+	for _, res := range s.Resources {
+		if res.Mode != "managed" {
+			continue
+		}
+		for _, instance := range res.Instances {
+			switch res.Type {
+			case "auth0_rule":
+				var r stateRule
+				if err := json.Unmarshal(instance.Attributes, &r); err != nil {
+					return nil, nil, err
+				}
+				rules = append(rules, rule{r.Name, r.Script, r.Order, r.Enabled})
+			case "auth0_hook":
+				var h stateHook
+				if err := json.Unmarshal(instance.Attributes, &h); err != nil {
+					return nil, nil, err
+				}
+				hooks = append(hooks, hook{h.Name, h.Script, h.TriggerID, h.Enabled, keys(h.Secrets), h.Dependencies})
+			}
+		}
+	}
+	return rules, hooks, nil
+}
+
+// readTenant reads every rule and hook of the tenant. The values of hook
+// secrets can't be read, so only their names are kept.
+func readTenant(api *management.Management) ([]rule, []hook, error) {
+	var rules []rule
+	for page := 0; ; page++ {
+		l, err := api.Rule.List(management.Page(page))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range l.Rules {
+			rules = append(rules, rule{r.GetName(), r.GetScript(), r.GetOrder(), r.GetEnabled()})
+		}
+		if !l.HasNext() {
+			break
+		}
+	}
+
+	var hooks []hook
+	for page := 0; ; page++ {
+		l, err := api.Hook.List(management.Page(page))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, h := range l.Hooks {
+			secrets, err := api.Hook.Secrets(h.GetID())
+			if err != nil {
+				return nil, nil, err
+			}
+			dependencies := make(map[string]string)
+			if h.Dependencies != nil {
+				for name, version := range *h.Dependencies {
+					dependencies[name] = fmt.Sprint(version)
+				}
+			}
+			hooks = append(hooks, hook{h.GetName(), h.GetScript(), h.GetTriggerID(), h.GetEnabled(), keys(secrets), dependencies})
+		}
+		if !l.HasNext() {
+			break
+		}
+	}
+
+	return rules, hooks, nil
+}
+
+func keys(m map[string]string) []string {
+	k := make([]string, 0, len(m))
+	for key := range m {
+		k = append(k, key)
+	}
+	sort.Strings(k)
+	return k
+}
@@ -5,7 +5,7 @@ provider "auth0" {
 resource "auth0_connection" "my_connection" {
   name = "Example-Connection"
   strategy = "auth0"
-  options {
+  auth0_options {
     password_policy = "excellent"
     password_history {
       enable = true
@@ -20,6 +20,11 @@ resource "auth0_connection" "my_connection" {
     brute_force_protection = true
     enabled_database_customization = true
     custom_scripts = {
+      login = <<EOF
+function login (email, password, callback) {
+  return callback(new Error("Whoops!"))
+}
+EOF
       get_user = <<EOF
 function getByEmail (email, callback) {
   return callback(new Error("Whoops!"))